@@ -13,22 +13,11 @@ type CAService struct {
 
 func (s *CAService) Create(objectDN string, className string, driverName string,
 	extraProperties map[string]string) (*ConfigObject, error) {
-	me, err := s.client.Identity.Self()
+	allProps, err := s.properties(driverName, extraProperties)
 	if err != nil {
 		return nil, err
 	}
 
-	allProps := map[string]string{
-		"Contact":     me.PrefixedUniversal,
-		"Driver Name": driverName,
-	}
-
-	if extraProperties != nil {
-		for k, v := range extraProperties {
-			allProps[k] = v
-		}
-	}
-
 	obj, err := s.client.Config.Create(objectDN, className, allProps)
 	if err != nil {
 		return nil, err
@@ -68,7 +57,7 @@ func (s *CAService) CreateOpenSSL(objectDN string, hostname string, sshKeyDN str
 		extraProps["Copy Extensions"] = "copy"
 	}
 
-	obj, err := s.Create(objectDN, config.ClassOpenSSLCA, config.DriverOpenSSL, extraProps)
+	allProps, err := s.properties(config.DriverOpenSSL, extraProps)
 	if err != nil {
 		return nil, err
 	}
@@ -77,8 +66,18 @@ func (s *CAService) CreateOpenSSL(objectDN string, hostname string, sshKeyDN str
 	for i := 1; i <= maxValidityYears; i++ {
 		periods = append(periods, strconv.Itoa(i))
 	}
-	err = s.client.Config.Write(objectDN, map[string][]string{
-		"Validity Period": periods,
+
+	// Create and Write run as a batch so a failed Write does not leave a
+	// half-configured CA behind.
+	var obj *ConfigObject
+	err = RunConfigBatch(s.client.Config, func(b *ConfigBatch) error {
+		var err error
+		if obj, err = b.Create(objectDN, config.ClassOpenSSLCA, allProps); err != nil {
+			return err
+		}
+		return b.Write(objectDN, map[string][]string{
+			"Validity Period": periods,
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -90,3 +89,25 @@ func (s *CAService) CreateOpenSSL(objectDN string, hostname string, sshKeyDN str
 func (s *CAService) Delete(objectDN string, recursive bool) error {
 	return s.client.Config.Delete(objectDN, recursive)
 }
+
+// properties returns the attributes every CA template is created with, merged
+// with extraProperties.
+func (s *CAService) properties(driverName string, extraProperties map[string]string) (map[string]string, error) {
+	me, err := s.client.Identity.Self()
+	if err != nil {
+		return nil, err
+	}
+
+	allProps := map[string]string{
+		"Contact":     me.PrefixedUniversal,
+		"Driver Name": driverName,
+	}
+
+	if extraProperties != nil {
+		for k, v := range extraProperties {
+			allProps[k] = v
+		}
+	}
+
+	return allProps, nil
+}
@@ -0,0 +1,140 @@
+package venafi
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lookupCache is an LRU cache of DN to GUID mappings, keyed both ways. Entries
+// expire after a fixed TTL.
+type lookupCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	byDN    map[string]*list.Element
+	byGUID  map[string]*list.Element
+	nowFunc func() time.Time
+}
+
+type lookupEntry struct {
+	dn       string
+	guid     string
+	class    string
+	revision int64
+	expires  time.Time
+}
+
+func newLookupCache(size int, ttl time.Duration) *lookupCache {
+	return &lookupCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		byDN:    make(map[string]*list.Element),
+		byGUID:  make(map[string]*list.Element),
+		nowFunc: time.Now,
+	}
+}
+
+// EnableLookupCache turns on caching of DN and GUID lookups made through
+// ConfigService. At most size entries are kept, each for at most ttl; a ttl
+// of zero or less keeps entries until they are evicted or their object is
+// deleted. Calling it again replaces the cache; a size of zero turns caching
+// off.
+func (c *Client) EnableLookupCache(size int, ttl time.Duration) {
+	if size <= 0 {
+		c.cache = nil
+		return
+	}
+	c.cache = newLookupCache(size, ttl)
+}
+
+// ClearLookupCache discards every cached lookup.
+func (c *Client) ClearLookupCache() {
+	if c.cache != nil {
+		c.cache.clear()
+	}
+}
+
+func (lc *lookupCache) getByDN(dn string) (*lookupEntry, bool) {
+	return lc.get(lc.byDN, strings.ToLower(dn))
+}
+
+func (lc *lookupCache) getByGUID(guid string) (*lookupEntry, bool) {
+	return lc.get(lc.byGUID, strings.ToLower(guid))
+}
+
+func (lc *lookupCache) get(index map[string]*list.Element, key string) (*lookupEntry, bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	el, ok := index[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lookupEntry)
+	if lc.ttl > 0 && lc.nowFunc().After(entry.expires) {
+		lc.remove(el)
+		return nil, false
+	}
+	lc.order.MoveToFront(el)
+	e := *entry
+	return &e, true
+}
+
+func (lc *lookupCache) put(entry lookupEntry) {
+	if entry.dn == "" || entry.guid == "" {
+		return
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if el, ok := lc.byDN[strings.ToLower(entry.dn)]; ok {
+		lc.remove(el)
+	}
+	if el, ok := lc.byGUID[strings.ToLower(entry.guid)]; ok {
+		lc.remove(el)
+	}
+
+	entry.expires = lc.nowFunc().Add(lc.ttl)
+	el := lc.order.PushFront(&entry)
+	lc.byDN[strings.ToLower(entry.dn)] = el
+	lc.byGUID[strings.ToLower(entry.guid)] = el
+
+	for lc.order.Len() > lc.size {
+		lc.remove(lc.order.Back())
+	}
+}
+
+// invalidateTree drops dn and every cached object below it.
+func (lc *lookupCache) invalidateTree(dn string) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	key := strings.ToLower(dn)
+	for k, el := range lc.byDN {
+		if k == key || strings.HasPrefix(k, key+`\`) {
+			lc.remove(el)
+		}
+	}
+}
+
+func (lc *lookupCache) clear() {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.order.Init()
+	lc.byDN = make(map[string]*list.Element)
+	lc.byGUID = make(map[string]*list.Element)
+}
+
+// remove deletes el from the cache. The caller must hold lc.mu.
+func (lc *lookupCache) remove(el *list.Element) {
+	entry := el.Value.(*lookupEntry)
+	lc.order.Remove(el)
+	delete(lc.byDN, strings.ToLower(entry.dn))
+	delete(lc.byGUID, strings.ToLower(entry.guid))
+}
@@ -0,0 +1,83 @@
+package venafi_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupCache(t *testing.T) {
+	v, srv, transport := newCountingClient(t)
+	web := srv.AddObject(`\VED\Policy\Web`, "Policy", nil)
+	srv.AddObject(`\VED\Policy\Web\web01`, "X509 Certificate", nil)
+	v.EnableLookupCache(10, 0)
+
+	for i := 0; i < 3; i++ {
+		guid, err := v.Config.DnToGuid(`\VED\Policy\Web`)
+		if err != nil || !strings.EqualFold(guid, web.GUID) {
+			t.Fatalf("DnToGuid = %q, %v, want %q", guid, err, web.GUID)
+		}
+	}
+	if n := transport.count("/vedsdk/Config/DnToGuid"); n != 1 {
+		t.Errorf("DnToGuid made %d requests, want 1", n)
+	}
+
+	// The reverse lookup is answered from the same entry.
+	if dn, err := v.Config.GuidToDn(web.GUID); err != nil || dn != `\VED\Policy\Web` {
+		t.Errorf("GuidToDn = %q, %v", dn, err)
+	}
+	if n := transport.count("/vedsdk/Config/GuidToDn"); n != 0 {
+		t.Errorf("GuidToDn made %d requests, want 0", n)
+	}
+
+	// Deleting an ancestor drops everything below it.
+	if _, err := v.Config.DnToGuid(`\VED\Policy\Web\web01`); err != nil {
+		t.Fatalf("DnToGuid: %v", err)
+	}
+	if err := v.Config.Delete(`\VED\Policy\Web`, true); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := v.Config.DnToGuid(`\VED\Policy\Web\web01`); err == nil {
+		t.Error("DnToGuid of a deleted object succeeded from the cache")
+	}
+}
+
+func TestLookupCacheExpiryAndEviction(t *testing.T) {
+	v, srv, transport := newCountingClient(t)
+	srv.AddObject(`\VED\Policy\A`, "Policy", nil)
+	srv.AddObject(`\VED\Policy\B`, "Policy", nil)
+
+	lookup := func(dn string) {
+		t.Helper()
+		if _, err := v.Config.DnToGuid(dn); err != nil {
+			t.Fatalf("DnToGuid(%s): %v", dn, err)
+		}
+	}
+
+	// With room for one entry, looking up B evicts A.
+	v.EnableLookupCache(1, 0)
+	lookup(`\VED\Policy\A`)
+	lookup(`\VED\Policy\B`)
+	lookup(`\VED\Policy\A`)
+	if n := transport.count("/vedsdk/Config/DnToGuid"); n != 3 {
+		t.Errorf("after eviction: %d requests, want 3", n)
+	}
+
+	// Entries expire after the TTL.
+	v.EnableLookupCache(10, 20*time.Millisecond)
+	lookup(`\VED\Policy\A`)
+	lookup(`\VED\Policy\A`)
+	time.Sleep(40 * time.Millisecond)
+	lookup(`\VED\Policy\A`)
+	if n := transport.count("/vedsdk/Config/DnToGuid"); n != 5 {
+		t.Errorf("after expiry: %d requests, want 5", n)
+	}
+
+	// A size of zero turns the cache off.
+	v.EnableLookupCache(0, 0)
+	lookup(`\VED\Policy\A`)
+	lookup(`\VED\Policy\A`)
+	if n := transport.count("/vedsdk/Config/DnToGuid"); n != 7 {
+		t.Errorf("with caching off: %d requests, want 7", n)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -48,17 +49,14 @@ func (s *CertificateService) doRequestWithBody(method string, path string, param
 
 	res, err := s.client.doRequestWithBody(method, path, params)
 	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest && apiErr.Err == nil {
+			apiErr.Err = &CertificateServiceError{Message: apiErr.Message}
+		}
 		return nil, err
 	}
 
 	defer res.Body.Close()
-	if res.StatusCode == 400 {
-		var errorOutput CertificateServiceError
-		if err := json.NewDecoder(res.Body).Decode(&errorOutput); err != nil {
-			return nil, err
-		}
-		return nil, &errorOutput
-	}
 
 	if output != nil {
 		if err := json.NewDecoder(res.Body).Decode(output); err != nil {
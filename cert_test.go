@@ -1 +1,105 @@
-package venafi
+package venafi_test
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+func TestCertificateImportAndRetrieve(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	cert, key := newCertificate(t, "web01.example.com", 30, "web01.example.com", "www.example.com")
+
+	out, err := v.Certs.Import(`\VED\Policy\Web`, "web01", cert, key, false)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if out.CertificateDN != `\VED\Policy\Web\web01` || out.CertificateGuid == "" || out.CertificateVaultId == 0 {
+		t.Errorf("Import = %+v", out)
+	}
+
+	got, pk, err := v.Certs.Retrieve(`\VED\Policy\Web\web01`)
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if !got.Equal(cert) {
+		t.Errorf("Retrieve returned %s, want the imported certificate", got.Subject)
+	}
+	if pk == nil || !pk.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(key.Public()) {
+		t.Error("Retrieve did not return the imported private key")
+	}
+
+	list, err := v.Certs.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("List returned %d certificates, want 1", len(list))
+	}
+	c := list[0]
+	if c.ObjectDN != `\VED\Policy\Web\web01` || c.ParentDN != `\VED\Policy\Web` || c.Class != config.ClassX509Certificate ||
+		c.X509.CommonName != "web01.example.com" || len(c.X509.AltNames["DNS"]) != 2 || !c.X509.ValidTo.Equal(cert.NotAfter) {
+		t.Errorf("List entry = %+v", c)
+	}
+}
+
+func TestCertificateListPages(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	cert, key := newCertificate(t, "web.example.com", 30)
+
+	// More than one page of 100.
+	const n = 130
+	for i := 0; i < n; i++ {
+		if _, err := v.Certs.Import(`\VED\Policy\Web`, fmt.Sprintf("web%03d", i), cert, key, false); err != nil {
+			t.Fatalf("Import: %v", err)
+		}
+	}
+
+	list, err := v.Certs.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != n {
+		t.Errorf("List returned %d certificates, want %d", len(list), n)
+	}
+	seen := make(map[string]bool)
+	for _, c := range list {
+		seen[c.ObjectDN] = true
+	}
+	if len(seen) != n {
+		t.Errorf("List returned %d distinct certificates, want %d", len(seen), n)
+	}
+}
+
+func TestCertificateErrors(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	cert, key := newCertificate(t, "web01.example.com", 30)
+
+	_, _, err := v.Certs.Retrieve(`\VED\Policy\Missing`)
+	var certErr *venafi.CertificateServiceError
+	if !errors.As(err, &certErr) {
+		t.Errorf("Retrieve of a missing certificate: got %v, want *CertificateServiceError", err)
+	}
+
+	if _, err := v.Certs.Import(`\VED\Policy\Missing`, "web01", cert, key, false); err == nil {
+		t.Error("Import into a missing folder succeeded")
+	}
+
+	// Importing over an existing certificate needs reconcile.
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	if _, err := v.Certs.Import(`\VED\Policy\Web`, "web01", cert, key, false); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if _, err := v.Certs.Import(`\VED\Policy\Web`, "web01", cert, key, false); err == nil {
+		t.Error("Import over an existing certificate without reconcile succeeded")
+	}
+	if _, err := v.Certs.Import(`\VED\Policy\Web`, "web01", cert, key, true); err != nil {
+		t.Errorf("Import with reconcile: %v", err)
+	}
+}
@@ -9,26 +9,46 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"sync"
 
 	"github.com/hashicorp/go-hclog"
 )
 
+// Client is a connection to a TPP server. APIKey is fetched on the first
+// request if it is empty; set or clear it only while no requests are in
+// flight.
 type Client struct {
-	Username  string
-	Password  string
-	BaseURL   *url.URL
-	APIKey    string
-	client    *http.Client
-	X509Store *X509StoreService
-	Identity  *IdentityService
-	Config    *ConfigService
-	Policy    *PolicyService
-	CA        *CAService
-	Certs     *CertificateService
-	logger    hclog.Logger
+	Username    string
+	Password    string
+	BaseURL     *url.URL
+	APIKey      string
+	client      *http.Client
+	X509Store   X509StoreAPI
+	Identity    IdentityAPI
+	Config      ConfigAPI
+	Policy      PolicyAPI
+	CA          CAAPI
+	Certs       CertificateAPI
+	Schema      SchemaAPI
+	Permissions PermissionsAPI
+	SecretStore SecretStoreAPI
+	Crypto      CryptoAPI
+	logger      hclog.Logger
+	cache       *lookupCache
+	schema      schemaCache
+	session     sessionCache
+	authMu      sync.Mutex
 }
 
 func NewClient(httpAddress string, username string, password string, httpClient *http.Client) (*Client, error) {
+	return NewClientWithLogger(httpAddress, username, password, httpClient, nil)
+}
+
+// NewClientWithLogger is like NewClient but sends log output to the given
+// logger. Secrets are always masked before they reach it. If logger is nil a
+// default logger at Info level is used.
+func NewClientWithLogger(httpAddress string, username string, password string, httpClient *http.Client,
+	logger hclog.Logger) (*Client, error) {
 	baseURL, err := url.Parse(httpAddress)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing Venafi base URL: %s", err)
@@ -38,15 +58,16 @@ func NewClient(httpAddress string, username string, password string, httpClient
 		httpClient = http.DefaultClient
 	}
 
+	if logger == nil {
+		logger = defaultLogger()
+	}
+
 	c := &Client{
 		BaseURL:  baseURL,
 		Username: username,
 		Password: password,
 		client:   httpClient,
-		logger: hclog.New(&hclog.LoggerOptions{
-			Name:  "venafi",
-			Level: hclog.Debug,
-		}),
+		logger:   NewRedactingLogger(logger),
 	}
 
 	c.X509Store = &X509StoreService{c}
@@ -55,15 +76,42 @@ func NewClient(httpAddress string, username string, password string, httpClient
 	c.Policy = &PolicyService{c}
 	c.CA = &CAService{c}
 	c.Certs = &CertificateService{c}
+	c.Schema = &SchemaService{c}
+	c.Permissions = &PermissionsService{c}
+	c.SecretStore = &SecretStoreService{c}
+	c.Crypto = &CryptoService{c}
 
 	return c, nil
 }
 
 func NewFromEnviron() (*Client, error) {
-	return NewClient(os.Getenv("VENAFI_TPP_ADDR"),
+	c, err := NewClient(os.Getenv("VENAFI_TPP_ADDR"),
 		os.Getenv("VENAFI_TPP_USERNAME"),
 		os.Getenv("VENAFI_TPP_PASSWORD"),
 		nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if level := os.Getenv("VENAFI_TPP_LOG_LEVEL"); level != "" {
+		c.SetLogLevel(hclog.LevelFromString(level))
+	}
+
+	return c, nil
+}
+
+// SetLogger replaces the client's logger. Secrets are masked before they
+// reach it.
+func (c *Client) SetLogger(logger hclog.Logger) {
+	c.logger = NewRedactingLogger(logger)
+}
+
+// SetLogLevel changes the level of the client's logger. Request and response
+// bodies are only dumped at Debug or lower. The level is set on the logger
+// given to NewClientWithLogger or SetLogger itself, so anything else sharing
+// that logger sees the change too.
+func (c *Client) SetLogLevel(level hclog.Level) {
+	c.logger.SetLevel(level)
 }
 
 func (c *Client) getURL(path string) (*url.URL, error) {
@@ -76,7 +124,7 @@ func (c *Client) getURL(path string) (*url.URL, error) {
 	return combined, nil
 }
 
-func (c *Client) prepareRequest(method string, url *url.URL, params map[string]string, body interface{}) (*http.Request, error) {
+func (c *Client) prepareRequest(method string, url *url.URL, apiKey string, params map[string]string, body interface{}) (*http.Request, error) {
 	var buf io.ReadWriter //= new(bytes.Buffer)
 	if body != nil {
 		//buf2 := new(bytes.Buffer)
@@ -104,8 +152,8 @@ func (c *Client) prepareRequest(method string, url *url.URL, params map[string]s
 		req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	}
 
-	if c.APIKey != "" {
-		req.Header.Set("X-Venafi-Api-Key", c.APIKey)
+	if apiKey != "" {
+		req.Header.Set("X-Venafi-Api-Key", apiKey)
 	}
 
 	req.Header.Set("Accept", "application/json")
@@ -113,35 +161,38 @@ func (c *Client) prepareRequest(method string, url *url.URL, params map[string]s
 	return req, nil
 }
 
-func (c *Client) doRequestInternal(method string, url *url.URL, params map[string]string, body interface{}) (*http.Response, error) {
-	req, err := c.prepareRequest(method, url, params, body)
+func (c *Client) doRequestInternal(method string, url *url.URL, apiKey string, params map[string]string, body interface{}) (*http.Response, error) {
+	req, err := c.prepareRequest(method, url, apiKey, params, body)
 	if err != nil {
 		return nil, err
 	}
 
-	reqText, _ := httputil.DumpRequest(req, true)
-	c.logger.Debug("Sending request:\n" + string(reqText))
+	if c.logger.IsDebug() {
+		reqText, _ := httputil.DumpRequest(req, true)
+		c.logger.Debug("Sending request:\n" + string(reqText))
+	}
 
 	res, err := c.client.Do(req)
 	if err != nil {
 		return res, err
 	}
 
-	resText, _ := httputil.DumpResponse(res, true)
-	c.logger.Debug("Received response:\n" + string(resText))
+	if c.logger.IsDebug() {
+		resText, _ := httputil.DumpResponse(res, true)
+		c.logger.Debug("Received response:\n" + string(resText))
+	}
 
-	if res.StatusCode > 400 {
-		return res, fmt.Errorf("unexpected status code response: %d (%s)", res.StatusCode, res.Status)
+	if res.StatusCode >= 400 {
+		return nil, newHTTPError(res)
 	}
 
 	return res, nil
 }
 
 func (c *Client) doRequestWithParams(method string, path string, params map[string]string) (*http.Response, error) {
-	if c.APIKey == "" {
-		if err := c.getAPIKey(); err != nil {
-			return nil, err
-		}
+	apiKey, err := c.apiKey()
+	if err != nil {
+		return nil, err
 	}
 
 	finalUrl, err := c.getURL(path)
@@ -149,7 +200,7 @@ func (c *Client) doRequestWithParams(method string, path string, params map[stri
 		return nil, err
 	}
 
-	return c.doRequestInternal(method, finalUrl, params, nil)
+	return c.doRequestInternal(method, finalUrl, apiKey, params, nil)
 }
 
 func (c *Client) doJsonRequestWithParams(method string, path string, params map[string]string, output interface{}) (*http.Response, error) {
@@ -167,10 +218,9 @@ func (c *Client) doJsonRequestWithParams(method string, path string, params map[
 }
 
 func (c *Client) doRequestWithBody(method string, path string, body interface{}) (*http.Response, error) {
-	if c.APIKey == "" {
-		if err := c.getAPIKey(); err != nil {
-			return nil, err
-		}
+	apiKey, err := c.apiKey()
+	if err != nil {
+		return nil, err
 	}
 
 	finalUrl, err := c.getURL(path)
@@ -178,7 +228,7 @@ func (c *Client) doRequestWithBody(method string, path string, body interface{})
 		return nil, err
 	}
 
-	return c.doRequestInternal(method, finalUrl, nil, body)
+	return c.doRequestInternal(method, finalUrl, apiKey, nil, body)
 }
 
 func (c *Client) doJsonRequestWithBody(method string, path string, body interface{}, output interface{}) (*http.Response, error) {
@@ -195,6 +245,23 @@ func (c *Client) doJsonRequestWithBody(method string, path string, body interfac
 	return res, nil
 }
 
+// apiKey returns the client's API key, authenticating first if it has none.
+// Requests may run on several goroutines, as in ExpiryReport, so authMu makes
+// them wait for a single authorize call instead of each starting one.
+func (c *Client) apiKey() (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.APIKey == "" {
+		if err := c.getAPIKey(); err != nil {
+			return "", err
+		}
+	}
+	return c.APIKey, nil
+}
+
+// getAPIKey authenticates with the client's username and password. The
+// caller must hold authMu.
 func (c *Client) getAPIKey() error {
 	c.APIKey = ""
 
@@ -203,16 +270,23 @@ func (c *Client) getAPIKey() error {
 		return err
 	}
 
-	req, err := c.prepareRequest("POST", finalUrl, nil, map[string]interface{}{
+	req, err := c.prepareRequest("POST", finalUrl, "", nil, map[string]interface{}{
 		"Username": c.Username,
 		"Password": c.Password,
 	})
+	if err != nil {
+		return err
+	}
 
 	res, err := c.client.Do(req)
 	if err != nil {
 		return err
 	}
 
+	if res.StatusCode >= 400 {
+		return newHTTPError(res)
+	}
+
 	e := make(map[string]string)
 
 	if err := json.NewDecoder(res.Body).Decode(&e); err != nil {
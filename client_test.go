@@ -0,0 +1,139 @@
+package venafi_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+// newTestClient starts a fake TPP server and returns a client logged in to
+// it. The server is closed when the test finishes. A nil logger discards log
+// output.
+func newTestClient(t *testing.T, logger hclog.Logger) (*venafi.Client, *venafitest.Server) {
+	t.Helper()
+
+	srv := venafitest.NewServer()
+	t.Cleanup(srv.Close)
+
+	if logger == nil {
+		logger = hclog.New(&hclog.LoggerOptions{Output: ioutil.Discard})
+	}
+	v, err := venafi.NewClientWithLogger(srv.URL, srv.Username, srv.Password, srv.Client(), logger)
+	if err != nil {
+		t.Fatalf("NewClientWithLogger: %v", err)
+	}
+	return v, srv
+}
+
+// countingTransport counts the requests made to each path.
+type countingTransport struct {
+	next http.RoundTripper
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.calls[strings.ToLower(req.URL.Path)]++
+	t.mu.Unlock()
+	return t.next.RoundTrip(req)
+}
+
+func (t *countingTransport) count(path string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.calls[strings.ToLower(path)]
+}
+
+// newCountingClient is like newTestClient but also returns a transport that
+// counts the requests the client makes.
+func newCountingClient(t *testing.T) (*venafi.Client, *venafitest.Server, *countingTransport) {
+	t.Helper()
+
+	srv := venafitest.NewServer()
+	t.Cleanup(srv.Close)
+
+	transport := &countingTransport{next: srv.Client().Transport, calls: make(map[string]int)}
+	logger := hclog.New(&hclog.LoggerOptions{Output: ioutil.Discard})
+	v, err := venafi.NewClientWithLogger(srv.URL, srv.Username, srv.Password, &http.Client{Transport: transport}, logger)
+	if err != nil {
+		t.Fatalf("NewClientWithLogger: %v", err)
+	}
+	return v, srv, transport
+}
+
+// newCertificate returns a self-signed certificate for commonName, valid for
+// the given number of days, and its key.
+func newCertificate(t *testing.T, commonName string, days int, dnsNames ...string) (*x509.Certificate, crypto.Signer) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		t.Fatalf("rand.Int: %v", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Duration(days)*24*time.Hour + time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	return cert, key
+}
+
+func TestConcurrentRequestsAuthenticateOnce(t *testing.T) {
+	v, srv, transport := newCountingClient(t)
+	srv.AddObject(`\VED\Policy\Web`, "Policy", nil)
+
+	// The first requests of a new client all need an API key.
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Config.IsValid(`\VED\Policy\Web`, "")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("IsValid: %v", err)
+		}
+	}
+	if n := transport.count("/vedsdk/authorize/"); n != 1 {
+		t.Errorf("concurrent requests authenticated %d times, want 1", n)
+	}
+}
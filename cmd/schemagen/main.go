@@ -0,0 +1,64 @@
+// Command schemagen writes Go constants for the classes and attributes in a
+// TPP schema, in the style of pkg/const/config. It connects using the
+// VENAFI_TPP_ADDR, VENAFI_TPP_USERNAME and VENAFI_TPP_PASSWORD environment
+// variables.
+//
+//	schemagen -package config -classes "Policy,X509 Certificate" -o schema_const.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	venafi "github.com/tradel/venafi-tpp"
+)
+
+func main() {
+	pkg := flag.String("package", "config", "package name of the generated file")
+	classes := flag.String("classes", "", "comma-separated class names to declare constants for")
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	if err := run(*pkg, *classes, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "schemagen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg string, classes string, out string) (err error) {
+	client, err := venafi.NewFromEnviron()
+	if err != nil {
+		return err
+	}
+
+	var classNames []string
+	for _, name := range strings.Split(classes, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			if _, err := client.Schema.Class(name); err != nil {
+				return err
+			}
+			classNames = append(classNames, name)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		// Report a failed close, since it can mean the file was not fully
+		// written.
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+
+	return venafi.GenerateConstants(client.Schema, w, pkg, classNames)
+}
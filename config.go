@@ -3,11 +3,14 @@ package venafi
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
 	"strconv"
-	
+	"strings"
+
 	"github.com/tradel/venafi-tpp/pkg/const/config"
 )
 
@@ -58,7 +61,7 @@ func (s *ConfigService) doRequestWithBody(method string, path string, body inter
 	}
 
 	if resultOutput.Result != config.Success {
-		return nil, &ConfigServiceError{Result: resultOutput.Result, Message: resultOutput.Error}
+		return nil, newConfigError(res, resultOutput.Result, resultOutput.Error)
 	}
 
 	res.Body = save
@@ -94,6 +97,9 @@ func (s *ConfigService) Create(objectDN string, className string, attributes map
 		for k, v := range attributes {
 			input.NameAttributeList = append(input.NameAttributeList, NameAttributePair{k, v})
 		}
+		sort.Slice(input.NameAttributeList, func(i, j int) bool {
+			return input.NameAttributeList[i].Name < input.NameAttributeList[j].Name
+		})
 	}
 	var output Output
 
@@ -102,6 +108,7 @@ func (s *ConfigService) Create(objectDN string, className string, attributes map
 		return nil, err
 	}
 
+	s.remember(&output.Object)
 	return &output.Object, nil
 }
 
@@ -122,6 +129,7 @@ func (s *ConfigService) IsValid(objectDN string, objectGUID string) (*ConfigObje
 		return nil, err
 	}
 
+	s.remember(&output.Object)
 	return &output.Object, nil
 }
 
@@ -165,6 +173,9 @@ func (s *ConfigService) Delete(objectDN string, recursive bool) error {
 		return err
 	}
 
+	if s.client.cache != nil {
+		s.client.cache.invalidateTree(objectDN)
+	}
 	return nil
 }
 
@@ -290,6 +301,9 @@ func (s *ConfigService) Write(objectDN string, attributes map[string][]string) e
 		for k, v := range attributes {
 			input.AttributeData = append(input.AttributeData, NameAttributePair{k, v})
 		}
+		sort.Slice(input.AttributeData, func(i, j int) bool {
+			return input.AttributeData[i].Name < input.AttributeData[j].Name
+		})
 	}
 
 	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/Write", input, nil)
@@ -300,6 +314,448 @@ func (s *ConfigService) Write(objectDN string, attributes map[string][]string) e
 	return nil
 }
 
+// GetRevision returns the revision of the object at objectDN. It changes
+// every time the object does.
+func (s *ConfigService) GetRevision(objectDN string) (int64, error) {
+	type Input struct {
+		ObjectDN string
+	}
+	type Output struct {
+		Revision int64
+	}
+
+	var input Input = Input{objectDN}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/GetRevision", input, &output)
+	if err != nil {
+		return 0, err
+	}
+
+	return output.Revision, nil
+}
+
+// RemoveValue removes a single value from a multi-valued attribute, leaving
+// any other values in place.
+func (s *ConfigService) RemoveValue(objectDN string, name string, value string) error {
+	type Input struct {
+		ObjectDN      string
+		AttributeName string
+		Values        []string
+	}
+
+	var input Input = Input{objectDN, name, []string{value}}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/RemoveAttributeValues", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveDnValue removes a single DN from a DN-syntax attribute.
+func (s *ConfigService) RemoveDnValue(objectDN string, name string, value string) error {
+	type Input struct {
+		ObjectDN      string
+		AttributeName string
+		Value         string
+	}
+
+	var input Input = Input{objectDN, name, value}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/RemoveDnValue", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WriteDn replaces the values of a DN-syntax attribute. TPP checks that each
+// value refers to an existing object.
+func (s *ConfigService) WriteDn(objectDN string, name string, values []string) error {
+	type Input struct {
+		ObjectDN      string
+		AttributeName string
+		Values        []string
+	}
+
+	var input Input = Input{objectDN, name, values}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/WriteDn", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Find searches the whole tree for objects matching pattern, which may
+// contain * wildcards. Without attributeNames the pattern is matched against
+// object names; otherwise an object matches if any value of one of the named
+// attributes does.
+func (s *ConfigService) Find(pattern string, attributeNames ...string) ([]ConfigObject, error) {
+	type Input struct {
+		Pattern        string
+		AttributeNames []string `json:",omitempty"`
+	}
+	type Output struct {
+		Objects []ConfigObject
+	}
+
+	var input Input = Input{pattern, attributeNames}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/Find", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Objects, nil
+}
+
+// FindObjectsOfClass returns the objects of any of classNames below
+// objectDN, optionally only those whose name matches pattern. An empty
+// objectDN searches the whole tree, in which case recursive is ignored.
+func (s *ConfigService) FindObjectsOfClass(classNames []string, objectDN string, recursive bool, pattern string) ([]ConfigObject, error) {
+	type Input struct {
+		Classes   string
+		ObjectDN  string `json:",omitempty"`
+		Recursive int    `json:",omitempty"`
+		Pattern   string `json:",omitempty"`
+	}
+	type Output struct {
+		Objects []ConfigObject
+	}
+
+	var input Input = Input{strings.Join(classNames, ","), objectDN, btoi(recursive), pattern}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/FindObjectsOfClass", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Objects, nil
+}
+
+// FindContainers returns the container objects, such as policy folders and
+// devices, below objectDN.
+func (s *ConfigService) FindContainers(objectDN string, recursive bool) ([]ConfigObject, error) {
+	type Input struct {
+		ObjectDN  string
+		Recursive int `json:",omitempty"`
+	}
+	type Output struct {
+		Objects []ConfigObject
+	}
+
+	var input Input = Input{objectDN, btoi(recursive)}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/FindContainers", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Objects, nil
+}
+
+// PolicyValue is the value of a policy attribute together with the policy
+// folder it was set on. Overridden is set by ReadEffectivePolicy when a locked
+// policy hides a value set on the object itself.
+type PolicyValue struct {
+	PolicyDN   string
+	Locked     bool
+	Overridden bool
+	Values     []string
+}
+
+// FindPolicy returns the policy value for attributeName on objects of
+// className that applies at objectDN, and the folder it was set on. It
+// returns an error wrapping ErrNotFound if no folder at or above objectDN
+// sets it.
+func (s *ConfigService) FindPolicy(objectDN string, className string, attributeName string) (*PolicyValue, error) {
+	type Input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+	}
+
+	var input Input = Input{objectDN, className, attributeName}
+	var output PolicyValue
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/FindPolicy", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+func (s *ConfigService) DnToGuid(objectDN string) (string, error) {
+	if s.client.cache != nil {
+		if entry, ok := s.client.cache.getByDN(objectDN); ok {
+			return entry.guid, nil
+		}
+	}
+
+	type Input struct {
+		ObjectDN string
+	}
+	type Output struct {
+		ClassName string
+		GUID      string
+		Revision  int64
+	}
+
+	var input Input = Input{objectDN}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/DnToGuid", input, &output)
+	if err != nil {
+		return "", err
+	}
+
+	s.remember(&ConfigObject{DN: objectDN, GUID: output.GUID, Class: output.ClassName, Revision: output.Revision})
+	return output.GUID, nil
+}
+
+func (s *ConfigService) GuidToDn(objectGUID string) (string, error) {
+	if s.client.cache != nil {
+		if entry, ok := s.client.cache.getByGUID(objectGUID); ok {
+			return entry.dn, nil
+		}
+	}
+
+	type Input struct {
+		ObjectGUID string
+	}
+	type Output struct {
+		ClassName string
+		ObjectDN  string
+		Revision  int64
+	}
+
+	var input Input = Input{objectGUID}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/GuidToDn", input, &output)
+	if err != nil {
+		return "", err
+	}
+
+	s.remember(&ConfigObject{DN: output.ObjectDN, GUID: objectGUID, Class: output.ClassName, Revision: output.Revision})
+	return output.ObjectDN, nil
+}
+
+// IdInfo looks up an object by GUID or numeric ID. The returned object has its
+// GUID, Id, Class and Revision set, but not its DN.
+func (s *ConfigService) IdInfo(objectID string) (*ConfigObject, error) {
+	type Input struct {
+		ObjectID string
+	}
+	type Output struct {
+		ClassName string
+		GUID      string
+		Revision  int64
+	}
+
+	var input Input = Input{objectID}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/IdInfo", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &ConfigObject{GUID: output.GUID, Class: output.ClassName, Revision: output.Revision}
+	if id, err := strconv.Atoi(objectID); err == nil {
+		obj.Id = id
+	}
+	return obj, nil
+}
+
+// ReadDn reads a DN-syntax attribute, returning the DNs of the objects it
+// refers to.
+func (s *ConfigService) ReadDn(objectDN string, name string) ([]string, error) {
+	type Input struct {
+		ObjectDN      string
+		AttributeName string
+	}
+	type Output struct {
+		Values []string
+	}
+
+	var input Input = Input{objectDN, name}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/ReadDn", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Values, nil
+}
+
+// ReadEffectivePolicy returns the value of an attribute as it applies to
+// objectDN, taking policies set on ancestor folders into account.
+func (s *ConfigService) ReadEffectivePolicy(objectDN string, attributeName string) (*PolicyValue, error) {
+	type Input struct {
+		ObjectDN      string
+		AttributeName string
+	}
+
+	var input Input = Input{objectDN, attributeName}
+	var output PolicyValue
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/ReadEffectivePolicy", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// ReadPolicy returns the policy value set directly on the folder policyDN for
+// objects of className.
+func (s *ConfigService) ReadPolicy(policyDN string, className string, attributeName string) (*PolicyValue, error) {
+	type Input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+	}
+
+	var input Input = Input{policyDN, className, attributeName}
+	var output PolicyValue
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/ReadPolicy", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	output.PolicyDN = policyDN
+	return &output, nil
+}
+
+// WritePolicy sets a policy value on the folder policyDN for objects of
+// className, replacing any existing values. A locked value cannot be changed
+// on objects or folders below policyDN.
+func (s *ConfigService) WritePolicy(policyDN string, className string, attributeName string, values []string, locked bool) error {
+	type Input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+		Locked        int
+		Values        []string
+	}
+
+	var input Input = Input{policyDN, className, attributeName, btoi(locked), values}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/WritePolicy", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddPolicyValue adds value to a policy attribute on the folder policyDN for
+// objects of className, keeping any values already there. locked applies to
+// the attribute as a whole, so it also locks or unlocks the existing values.
+func (s *ConfigService) AddPolicyValue(policyDN string, className string, attributeName string, value string, locked bool) error {
+	type Input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+		Value         string
+		Locked        int
+	}
+
+	var input Input = Input{policyDN, className, attributeName, value, btoi(locked)}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/AddPolicyValue", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClearPolicyAttribute removes a policy attribute and its lock from the
+// folder policyDN, so objects below it inherit from further up the tree again.
+func (s *ConfigService) ClearPolicyAttribute(policyDN string, className string, attributeName string) error {
+	type Input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+	}
+
+	var input Input = Input{policyDN, className, attributeName}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/ClearPolicyAttribute", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameObject renames or moves the object at oldDN to newDN and returns the
+// object at its new location.
+func (s *ConfigService) RenameObject(oldDN string, newDN string) (*ConfigObject, error) {
+	type Input struct {
+		ObjectDN    string
+		NewObjectDN string
+	}
+
+	var input Input = Input{oldDN, newDN}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/RenameObject", input, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.client.cache != nil {
+		s.client.cache.invalidateTree(oldDN)
+	}
+	return s.IsValid(newDN, "")
+}
+
+// Move relocates the object at objectDN into the container at newParentDN,
+// keeping its name. The destination's class is checked against the schema
+// first, and an ObjectInvalidContainment error is returned if the object may
+// not live there.
+func (s *ConfigService) Move(objectDN string, newParentDN string) (*ConfigObject, error) {
+	obj, err := s.IsValid(objectDN, "")
+	if err != nil {
+		return nil, err
+	}
+	parent, err := s.IsValid(newParentDN, "")
+	if err != nil {
+		return nil, err
+	}
+
+	class, err := s.client.Schema.Class(obj.Class)
+	if err != nil {
+		return nil, err
+	}
+	if !containsFold(class.ContainmentNames, parent.Class) {
+		return nil, newConfigError(nil, config.ObjectInvalidContainment,
+			fmt.Sprintf("a %s object cannot be contained in a %s object", obj.Class, parent.Class))
+	}
+
+	return s.RenameObject(obj.DN, strings.TrimSuffix(parent.DN, `\`)+`\`+obj.Name)
+}
+
+// remember adds obj to the client's lookup cache, if one is enabled.
+func (s *ConfigService) remember(obj *ConfigObject) {
+	if s.client.cache != nil {
+		s.client.cache.put(lookupEntry{dn: obj.DN, guid: obj.GUID, class: obj.Class, revision: obj.Revision})
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Helper funcs
 ///////////////////////////////////////////////////////////////////////////////
@@ -311,6 +767,15 @@ func btoi(b bool) int {
 	return 0
 }
 
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
 // drainBody reads all of b to memory and then returns two equivalent
 // ReadClosers yielding the same bytes.
 //
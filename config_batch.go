@@ -0,0 +1,261 @@
+package venafi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigBatch makes Config changes through a ConfigAPI and remembers how to
+// undo each one, so that a multi-step operation that fails part way can be
+// rolled back. Changes are made immediately; TPP has no server-side
+// transactions.
+type ConfigBatch struct {
+	config  ConfigAPI
+	created map[string]bool
+	undo    []batchStep
+}
+
+type batchStep struct {
+	description string
+	undo        func() error
+}
+
+// NewConfigBatch starts a batch of changes made through config, normally a
+// client's Config field.
+func NewConfigBatch(config ConfigAPI) *ConfigBatch {
+	return &ConfigBatch{config: config, created: make(map[string]bool)}
+}
+
+// RunConfigBatch calls fn with a new batch and rolls back every change it made
+// if fn returns an error. The error from fn is returned unchanged if the
+// rollback succeeds, or wrapped in a *RollbackError if it does not.
+func RunConfigBatch(config ConfigAPI, fn func(b *ConfigBatch) error) error {
+	b := NewConfigBatch(config)
+	err := fn(b)
+	if err == nil {
+		return nil
+	}
+	if rbErr := b.Rollback(); rbErr != nil {
+		return &RollbackError{Err: err, Failures: rbErr.(*RollbackError).Failures}
+	}
+	return err
+}
+
+// Len returns the number of changes recorded so far.
+func (b *ConfigBatch) Len() int {
+	return len(b.undo)
+}
+
+// Create creates an object. Rolling back deletes it along with anything
+// created below it.
+func (b *ConfigBatch) Create(objectDN string, className string, attributes map[string]string) (*ConfigObject, error) {
+	obj, err := b.config.Create(objectDN, className, attributes)
+	if err != nil {
+		return nil, err
+	}
+	b.created[strings.ToLower(objectDN)] = true
+	b.record("create "+objectDN, func() error {
+		return b.config.Delete(objectDN, true)
+	})
+	return obj, nil
+}
+
+// Write replaces attribute values. Rolling back restores the values the
+// attributes had before.
+func (b *ConfigBatch) Write(objectDN string, attributes map[string][]string) error {
+	var old map[string][]string
+	if !b.isCreated(objectDN) {
+		old = make(map[string][]string, len(attributes))
+		for name := range attributes {
+			values, err := b.config.Read(objectDN, name)
+			if err != nil {
+				return err
+			}
+			old[name] = values
+		}
+	}
+
+	if err := b.config.Write(objectDN, attributes); err != nil {
+		return err
+	}
+	if old != nil {
+		b.record("write "+objectDN, func() error {
+			return b.restore(objectDN, old)
+		})
+	}
+	return nil
+}
+
+// AddValue adds a value to an attribute. Rolling back removes it again.
+func (b *ConfigBatch) AddValue(objectDN string, name string, value string) error {
+	if err := b.config.AddValue(objectDN, name, value); err != nil {
+		return err
+	}
+	if !b.isCreated(objectDN) {
+		b.record(fmt.Sprintf("add value to %s on %s", name, objectDN), func() error {
+			return b.config.RemoveValue(objectDN, name, value)
+		})
+	}
+	return nil
+}
+
+// ClearAttribute removes all values of an attribute. Rolling back writes them
+// back.
+func (b *ConfigBatch) ClearAttribute(objectDN string, name string) error {
+	var old []string
+	if !b.isCreated(objectDN) {
+		var err error
+		if old, err = b.config.Read(objectDN, name); err != nil {
+			return err
+		}
+	}
+
+	if err := b.config.ClearAttribute(objectDN, name); err != nil {
+		return err
+	}
+	if len(old) > 0 {
+		b.record(fmt.Sprintf("clear %s on %s", name, objectDN), func() error {
+			return b.config.Write(objectDN, map[string][]string{name: old})
+		})
+	}
+	return nil
+}
+
+// WritePolicy sets a policy value on a folder. Rolling back restores the
+// previous value and lock, or clears the policy if there was none.
+func (b *ConfigBatch) WritePolicy(policyDN string, className string, attributeName string, values []string, locked bool) error {
+	var old *PolicyValue
+	if !b.isCreated(policyDN) {
+		var err error
+		if old, err = b.config.ReadPolicy(policyDN, className, attributeName); err != nil {
+			return err
+		}
+	}
+
+	if err := b.config.WritePolicy(policyDN, className, attributeName, values, locked); err != nil {
+		return err
+	}
+	if old != nil {
+		b.record(fmt.Sprintf("write policy %s on %s", attributeName, policyDN), func() error {
+			return b.restorePolicy(policyDN, className, attributeName, old)
+		})
+	}
+	return nil
+}
+
+// ClearPolicyAttribute removes a policy value from a folder. Rolling back
+// writes it back.
+func (b *ConfigBatch) ClearPolicyAttribute(policyDN string, className string, attributeName string) error {
+	var old *PolicyValue
+	if !b.isCreated(policyDN) {
+		var err error
+		if old, err = b.config.ReadPolicy(policyDN, className, attributeName); err != nil {
+			return err
+		}
+	}
+
+	if err := b.config.ClearPolicyAttribute(policyDN, className, attributeName); err != nil {
+		return err
+	}
+	if old != nil && len(old.Values) > 0 {
+		b.record(fmt.Sprintf("clear policy %s on %s", attributeName, policyDN), func() error {
+			return b.restorePolicy(policyDN, className, attributeName, old)
+		})
+	}
+	return nil
+}
+
+// Rollback undoes every recorded change, newest first. It carries on past
+// failures and returns a *RollbackError listing them. The batch is empty
+// afterwards.
+func (b *ConfigBatch) Rollback() error {
+	var failures []error
+	for i := len(b.undo) - 1; i >= 0; i-- {
+		step := b.undo[i]
+		if err := step.undo(); err != nil {
+			failures = append(failures, fmt.Errorf("error undoing %s: %w", step.description, err))
+		}
+	}
+	b.undo = nil
+	b.created = make(map[string]bool)
+
+	if len(failures) > 0 {
+		return &RollbackError{Failures: failures}
+	}
+	return nil
+}
+
+// Commit forgets the recorded changes so they can no longer be rolled back.
+func (b *ConfigBatch) Commit() {
+	b.undo = nil
+	b.created = make(map[string]bool)
+}
+
+func (b *ConfigBatch) record(description string, undo func() error) {
+	b.undo = append(b.undo, batchStep{description, undo})
+}
+
+// isCreated reports whether objectDN, or one of its parents, was created by
+// this batch. Changes to such objects need no undo step of their own.
+func (b *ConfigBatch) isCreated(objectDN string) bool {
+	dn := strings.ToLower(objectDN)
+	for {
+		if b.created[dn] {
+			return true
+		}
+		i := strings.LastIndex(dn, `\`)
+		if i <= 0 {
+			return false
+		}
+		dn = dn[:i]
+	}
+}
+
+func (b *ConfigBatch) restore(objectDN string, old map[string][]string) error {
+	write := make(map[string][]string)
+	for name, values := range old {
+		if len(values) > 0 {
+			write[name] = values
+		} else if err := b.config.ClearAttribute(objectDN, name); err != nil {
+			return err
+		}
+	}
+	if len(write) > 0 {
+		return b.config.Write(objectDN, write)
+	}
+	return nil
+}
+
+func (b *ConfigBatch) restorePolicy(policyDN string, className string, attributeName string, old *PolicyValue) error {
+	if len(old.Values) == 0 {
+		return b.config.ClearPolicyAttribute(policyDN, className, attributeName)
+	}
+	return b.config.WritePolicy(policyDN, className, attributeName, old.Values, old.Locked)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Errors
+///////////////////////////////////////////////////////////////////////////////
+
+// RollbackError is returned when a batch could not be fully rolled back. Err
+// is the error that caused the rollback, if any, and Failures lists each undo
+// step that failed. TPP may be left partly changed.
+type RollbackError struct {
+	Err      error
+	Failures []error
+}
+
+func (e *RollbackError) Error() string {
+	msgs := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		msgs[i] = f.Error()
+	}
+	if e.Err == nil {
+		return "rollback failed: " + strings.Join(msgs, "; ")
+	}
+	return fmt.Sprintf("%s; rollback failed: %s", e.Err, strings.Join(msgs, "; "))
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
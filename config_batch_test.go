@@ -0,0 +1,97 @@
+package venafi_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+func TestConfigBatchRollback(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, map[string][]string{"Description": {"Teams"}})
+	if err := v.Config.WritePolicy(`\VED\Policy\Teams`, config.ClassX509Certificate, "Key Bit Strength", []string{"2048"}, true); err != nil {
+		t.Fatalf("WritePolicy: %v", err)
+	}
+
+	failure := errors.New("step failed")
+	err := venafi.RunConfigBatch(v.Config, func(b *venafi.ConfigBatch) error {
+		if _, err := b.Create(`\VED\Policy\Teams\Web`, config.ClassPolicy, nil); err != nil {
+			return err
+		}
+		if err := b.Write(`\VED\Policy\Teams\Web`, map[string][]string{"Description": {"Web"}}); err != nil {
+			return err
+		}
+		if err := b.Write(`\VED\Policy\Teams`, map[string][]string{"Description": {"Changed"}}); err != nil {
+			return err
+		}
+		if err := b.WritePolicy(`\VED\Policy\Teams`, config.ClassX509Certificate, "Key Bit Strength", []string{"4096"}, false); err != nil {
+			return err
+		}
+		if err := b.ClearAttribute(`\VED\Policy\Teams`, "Description"); err != nil {
+			return err
+		}
+		if b.Len() != 4 {
+			t.Errorf("Len = %d, want 4: changes to a new object need no undo step", b.Len())
+		}
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("RunConfigBatch: got %v, want the error from fn", err)
+	}
+
+	if v.Config.Exists(`\VED\Policy\Teams\Web`) {
+		t.Error("created object was not deleted")
+	}
+	attrs, _ := srv.Object(`\VED\Policy\Teams`)
+	if got := attrs["Description"]; !reflect.DeepEqual(got, []string{"Teams"}) {
+		t.Errorf("Description = %q, want [Teams]", got)
+	}
+	value, err := v.Config.ReadPolicy(`\VED\Policy\Teams`, config.ClassX509Certificate, "Key Bit Strength")
+	if err != nil || !reflect.DeepEqual(value.Values, []string{"2048"}) || !value.Locked {
+		t.Errorf("Key Bit Strength = %+v, %v, want locked [2048]", value, err)
+	}
+}
+
+func TestConfigBatchRollbackFailure(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+
+	failure := errors.New("step failed")
+	err := venafi.RunConfigBatch(v.Config, func(b *venafi.ConfigBatch) error {
+		if _, err := b.Create(`\VED\Policy\Web`, config.ClassPolicy, nil); err != nil {
+			return err
+		}
+		srv.Inject("/vedsdk/Config/Delete", venafitest.ConfigFault(config.InsufficientPrivileges, "denied"))
+		return failure
+	})
+
+	var rollbackErr *venafi.RollbackError
+	if !errors.As(err, &rollbackErr) {
+		t.Fatalf("RunConfigBatch: got %v, want *RollbackError", err)
+	}
+	if !errors.Is(err, failure) || len(rollbackErr.Failures) != 1 {
+		t.Errorf("RollbackError = %v, want the original error and one failure", err)
+	}
+	if !errors.Is(rollbackErr.Failures[0], venafi.ErrInsufficientPrivileges) {
+		t.Errorf("rollback failure = %v, want ErrInsufficientPrivileges", rollbackErr.Failures[0])
+	}
+}
+
+func TestConfigBatchCommit(t *testing.T) {
+	v, _ := newTestClient(t, nil)
+
+	b := venafi.NewConfigBatch(v.Config)
+	if _, err := b.Create(`\VED\Policy\Web`, config.ClassPolicy, nil); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	b.Commit()
+	if err := b.Rollback(); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if !v.Config.Exists(`\VED\Policy\Web`) {
+		t.Error("Rollback after Commit deleted the object")
+	}
+}
@@ -0,0 +1,293 @@
+package venafi_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+// dns returns the DNs of objects in order.
+func dns(objects []venafi.ConfigObject) []string {
+	rv := make([]string, len(objects))
+	for i, obj := range objects {
+		rv[i] = obj.DN
+	}
+	return rv
+}
+
+// addFindTree adds a small tree of folders, devices and applications to srv.
+func addFindTree(srv *venafitest.Server) {
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Teams\web01`, config.ClassDevice, map[string][]string{"Host": {"web01.example.com"}})
+	srv.AddObject(`\VED\Policy\Teams\web01\apache`, "Apache", map[string][]string{"Description": {"web server"}})
+	srv.AddObject(`\VED\Policy\Teams\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Teams\Web\web02`, config.ClassDevice, map[string][]string{"Host": {"web02.example.com"}})
+	srv.AddObject(`\VED\Policy\Teams\Web\db01`, config.ClassDevice, map[string][]string{"Host": {"db01.example.com"}})
+}
+
+func TestFind(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	addFindTree(srv)
+
+	objects, err := v.Config.Find("web0*")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if got, want := dns(objects), []string{`\VED\Policy\Teams\web01`, `\VED\Policy\Teams\Web\web02`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Find by name = %q, want %q", got, want)
+	}
+
+	// With attribute names, the pattern is matched against their values.
+	objects, err = v.Config.Find("*.example.com", "Host")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(objects) != 3 {
+		t.Errorf("Find by Host = %q, want the three devices", dns(objects))
+	}
+	objects, err = v.Config.Find("web*", "Host", "Description")
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if got, want := dns(objects), []string{`\VED\Policy\Teams\web01`, `\VED\Policy\Teams\web01\apache`, `\VED\Policy\Teams\Web\web02`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Find by Host or Description = %q, want %q", got, want)
+	}
+}
+
+func TestFindObjectsOfClass(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	addFindTree(srv)
+	srv.AddObject(`\VED\Policy\Elsewhere`, config.ClassDevice, nil)
+
+	tests := []struct {
+		name      string
+		classes   []string
+		root      string
+		recursive bool
+		pattern   string
+		want      []string
+	}{
+		{"whole tree", []string{config.ClassDevice}, "", false, "", []string{
+			`\VED\Policy\Elsewhere`, `\VED\Policy\Teams\web01`, `\VED\Policy\Teams\Web\db01`, `\VED\Policy\Teams\Web\web02`}},
+		{"several classes", []string{config.ClassDevice, "Apache"}, `\VED\Policy\Teams`, true, "", []string{
+			`\VED\Policy\Teams\web01`, `\VED\Policy\Teams\web01\apache`, `\VED\Policy\Teams\Web\db01`, `\VED\Policy\Teams\Web\web02`}},
+		{"one level", []string{config.ClassDevice, "Apache"}, `\VED\Policy\Teams`, false, "", []string{`\VED\Policy\Teams\web01`}},
+		{"pattern", []string{config.ClassDevice}, `\VED\Policy\Teams`, true, "web*", []string{
+			`\VED\Policy\Teams\web01`, `\VED\Policy\Teams\Web\web02`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := v.Config.FindObjectsOfClass(tt.classes, tt.root, tt.recursive, tt.pattern)
+			if err != nil {
+				t.Fatalf("FindObjectsOfClass: %v", err)
+			}
+			if got := dns(objects); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := v.Config.FindObjectsOfClass([]string{config.ClassDevice}, `\VED\Policy\Missing`, true, ""); !errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("FindObjectsOfClass under a missing DN: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestFindContainers(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	addFindTree(srv)
+	srv.AddObject(`\VED\Policy\Teams\Web\Internal`, config.ClassPolicy, nil)
+
+	// Devices are containers too; the Apache application below web01 is not.
+
+	objects, err := v.Config.FindContainers(`\VED\Policy\Teams`, false)
+	if err != nil {
+		t.Fatalf("FindContainers: %v", err)
+	}
+	if got, want := dns(objects), []string{`\VED\Policy\Teams\Web`, `\VED\Policy\Teams\web01`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindContainers = %q, want %q", got, want)
+	}
+
+	objects, err = v.Config.FindContainers(`\VED\Policy\Teams`, true)
+	if err != nil {
+		t.Fatalf("FindContainers: %v", err)
+	}
+	if got, want := dns(objects), []string{`\VED\Policy\Teams\Web`, `\VED\Policy\Teams\web01`, `\VED\Policy\Teams\Web\db01`,
+		`\VED\Policy\Teams\Web\Internal`, `\VED\Policy\Teams\Web\web02`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("recursive FindContainers = %q, want %q", got, want)
+	}
+}
+
+func TestFindPolicy(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	addFindTree(srv)
+	if err := v.Config.WritePolicy(`\VED\Policy\Teams`, config.ClassX509Certificate, "Key Bit Strength",
+		[]string{"2048"}, false); err != nil {
+		t.Fatalf("WritePolicy: %v", err)
+	}
+
+	value, err := v.Config.FindPolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, "Key Bit Strength")
+	if err != nil {
+		t.Fatalf("FindPolicy: %v", err)
+	}
+	if value.PolicyDN != `\VED\Policy\Teams` || value.Locked || !reflect.DeepEqual(value.Values, []string{"2048"}) {
+		t.Errorf("FindPolicy = %+v, want 2048 inherited from Teams", value)
+	}
+
+	if _, err := v.Config.FindPolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, "Organization"); !errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("FindPolicy of an unset attribute: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestRenameObject(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	web := srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Web\web01`, config.ClassX509Certificate, nil)
+
+	obj, err := v.Config.RenameObject(`\VED\Policy\Web`, `\VED\Policy\Frontend`)
+	if err != nil {
+		t.Fatalf("RenameObject: %v", err)
+	}
+	if obj.DN != `\VED\Policy\Frontend` || obj.Name != "Frontend" || !strings.EqualFold(obj.GUID, web.GUID) {
+		t.Errorf("RenameObject = %+v, want Web under its new name", obj)
+	}
+	if _, ok := srv.Object(`\VED\Policy\Frontend\web01`); !ok {
+		t.Error("web01 did not move with its folder")
+	}
+	if _, err := v.Config.IsValid(`\VED\Policy\Web`, ""); !errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("IsValid of the old DN: got %v, want ErrNotFound", err)
+	}
+
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	if _, err := v.Config.RenameObject(`\VED\Policy\Frontend`, `\VED\Policy\Web`); !errors.Is(err, venafi.ErrAlreadyExists) {
+		t.Errorf("RenameObject onto an existing DN: got %v, want ErrAlreadyExists", err)
+	}
+}
+
+func TestMove(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Web\web01`, config.ClassX509Certificate, nil)
+	srv.AddObject(`\VED\Policy\Devices`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Devices\app01`, config.ClassDevice, nil)
+
+	obj, err := v.Config.Move(`\VED\Policy\Web\web01`, `\VED\Policy\Devices\app01`)
+	if err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if obj.DN != `\VED\Policy\Devices\app01\web01` {
+		t.Errorf("Move = %s, want web01 below app01", obj.DN)
+	}
+
+	// A policy folder may not live inside a device.
+	_, err = v.Config.Move(`\VED\Policy\Web`, `\VED\Policy\Devices\app01`)
+	var configErr *venafi.ConfigServiceError
+	if !errors.As(err, &configErr) || configErr.Result != config.ObjectInvalidContainment {
+		t.Errorf("Move into a device: got %v, want ObjectInvalidContainment", err)
+	}
+	if _, ok := srv.Object(`\VED\Policy\Web`); !ok {
+		t.Error("Web was moved although its new parent cannot contain it")
+	}
+}
+
+func TestRenameObjectClearsLookupCache(t *testing.T) {
+	v, srv, transport := newCountingClient(t)
+	web := srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Web\web01`, config.ClassX509Certificate, nil)
+	v.EnableLookupCache(10, 0)
+
+	for _, dn := range []string{`\VED\Policy\Web`, `\VED\Policy\Web\web01`} {
+		if _, err := v.Config.DnToGuid(dn); err != nil {
+			t.Fatalf("DnToGuid(%s): %v", dn, err)
+		}
+	}
+	if _, err := v.Config.RenameObject(`\VED\Policy\Web`, `\VED\Policy\Frontend`); err != nil {
+		t.Fatalf("RenameObject: %v", err)
+	}
+
+	// The old DNs of the object and everything below it are looked up again.
+	before := transport.count("/vedsdk/Config/DnToGuid")
+	for _, dn := range []string{`\VED\Policy\Web`, `\VED\Policy\Web\web01`} {
+		if _, err := v.Config.DnToGuid(dn); !errors.Is(err, venafi.ErrNotFound) {
+			t.Errorf("DnToGuid(%s) after the rename: got %v, want ErrNotFound", dn, err)
+		}
+	}
+	if n := transport.count("/vedsdk/Config/DnToGuid") - before; n != 2 {
+		t.Errorf("DnToGuid made %d requests for the old DNs, want 2", n)
+	}
+	if dn, err := v.Config.GuidToDn(web.GUID); err != nil || dn != `\VED\Policy\Frontend` {
+		t.Errorf("GuidToDn after the rename = %q, %v, want the new DN", dn, err)
+	}
+}
+
+func TestPolicyValues(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Teams\Web`, config.ClassPolicy, nil)
+
+	for _, org := range []string{"Example", "Example Ltd"} {
+		if err := v.Config.AddPolicyValue(`\VED\Policy\Teams`, config.ClassX509Certificate, "Organization", org, false); err != nil {
+			t.Fatalf("AddPolicyValue: %v", err)
+		}
+	}
+	value, err := v.Config.ReadPolicy(`\VED\Policy\Teams`, config.ClassX509Certificate, "Organization")
+	if err != nil || !reflect.DeepEqual(value.Values, []string{"Example", "Example Ltd"}) || value.Locked {
+		t.Errorf("ReadPolicy = %+v, %v, want both values unlocked", value, err)
+	}
+
+	// Clearing the attribute on Web lets Teams show through again.
+	if err := v.Config.WritePolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, "Organization",
+		[]string{"Web"}, false); err != nil {
+		t.Fatalf("WritePolicy: %v", err)
+	}
+	if err := v.Config.ClearPolicyAttribute(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, "Organization"); err != nil {
+		t.Fatalf("ClearPolicyAttribute: %v", err)
+	}
+	value, err = v.Config.FindPolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, "Organization")
+	if err != nil || value.PolicyDN != `\VED\Policy\Teams` {
+		t.Errorf("FindPolicy after ClearPolicyAttribute = %+v, %v, want the Teams value", value, err)
+	}
+}
+
+func TestReadEffectivePolicy(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Teams\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Teams\Web\web01`, config.ClassX509Certificate, nil)
+	srv.AddObject(`\VED\Policy\Teams\Web\web02`, config.ClassX509Certificate,
+		map[string][]string{"Organization": {"Web02 Inc"}})
+	if err := v.Config.WritePolicy(`\VED\Policy\Teams`, config.ClassX509Certificate, "Organization",
+		[]string{"Example"}, false); err != nil {
+		t.Fatalf("WritePolicy: %v", err)
+	}
+
+	check := func(dn string, want venafi.PolicyValue) {
+		t.Helper()
+		got, err := v.Config.ReadEffectivePolicy(dn, "Organization")
+		if err != nil {
+			t.Fatalf("ReadEffectivePolicy(%s): %v", dn, err)
+		}
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("ReadEffectivePolicy(%s) = %+v, want %+v", dn, *got, want)
+		}
+	}
+
+	// An unlocked value is inherited from the folder it was set on, and a
+	// value on the object itself wins over it.
+	check(`\VED\Policy\Teams\Web\web01`, venafi.PolicyValue{PolicyDN: `\VED\Policy\Teams`, Values: []string{"Example"}})
+	check(`\VED\Policy\Teams\Web\web02`, venafi.PolicyValue{PolicyDN: `\VED\Policy\Teams\Web\web02`, Values: []string{"Web02 Inc"}})
+
+	// Once locked, the folder's value wins and the object's own value is
+	// reported as overridden.
+	if err := v.Config.WritePolicy(`\VED\Policy\Teams`, config.ClassX509Certificate, "Organization",
+		[]string{"Example"}, true); err != nil {
+		t.Fatalf("WritePolicy: %v", err)
+	}
+	check(`\VED\Policy\Teams\Web\web01`, venafi.PolicyValue{PolicyDN: `\VED\Policy\Teams`, Locked: true, Values: []string{"Example"}})
+	check(`\VED\Policy\Teams\Web\web02`, venafi.PolicyValue{PolicyDN: `\VED\Policy\Teams`, Locked: true, Overridden: true,
+		Values: []string{"Example"}})
+}
@@ -0,0 +1,360 @@
+package venafi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tppconfig "github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+// DN is the value of a DN-syntax attribute, as returned by ReadTyped.
+type DN string
+
+// schemaCache holds the attribute syntaxes read from the TPP schema. They are
+// fetched the first time they are needed and kept for the life of the client.
+type schemaCache struct {
+	mu       sync.Mutex
+	syntaxes map[string]tppconfig.AttributeSyntax
+}
+
+// timeLayout is the format Time-syntax values are written in. decodeTime
+// accepts other forms too when reading.
+const timeLayout = time.RFC3339
+
+// timeLayouts are the layouts tried when reading Time-syntax values. Values
+// without a zone are taken as UTC.
+var timeLayouts = []string{timeLayout, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "1/2/2006 3:04:05 PM"}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// AttributeSyntax returns the schema syntax of the named attribute.
+func (s *ConfigService) AttributeSyntax(name string) (tppconfig.AttributeSyntax, error) {
+	cache := &s.client.schema
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.syntaxes == nil {
+		defs, err := s.client.Schema.Attributes()
+		if err != nil {
+			return 0, err
+		}
+
+		cache.syntaxes = make(map[string]tppconfig.AttributeSyntax, len(defs))
+		for _, def := range defs {
+			cache.syntaxes[strings.ToLower(def.Name)] = def.Syntax
+		}
+	}
+
+	syntax, ok := cache.syntaxes[strings.ToLower(name)]
+	if !ok {
+		return 0, newConfigError(nil, tppconfig.AttributeDoesNotExist, fmt.Sprintf("attribute %q is not in the schema", name))
+	}
+	return syntax, nil
+}
+
+// ReadTyped reads an attribute and decodes its values according to the
+// attribute's schema syntax. Each value is a bool, int, time.Time, DN or
+// string. Time values may be in RFC 3339 form, in TPP's "/Date(ms)/" form, a
+// count of Unix seconds, or one of the zoneless layouts in timeLayouts, which
+// are taken as UTC.
+func (s *ConfigService) ReadTyped(objectDN string, name string) ([]interface{}, error) {
+	syntax, err := s.AttributeSyntax(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := readValues(s, objectDN, name, syntax)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make([]interface{}, len(values))
+	for i, v := range values {
+		if rv[i], err = decodeValue(syntax, v); err != nil {
+			return nil, fmt.Errorf("error decoding %q: %s", name, err)
+		}
+	}
+	return rv, nil
+}
+
+// ReadStruct reads the attributes of objectDN through config into the struct
+// pointed to by v. Fields are matched by a `tpp:"Attribute Name"` tag and
+// values are decoded according to the attribute's schema syntax, as with
+// ReadTyped. A field must be able to hold the decoded type: bool for Boolean,
+// any int type for Integer, time.Time for Time, and string or DN for the rest,
+// or a slice of one of those. Fields whose attribute is not set are left alone.
+// DN-syntax attributes are read with ReadDn, as ReadTyped does.
+func ReadStruct(config ConfigAPI, objectDN string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ReadStruct needs a pointer to a struct, got %T", v)
+	}
+	rv = rv.Elem()
+
+	attrs, err := config.ReadAll(objectDN)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < rv.NumField(); i++ {
+		name, _, ok := parseTag(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+		values := lookupFold(attrs, name)
+		if len(values) == 0 {
+			continue
+		}
+		syntax, err := config.AttributeSyntax(name)
+		if err != nil {
+			return err
+		}
+		if syntax == tppconfig.SyntaxDN {
+			if values, err = readValues(config, objectDN, name, syntax); err != nil {
+				return err
+			}
+		}
+		if err := setField(rv.Field(i), syntax, values); err != nil {
+			return fmt.Errorf("error decoding %q: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// WriteStruct writes the tagged fields of v, a struct or pointer to a struct,
+// to objectDN through config, encoding them according to each attribute's
+// schema syntax. Fields tagged with omitempty are skipped when they hold their
+// zero value. DN-syntax attributes are written with WriteDn, everything else
+// in a single Write.
+func WriteStruct(config ConfigAPI, objectDN string, v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("WriteStruct needs a struct, got %T", v)
+	}
+
+	attrs := make(map[string][]string)
+	dns := make(map[string][]string)
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		name, omitEmpty, ok := parseTag(field)
+		if !ok {
+			continue
+		}
+		if omitEmpty && rv.Field(i).IsZero() {
+			continue
+		}
+		syntax, err := config.AttributeSyntax(name)
+		if err != nil {
+			return err
+		}
+		values, err := encodeField(rv.Field(i), syntax)
+		if err != nil {
+			return fmt.Errorf("error encoding %q: %s", name, err)
+		}
+		if syntax == tppconfig.SyntaxDN {
+			dns[name] = values
+		} else {
+			attrs[name] = values
+		}
+	}
+
+	if len(attrs) > 0 {
+		if err := config.Write(objectDN, attrs); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(dns) {
+		if err := config.WriteDn(objectDN, name, dns[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// readValues reads one attribute, using ReadDn for DN-syntax attributes so
+// that ReadTyped and ReadStruct see the same values.
+func readValues(config ConfigAPI, objectDN string, name string, syntax tppconfig.AttributeSyntax) ([]string, error) {
+	if syntax == tppconfig.SyntaxDN {
+		return config.ReadDn(objectDN, name)
+	}
+	return config.Read(objectDN, name)
+}
+
+func decodeValue(syntax tppconfig.AttributeSyntax, value string) (interface{}, error) {
+	switch syntax {
+	case tppconfig.SyntaxBoolean:
+		return decodeBool(value)
+	case tppconfig.SyntaxInteger:
+		return strconv.Atoi(value)
+	case tppconfig.SyntaxTime:
+		return decodeTime(value)
+	case tppconfig.SyntaxDN:
+		return DN(value), nil
+	}
+	return value, nil
+}
+
+// decodeBool accepts TPP's "1" and "0" as well as the usual Go spellings.
+func decodeBool(value string) (bool, error) {
+	switch value {
+	case "1":
+		return true, nil
+	case "0", "":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// decodeTime parses a value in one of timeLayouts, TPP's "/Date(ms)/" form or
+// Unix seconds.
+func decodeTime(value string) (time.Time, error) {
+	if strings.HasPrefix(value, "/Date(") && strings.HasSuffix(value, ")/") {
+		ms, err := strconv.ParseInt(value[len("/Date("):len(value)-len(")/")], 10, 64)
+		if err == nil {
+			return time.Unix(0, ms*int64(time.Millisecond)).UTC(), nil
+		}
+	}
+	if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
+
+func parseTag(field reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	tag, ok := field.Tag.Lookup("tpp")
+	if !ok || tag == "" || tag == "-" || field.PkgPath != "" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, true
+}
+
+func lookupFold(attrs map[string][]string, name string) []string {
+	if values, ok := attrs[name]; ok {
+		return values
+	}
+	for k, values := range attrs {
+		if strings.EqualFold(k, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, syntax tppconfig.AttributeSyntax, values []string) error {
+	if field.Kind() != reflect.Slice {
+		return setScalar(field, syntax, values[0])
+	}
+	slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+	for i, v := range values {
+		if err := setScalar(slice.Index(i), syntax, v); err != nil {
+			return err
+		}
+	}
+	field.Set(slice)
+	return nil
+}
+
+// setScalar decodes value according to syntax and stores it in field, which
+// must be able to hold the decoded type.
+func setScalar(field reflect.Value, syntax tppconfig.AttributeSyntax, value string) error {
+	decoded, err := decodeValue(syntax, value)
+	if err != nil {
+		return err
+	}
+
+	switch v := decoded.(type) {
+	case bool:
+		if field.Kind() == reflect.Bool {
+			field.SetBool(v)
+			return nil
+		}
+	case int:
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if field.OverflowInt(int64(v)) {
+				return fmt.Errorf("%d overflows %s", v, field.Type())
+			}
+			field.SetInt(int64(v))
+			return nil
+		}
+	case time.Time:
+		if field.Type() == timeType {
+			field.Set(reflect.ValueOf(v))
+			return nil
+		}
+	case DN:
+		if field.Kind() == reflect.String {
+			field.SetString(string(v))
+			return nil
+		}
+	case string:
+		if field.Kind() == reflect.String {
+			field.SetString(v)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot store a %T in a field of type %s", decoded, field.Type())
+}
+
+func encodeField(field reflect.Value, syntax tppconfig.AttributeSyntax) ([]string, error) {
+	if field.Kind() != reflect.Slice {
+		value, err := encodeScalar(field, syntax)
+		if err != nil {
+			return nil, err
+		}
+		return []string{value}, nil
+	}
+	rv := make([]string, field.Len())
+	for i := range rv {
+		value, err := encodeScalar(field.Index(i), syntax)
+		if err != nil {
+			return nil, err
+		}
+		rv[i] = value
+	}
+	return rv, nil
+}
+
+// encodeScalar encodes field as a value of the given syntax, the inverse of
+// setScalar.
+func encodeScalar(field reflect.Value, syntax tppconfig.AttributeSyntax) (string, error) {
+	switch syntax {
+	case tppconfig.SyntaxBoolean:
+		if field.Kind() == reflect.Bool {
+			return strconv.Itoa(btoi(field.Bool())), nil
+		}
+	case tppconfig.SyntaxInteger:
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(field.Int(), 10), nil
+		}
+	case tppconfig.SyntaxTime:
+		if field.Type() == timeType {
+			return field.Interface().(time.Time).UTC().Format(timeLayout), nil
+		}
+	default:
+		if field.Kind() == reflect.String {
+			return field.String(), nil
+		}
+	}
+	return "", fmt.Errorf("cannot encode a field of type %s as syntax %d", field.Type(), syntax)
+}
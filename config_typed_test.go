@@ -0,0 +1,147 @@
+package venafi_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/mocks"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+type typedFolder struct {
+	Description string      `tpp:"Description,omitempty"`
+	Disabled    bool        `tpp:"Disabled"`
+	KeySize     int         `tpp:"Key Bit Strength"`
+	RenewedOn   time.Time   `tpp:"Last Renewed On"`
+	Contacts    []venafi.DN `tpp:"Contact"`
+}
+
+func TestWriteStructRoundTrip(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, "Policy", nil)
+	srv.AddObject(`\VED\Identity\alice`, "User", nil)
+	srv.AddObject(`\VED\Identity\bob`, "User", nil)
+
+	want := typedFolder{
+		Description: "Web team",
+		Disabled:    true,
+		KeySize:     2048,
+		RenewedOn:   time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		Contacts:    []venafi.DN{`\VED\Identity\alice`, `\VED\Identity\bob`},
+	}
+	if err := venafi.WriteStruct(v.Config, `\VED\Policy\Teams`, want); err != nil {
+		t.Fatalf("WriteStruct: %v", err)
+	}
+
+	attrs, _ := srv.Object(`\VED\Policy\Teams`)
+	for name, value := range map[string]string{
+		"Disabled":         "1",
+		"Key Bit Strength": "2048",
+		"Last Renewed On":  "2024-03-01T12:30:00Z",
+	} {
+		if got := attrs[name]; len(got) != 1 || got[0] != value {
+			t.Errorf("%s = %q, want [%q]", name, got, value)
+		}
+	}
+
+	var got typedFolder
+	if err := venafi.ReadStruct(v.Config, `\VED\Policy\Teams`, &got); err != nil {
+		t.Fatalf("ReadStruct: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadStruct = %+v, want %+v", got, want)
+	}
+
+	values, err := v.Config.ReadTyped(`\VED\Policy\Teams`, "Last Renewed On")
+	if err != nil || len(values) != 1 || !values[0].(time.Time).Equal(want.RenewedOn) {
+		t.Errorf("ReadTyped = %v, %v, want [%v]", values, err, want.RenewedOn)
+	}
+}
+
+func TestWriteStructChecksSyntax(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, "Policy", nil)
+
+	// Key Bit Strength has Integer syntax, so a string field cannot hold it.
+	bad := struct {
+		KeySize string `tpp:"Key Bit Strength"`
+	}{"2048"}
+	if err := venafi.WriteStruct(v.Config, `\VED\Policy\Teams`, bad); err == nil {
+		t.Error("WriteStruct of a string into an Integer attribute succeeded")
+	}
+}
+
+func TestReadStructReadsDNsLikeReadTyped(t *testing.T) {
+	// ReadAll may return a DN attribute in a different form than ReadDn; the
+	// ReadDn values are the ones used.
+	cfg := &mocks.ConfigAPI{
+		ReadAllFunc: func(string) (map[string][]string, error) {
+			return map[string][]string{"Contact": {"local:{alice}"}, "Description": {"Web team"}}, nil
+		},
+		ReadDnFunc: func(string, string) ([]string, error) {
+			return []string{`\VED\Identity\alice`}, nil
+		},
+		AttributeSyntaxFunc: func(name string) (config.AttributeSyntax, error) {
+			if name == "Contact" {
+				return config.SyntaxDN, nil
+			}
+			return config.SyntaxString, nil
+		},
+	}
+
+	var got typedFolder
+	if err := venafi.ReadStruct(cfg, `\VED\Policy\Teams`, &got); err != nil {
+		t.Fatalf("ReadStruct: %v", err)
+	}
+	if want := []venafi.DN{`\VED\Identity\alice`}; !reflect.DeepEqual(got.Contacts, want) || got.Description != "Web team" {
+		t.Errorf("ReadStruct = %+v, want Contacts %q", got, want)
+	}
+	if calls := cfg.ReadDnCalls(); len(calls) != 1 || calls[0].Name != "Contact" {
+		t.Errorf("ReadDn calls = %+v, want one for Contact", calls)
+	}
+
+	// Against the fake server both read the same DNs.
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, map[string][]string{"Contact": {`\VED\Identity\alice`}})
+	got = typedFolder{}
+	if err := venafi.ReadStruct(v.Config, `\VED\Policy\Teams`, &got); err != nil {
+		t.Fatalf("ReadStruct: %v", err)
+	}
+	values, err := v.Config.ReadTyped(`\VED\Policy\Teams`, "Contact")
+	if err != nil || len(values) != 1 || values[0] != got.Contacts[0] {
+		t.Errorf("ReadTyped = %v, %v, want %q as from ReadStruct", values, err, got.Contacts)
+	}
+}
+
+func TestReadTypedTimeFormats(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	want := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, map[string][]string{
+		"Last Renewed On": {
+			"2024-03-01T12:30:00Z",
+			"2024-03-01T14:30:00+02:00",
+			"/Date(1709296200000)/",
+			"1709296200",
+			"2024-03-01T12:30:00",
+			"2024-03-01 12:30:00",
+			"3/1/2024 12:30:00 PM",
+		},
+		"Created On": {"yesterday"},
+	})
+
+	values, err := v.Config.ReadTyped(`\VED\Policy\Teams`, "Last Renewed On")
+	if err != nil {
+		t.Fatalf("ReadTyped: %v", err)
+	}
+	for i, value := range values {
+		if got, ok := value.(time.Time); !ok || !got.Equal(want) {
+			t.Errorf("value %d = %v, want %v", i, value, want)
+		}
+	}
+
+	if _, err := v.Config.ReadTyped(`\VED\Policy\Teams`, "Created On"); err == nil {
+		t.Error("ReadTyped of an unrecognized time succeeded")
+	}
+}
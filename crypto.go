@@ -0,0 +1,43 @@
+package venafi
+
+import (
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+type CryptoService struct {
+	client *Client
+}
+
+// AvailableKeys returns the protection keys that can encrypt vault entries,
+// including any configured HSM keys.
+func (s *CryptoService) AvailableKeys() ([]secret_store.ProtectionKey, error) {
+	type Output struct {
+		Keynames []secret_store.ProtectionKey
+	}
+
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("GET", "/vedsdk/Crypto/AvailableKeys", nil, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Keynames, nil
+}
+
+// DefaultKey returns the protection key new vault entries are encrypted with
+// when none is given.
+func (s *CryptoService) DefaultKey() (secret_store.ProtectionKey, error) {
+	type Output struct {
+		DefaultKey secret_store.ProtectionKey
+	}
+
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("GET", "/vedsdk/Crypto/GetDefaultKey", nil, &output)
+	if err != nil {
+		return "", err
+	}
+
+	return output.DefaultKey, nil
+}
@@ -0,0 +1,237 @@
+package venafi
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DriftKind identifies how an object or attribute differs between the two
+// trees being compared.
+type DriftKind string
+
+//noinspection GoUnusedConst
+const (
+	// DriftAdded means the target has an object or value the source lacks.
+	DriftAdded DriftKind = "added"
+	// DriftRemoved means the source has an object or value the target lacks.
+	DriftRemoved DriftKind = "removed"
+	// DriftChanged means both have the attribute but with different values.
+	DriftChanged DriftKind = "changed"
+)
+
+// Drift is a single difference. Attribute is empty when a whole object was
+// added or removed. Policy values are reported with PolicyClass set.
+type Drift struct {
+	Kind         DriftKind
+	RelativeDN   string
+	Class        string
+	Attribute    string   `json:",omitempty"`
+	PolicyClass  string   `json:",omitempty"`
+	SourceValues []string `json:",omitempty"`
+	TargetValues []string `json:",omitempty"`
+}
+
+func (d Drift) String() string {
+	where := d.RelativeDN
+	if where == "" {
+		where = `\`
+	}
+	name := d.Attribute
+	if d.PolicyClass != "" {
+		name = fmt.Sprintf("policy [%s] %s", d.PolicyClass, d.Attribute)
+	}
+
+	switch {
+	case d.Attribute == "" && d.Kind == DriftAdded:
+		return fmt.Sprintf("+ %s (%s)", where, d.Class)
+	case d.Attribute == "" && d.Kind == DriftRemoved:
+		return fmt.Sprintf("- %s (%s)", where, d.Class)
+	case d.Kind == DriftAdded:
+		return fmt.Sprintf("+ %s %s: %s", where, name, formatPolicyValues(d.TargetValues, false))
+	case d.Kind == DriftRemoved:
+		return fmt.Sprintf("- %s %s: %s", where, name, formatPolicyValues(d.SourceValues, false))
+	}
+	return fmt.Sprintf("~ %s %s: %s -> %s", where, name,
+		formatPolicyValues(d.SourceValues, false), formatPolicyValues(d.TargetValues, false))
+}
+
+// DriftOptions controls CompareTrees. IgnoreAttributes holds case-insensitive
+// wildcard patterns of attribute names whose values are not compared.
+type DriftOptions struct {
+	Export           ExportOptions
+	IgnoreAttributes []string
+}
+
+// DefaultDriftOptions ignores attributes that change on every edit or differ
+// between instances by design.
+var DefaultDriftOptions = DriftOptions{
+	Export: ExportOptions{
+		PolicyAttributes: DefaultExportOptions.PolicyAttributes,
+	},
+	IgnoreAttributes: []string{
+		"Revision", "*Date*", "*Time*", "*Timestamp*", "Last *", "Created *", "Modified *",
+		"Certificate Vault Id", "*Guid*",
+	},
+}
+
+// DriftReport lists the differences between two subtrees. Drifts are sorted
+// by RelativeDN.
+type DriftReport struct {
+	SourceRoot string
+	TargetRoot string
+	Drifts     []Drift
+}
+
+// Empty reports whether the two trees matched.
+func (r *DriftReport) Empty() bool {
+	return len(r.Drifts) == 0
+}
+
+func (r *DriftReport) String() string {
+	if r.Empty() {
+		return fmt.Sprintf("%s and %s match.", r.SourceRoot, r.TargetRoot)
+	}
+	lines := make([]string, len(r.Drifts))
+	for i, d := range r.Drifts {
+		lines[i] = d.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CompareTrees walks sourceRoot through source and targetRoot through target,
+// which may be the Config services of two different instances, and reports
+// objects that exist on only one side and attribute or policy values that
+// differ. DN values under sourceRoot are mapped to targetRoot before they are
+// compared. A nil opts uses DefaultDriftOptions.
+func CompareTrees(source ConfigAPI, sourceRoot string, target ConfigAPI, targetRoot string, opts *DriftOptions) (*DriftReport, error) {
+	if opts == nil {
+		opts = &DefaultDriftOptions
+	}
+
+	src, err := ExportPolicy(source, sourceRoot, &opts.Export)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := ExportPolicy(target, targetRoot, &opts.Export)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReport{SourceRoot: src.RootDN, TargetRoot: dst.RootDN, Drifts: make([]Drift, 0)}
+
+	srcObjects := indexExport(src)
+	dstObjects := indexExport(dst)
+
+	for key, s := range srcObjects {
+		d, ok := dstObjects[key]
+		if !ok {
+			report.Drifts = append(report.Drifts, Drift{Kind: DriftRemoved, RelativeDN: s.RelativeDN, Class: s.Class})
+			continue
+		}
+
+		srcAttrs := make(map[string][]string, len(s.Attributes))
+		for name, values := range s.Attributes {
+			srcAttrs[name] = remapDNs(values, src.RootDN, dst.RootDN)
+		}
+		report.Drifts = append(report.Drifts, diffValues(s, "", srcAttrs, d.Attributes, opts)...)
+
+		srcPolicies := policiesByClass(s.Policies)
+		dstPolicies := policiesByClass(d.Policies)
+		for class, values := range srcPolicies {
+			for name := range values {
+				values[name] = remapDNs(values[name], src.RootDN, dst.RootDN)
+			}
+			report.Drifts = append(report.Drifts, diffValues(s, class, values, dstPolicies[class], opts)...)
+		}
+		for class, values := range dstPolicies {
+			if _, ok := srcPolicies[class]; !ok {
+				report.Drifts = append(report.Drifts, diffValues(s, class, nil, values, opts)...)
+			}
+		}
+	}
+	for key, d := range dstObjects {
+		if _, ok := srcObjects[key]; !ok {
+			report.Drifts = append(report.Drifts, Drift{Kind: DriftAdded, RelativeDN: d.RelativeDN, Class: d.Class})
+		}
+	}
+
+	sort.SliceStable(report.Drifts, func(i, j int) bool {
+		a, b := report.Drifts[i], report.Drifts[j]
+		if !strings.EqualFold(a.RelativeDN, b.RelativeDN) {
+			return strings.ToLower(a.RelativeDN) < strings.ToLower(b.RelativeDN)
+		}
+		if a.PolicyClass != b.PolicyClass {
+			return a.PolicyClass < b.PolicyClass
+		}
+		return a.Attribute < b.Attribute
+	})
+
+	return report, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+func indexExport(e *PolicyExport) map[string]ExportedObject {
+	rv := make(map[string]ExportedObject, len(e.Objects))
+	for _, obj := range e.Objects {
+		rv[strings.ToLower(obj.RelativeDN)] = obj
+	}
+	return rv
+}
+
+// policiesByClass groups policy values by class and attribute. A locked value
+// gets a trailing "(locked)" marker so that lock changes show up as drift.
+func policiesByClass(policies []ExportedPolicy) map[string]map[string][]string {
+	rv := make(map[string]map[string][]string)
+	for _, p := range policies {
+		if rv[p.Class] == nil {
+			rv[p.Class] = make(map[string][]string)
+		}
+		values := p.Values
+		if p.Locked {
+			values = append(append([]string(nil), values...), "(locked)")
+		}
+		rv[p.Class][p.Attribute] = values
+	}
+	return rv
+}
+
+// diffValues compares two attribute maps belonging to obj.
+func diffValues(obj ExportedObject, policyClass string, src map[string][]string, dst map[string][]string, opts *DriftOptions) []Drift {
+	rv := make([]Drift, 0)
+	for name, s := range src {
+		if ignoredAttribute(name, opts.IgnoreAttributes) {
+			continue
+		}
+		d, ok := dst[name]
+		switch {
+		case !ok:
+			rv = append(rv, Drift{Kind: DriftRemoved, RelativeDN: obj.RelativeDN, Class: obj.Class,
+				Attribute: name, PolicyClass: policyClass, SourceValues: s})
+		case !sameValues(s, d):
+			rv = append(rv, Drift{Kind: DriftChanged, RelativeDN: obj.RelativeDN, Class: obj.Class,
+				Attribute: name, PolicyClass: policyClass, SourceValues: s, TargetValues: d})
+		}
+	}
+	for name, d := range dst {
+		if _, ok := src[name]; ok || ignoredAttribute(name, opts.IgnoreAttributes) {
+			continue
+		}
+		rv = append(rv, Drift{Kind: DriftAdded, RelativeDN: obj.RelativeDN, Class: obj.Class,
+			Attribute: name, PolicyClass: policyClass, TargetValues: d})
+	}
+	return rv
+}
+
+func ignoredAttribute(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package venafi_test
+
+import (
+	"strings"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+// driftLines returns the report as one line per drift.
+func driftLines(r *venafi.DriftReport) []string {
+	lines := make([]string, len(r.Drifts))
+	for i, d := range r.Drifts {
+		lines[i] = d.String()
+	}
+	return lines
+}
+
+func TestCompareTrees(t *testing.T) {
+	staging, stagingSrv := newTestClient(t, nil)
+	prod, prodSrv := newTestClient(t, nil)
+
+	for name, srv := range map[string]*venafitest.Server{"staging": stagingSrv, "prod": prodSrv} {
+		srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, nil)
+		srv.AddObject(`\VED\Policy\Teams\Web`, config.ClassPolicy, map[string][]string{
+			"Description":   {"Web team"},
+			"Last Modified": {name},
+		})
+	}
+	stagingSrv.AddObject(`\VED\Policy\Teams\Old`, config.ClassPolicy, nil)
+	prodSrv.AddObject(`\VED\Policy\Teams\New`, config.ClassPolicy, nil)
+	prodSrv.AddObject(`\VED\Policy\Teams\Db`, config.ClassPolicy, map[string][]string{"Description": {"Databases"}})
+	stagingSrv.AddObject(`\VED\Policy\Teams\Db`, config.ClassPolicy, map[string][]string{"Description": {"DB team"}})
+
+	// Same values, but locked only on prod.
+	if err := staging.Config.WritePolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, "Key Bit Strength",
+		[]string{"2048"}, false); err != nil {
+		t.Fatalf("WritePolicy: %v", err)
+	}
+	if err := prod.Config.WritePolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, "Key Bit Strength",
+		[]string{"2048"}, true); err != nil {
+		t.Fatalf("WritePolicy: %v", err)
+	}
+
+	report, err := venafi.CompareTrees(staging.Config, `\VED\Policy\Teams`, prod.Config, `\VED\Policy\Teams`, nil)
+	if err != nil {
+		t.Fatalf("CompareTrees: %v", err)
+	}
+	want := []string{
+		`~ \Db Description: [DB team] -> [Databases]`,
+		`+ \New (Policy)`,
+		`- \Old (Policy)`,
+		`~ \Web policy [X509 Certificate] Key Bit Strength: [2048] -> [2048, (locked)]`,
+	}
+	if got := driftLines(report); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("drift report:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if report.Drifts[1].Kind != venafi.DriftAdded || report.Drifts[2].Kind != venafi.DriftRemoved ||
+		report.Drifts[0].Kind != venafi.DriftChanged {
+		t.Errorf("drift kinds = %s, %s, %s", report.Drifts[0].Kind, report.Drifts[1].Kind, report.Drifts[2].Kind)
+	}
+
+	// Replacing the default ignore patterns brings back the Last Modified
+	// difference and hides Description.
+	opts := venafi.DefaultDriftOptions
+	opts.IgnoreAttributes = []string{"desc*"}
+	report, err = venafi.CompareTrees(staging.Config, `\VED\Policy\Teams`, prod.Config, `\VED\Policy\Teams`, &opts)
+	if err != nil {
+		t.Fatalf("CompareTrees: %v", err)
+	}
+	for _, line := range driftLines(report) {
+		if strings.Contains(line, "Description") {
+			t.Errorf("ignored attribute reported: %s", line)
+		}
+	}
+	if got := driftLines(report); !containsLine(got, `~ \Web Last Modified: [staging] -> [prod]`) {
+		t.Errorf("drift report does not show Last Modified:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestCompareTreesUnderDifferentRoots(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	for _, root := range []string{`\VED\Policy\Staging`, `\VED\Policy\Prod`} {
+		srv.AddObject(root, config.ClassPolicy, nil)
+		srv.AddObject(root+`\CA`, config.ClassSelfSignedCA, nil)
+		srv.AddObject(root+`\Web`, config.ClassPolicy, map[string][]string{"Certificate Authority": {root + `\CA`}})
+	}
+
+	// DNs under each root are compared relative to it.
+	report, err := venafi.CompareTrees(v.Config, `\VED\Policy\Staging`, v.Config, `\VED\Policy\Prod`, nil)
+	if err != nil {
+		t.Fatalf("CompareTrees: %v", err)
+	}
+	if !report.Empty() {
+		t.Errorf("trees differ:\n%s", report)
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
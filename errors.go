@@ -0,0 +1,164 @@
+package venafi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+// Sentinel conditions that can be tested for with errors.Is on any error
+// returned by the client.
+var (
+	ErrNotFound               = errors.New("venafi: object not found")
+	ErrAlreadyExists          = errors.New("venafi: object already exists")
+	ErrUnauthorized           = errors.New("venafi: not authorized")
+	ErrInsufficientPrivileges = errors.New("venafi: insufficient privileges")
+)
+
+// APIError is returned for every failure reported by TPP, whether as an HTTP
+// status or as a result code inside a successful response. Network and
+// decoding errors, and errors the client detects itself, are not APIErrors.
+// Service-specific detail (ConfigServiceError, X509StoreServiceError,
+// CertificateServiceError) is available through errors.As.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Endpoint   string
+	Result     int
+	Message    string
+	RequestID  string
+	Err        error
+
+	sentinel error
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Endpoint == "" && e.Result == 0 {
+		return msg
+	}
+	if e.Endpoint == "" {
+		return fmt.Sprintf("%s (result %d)", msg, e.Result)
+	}
+	if e.Result != 0 {
+		return fmt.Sprintf("%s %s: %s (status %d, result %d)", e.Method, e.Endpoint, msg, e.StatusCode, e.Result)
+	}
+	return fmt.Sprintf("%s %s: %s (status %d)", e.Method, e.Endpoint, msg, e.StatusCode)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e *APIError) Is(target error) bool {
+	return e.sentinel != nil && e.sentinel == target
+}
+
+// requestIDHeaders are checked, in order, for a request ID to attach to errors.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id"}
+
+func newAPIError(res *http.Response, result int, message string, cause error) *APIError {
+	e := &APIError{Result: result, Message: message, Err: cause}
+	if res != nil {
+		e.StatusCode = res.StatusCode
+		if res.Request != nil {
+			e.Method = res.Request.Method
+			e.Endpoint = res.Request.URL.Path
+		}
+		for _, h := range requestIDHeaders {
+			if id := res.Header.Get(h); id != "" {
+				e.RequestID = id
+				break
+			}
+		}
+	}
+	e.sentinel = statusSentinel(e.StatusCode)
+	return e
+}
+
+// newHTTPError builds an APIError from a response with an error status. TPP
+// usually puts a description in an "Error" field of the body.
+func newHTTPError(res *http.Response) *APIError {
+	var body struct {
+		Error string
+	}
+	if b, err := ioutil.ReadAll(res.Body); err == nil {
+		if json.Unmarshal(b, &body) != nil || body.Error == "" {
+			body.Error = string(b)
+		}
+	}
+	res.Body.Close()
+
+	return newAPIError(res, 0, body.Error, nil)
+}
+
+func newConfigError(res *http.Response, result config.ConfigResult, message string) *APIError {
+	e := newAPIError(res, int(result), message, &ConfigServiceError{Result: result, Message: message})
+	if s := configSentinel(result); s != nil {
+		e.sentinel = s
+	}
+	return e
+}
+
+func newX509StoreError(res *http.Response, result secret_store.SecretStoreResult) *APIError {
+	cause := &X509StoreServiceError{Result: result}
+	e := newAPIError(res, int(result), cause.Message(), cause)
+	if s := secretStoreSentinel(result); s != nil {
+		e.sentinel = s
+	}
+	return e
+}
+
+// newNotFoundError reports a lookup that matched nothing, where TPP itself did
+// not return an error.
+func newNotFoundError(message string) *APIError {
+	e := newAPIError(nil, 0, message, nil)
+	e.sentinel = ErrNotFound
+	return e
+}
+
+func statusSentinel(status int) error {
+	switch status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrAlreadyExists
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrInsufficientPrivileges
+	}
+	return nil
+}
+
+func configSentinel(result config.ConfigResult) error {
+	switch result {
+	case config.ObjectDoesNotExist, config.AttributeDoesNotExist, config.AttributeNotFound,
+		config.ClassDoesNotExist, config.PolicyDoesNotExist, config.AttributeValueDoesNotExist:
+		return ErrNotFound
+	case config.ObjectAlreadyExists, config.AttributeAlreadyExists, config.ClassAlreadyExists,
+		config.AttributeValueExists, config.LockNameAlreadyExists:
+		return ErrAlreadyExists
+	case config.InsufficientPrivileges:
+		return ErrInsufficientPrivileges
+	}
+	return nil
+}
+
+func secretStoreSentinel(result secret_store.SecretStoreResult) error {
+	switch result {
+	case secret_store.InvalidVaultID:
+		return ErrNotFound
+	case secret_store.InsufficientPermissions:
+		return ErrInsufficientPrivileges
+	}
+	return nil
+}
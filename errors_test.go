@@ -0,0 +1,120 @@
+package venafi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+func TestAPIErrorDetails(t *testing.T) {
+	v, _ := newTestClient(t, nil)
+
+	_, err := v.Config.Retrieve(`\VED\Policy\Missing`)
+	var apiErr *venafi.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Retrieve of a missing object: got %v, want *APIError", err)
+	}
+	if apiErr.Endpoint != "/vedsdk/Config/IsValid" || apiErr.Method != http.MethodPost {
+		t.Errorf("APIError endpoint = %s %s, want POST /vedsdk/Config/IsValid", apiErr.Method, apiErr.Endpoint)
+	}
+	if apiErr.Result != int(config.ObjectDoesNotExist) {
+		t.Errorf("APIError result = %d, want %d", apiErr.Result, config.ObjectDoesNotExist)
+	}
+	var configErr *venafi.ConfigServiceError
+	if !errors.As(err, &configErr) || configErr.Result != config.ObjectDoesNotExist {
+		t.Errorf("errors.As ConfigServiceError = %v, want result %d", configErr, config.ObjectDoesNotExist)
+	}
+}
+
+func TestErrorSentinels(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		fault venafitest.Fault
+		call  func(v *venafi.Client) error
+		want  error
+	}{
+		{
+			name:  "config not found",
+			path:  "/vedsdk/Config/IsValid",
+			fault: venafitest.ConfigFault(config.ObjectDoesNotExist, "no such object"),
+			call:  func(v *venafi.Client) error { _, err := v.Config.Retrieve(`\VED\Policy`); return err },
+			want:  venafi.ErrNotFound,
+		},
+		{
+			name:  "config already exists",
+			path:  "/vedsdk/Config/Create",
+			fault: venafitest.ConfigFault(config.ObjectAlreadyExists, "exists"),
+			call: func(v *venafi.Client) error {
+				_, err := v.Config.Create(`\VED\Policy\Web`, config.ClassPolicy, nil)
+				return err
+			},
+			want: venafi.ErrAlreadyExists,
+		},
+		{
+			name:  "config insufficient privileges",
+			path:  "/vedsdk/Config/Create",
+			fault: venafitest.ConfigFault(config.InsufficientPrivileges, "denied"),
+			call: func(v *venafi.Client) error {
+				_, err := v.Config.Create(`\VED\Policy\Web`, config.ClassPolicy, nil)
+				return err
+			},
+			want: venafi.ErrInsufficientPrivileges,
+		},
+		{
+			name:  "http unauthorized",
+			path:  "/vedsdk/Config/Enumerate",
+			fault: venafitest.HTTPFault(http.StatusUnauthorized, "expired"),
+			call:  func(v *venafi.Client) error { _, err := v.Config.Enumerate(`\VED\Policy`, false, ""); return err },
+			want:  venafi.ErrUnauthorized,
+		},
+		{
+			name:  "http forbidden",
+			path:  "/vedsdk/Config/Enumerate",
+			fault: venafitest.HTTPFault(http.StatusForbidden, "denied"),
+			call:  func(v *venafi.Client) error { _, err := v.Config.Enumerate(`\VED\Policy`, false, ""); return err },
+			want:  venafi.ErrInsufficientPrivileges,
+		},
+		{
+			name:  "http not found",
+			path:  "/vedsdk/Config/Enumerate",
+			fault: venafitest.HTTPFault(http.StatusNotFound, "gone"),
+			call:  func(v *venafi.Client) error { _, err := v.Config.Enumerate(`\VED\Policy`, false, ""); return err },
+			want:  venafi.ErrNotFound,
+		},
+		{
+			name:  "vault entry not found",
+			path:  "/vedsdk/SecretStore/Retrieve",
+			fault: venafitest.StoreFault(secret_store.InvalidVaultID),
+			call:  func(v *venafi.Client) error { _, _, err := v.SecretStore.Retrieve(42); return err },
+			want:  venafi.ErrNotFound,
+		},
+	}
+
+	sentinels := []error{venafi.ErrNotFound, venafi.ErrAlreadyExists, venafi.ErrUnauthorized, venafi.ErrInsufficientPrivileges}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, srv := newTestClient(t, nil)
+			srv.Inject(tt.path, tt.fault)
+
+			err := tt.call(v)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			for _, other := range sentinels {
+				if other != tt.want && errors.Is(err, other) {
+					t.Errorf("error also matches %v", other)
+				}
+			}
+			var apiErr *venafi.APIError
+			if !errors.As(err, &apiErr) {
+				t.Errorf("got %T, want *APIError", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package venafi
+
+import (
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExpiringCertificate is one certificate in an ExpiryReport. A certificate
+// held by several objects appears once for each of them. PolicyDN is the
+// folder containing OwnerDN, and Contacts are the names of the identities in
+// its effective Contact attribute.
+type ExpiringCertificate struct {
+	VaultID       int
+	OwnerDN       string
+	PolicyDN      string
+	Contacts      []string
+	Subject       string
+	SANs          []string
+	NotAfter      time.Time
+	DaysRemaining int
+}
+
+// ExpiryReport lists the certificates that expire within Days days, soonest
+// first.
+type ExpiryReport struct {
+	GeneratedAt  time.Time
+	Days         int
+	OwnerDN      string `json:",omitempty"`
+	Certificates []ExpiringCertificate
+}
+
+// ExpiryGroup is a set of report entries sharing a policy folder or contact.
+type ExpiryGroup struct {
+	Name         string
+	Certificates []ExpiringCertificate
+}
+
+// ExpiryGrouping selects how WriteMarkdown groups a report.
+type ExpiryGrouping int
+
+//noinspection GoUnusedConst
+const (
+	GroupByPolicy ExpiryGrouping = iota
+	GroupByContact
+)
+
+// noContact is the group name used for certificates without a contact.
+const noContact = "(no contact)"
+
+// expiryReportWorkers is how many certificates NewExpiryReport fetches at
+// once.
+const expiryReportWorkers = 8
+
+// NewExpiryReport looks up the certificates in x509Store that expire within
+// days, limited to those owned by ownerDN if it is set, and resolves each to
+// its owners, subject, SANs, policy folder and contacts. Certificates and
+// their owners are fetched concurrently.
+func NewExpiryReport(x509Store X509StoreAPI, secretStore SecretStoreAPI, config ConfigAPI, identity IdentityAPI,
+	days int, ownerDN string) (*ExpiryReport, error) {
+	ids, err := x509Store.LookupExpiring(days, ownerDN)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := fetchExpiring(x509Store, secretStore, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	report := &ExpiryReport{GeneratedAt: now, Days: days, OwnerDN: ownerDN, Certificates: make([]ExpiringCertificate, 0)}
+	names := make(map[string]string)
+
+	for i, entry := range entries {
+		owners := entry.owners
+		if len(owners) == 0 {
+			owners = []string{""}
+		}
+
+		for _, owner := range owners {
+			contacts, err := expiryContacts(config, identity, owner, names)
+			if err != nil {
+				return nil, err
+			}
+			report.Certificates = append(report.Certificates, ExpiringCertificate{
+				VaultID:       ids[i],
+				OwnerDN:       owner,
+				PolicyDN:      parentDN(owner),
+				Contacts:      contacts,
+				Subject:       entry.cert.Subject.String(),
+				SANs:          subjectAltNames(entry.cert),
+				NotAfter:      entry.cert.NotAfter,
+				DaysRemaining: int(math.Floor(entry.cert.NotAfter.Sub(now).Hours() / 24)),
+			})
+		}
+	}
+
+	sort.SliceStable(report.Certificates, func(i, j int) bool {
+		a, b := report.Certificates[i], report.Certificates[j]
+		if !a.NotAfter.Equal(b.NotAfter) {
+			return a.NotAfter.Before(b.NotAfter)
+		}
+		return a.OwnerDN < b.OwnerDN
+	})
+
+	return report, nil
+}
+
+// expiringEntry is a certificate fetched for an ExpiryReport and the objects
+// that own its vault entry.
+type expiringEntry struct {
+	cert   *x509.Certificate
+	owners []string
+	err    error
+}
+
+// fetchExpiring retrieves the certificate and owners of each vault ID, using
+// up to expiryReportWorkers requests at a time. Results are in the order of
+// ids; the error for the first ID that failed is returned.
+func fetchExpiring(x509Store X509StoreAPI, secretStore SecretStoreAPI, ids []int) ([]expiringEntry, error) {
+	entries := make([]expiringEntry, len(ids))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < expiryReportWorkers && w < len(ids); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entry := &entries[i]
+				if entry.cert, entry.err = x509Store.Retrieve(ids[i]); entry.err != nil {
+					continue
+				}
+				entry.owners, entry.err = secretStore.OwnerLookup(ids[i])
+			}
+		}()
+	}
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, entry := range entries {
+		if entry.err != nil {
+			return nil, entry.err
+		}
+	}
+	return entries, nil
+}
+
+// expiryContacts returns the names of the effective contacts of ownerDN.
+// names caches identity lookups across calls.
+func expiryContacts(config ConfigAPI, identity IdentityAPI, ownerDN string, names map[string]string) ([]string, error) {
+	if ownerDN == "" {
+		return nil, nil
+	}
+
+	value, err := config.ReadEffectivePolicy(ownerDN, "Contact")
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make([]string, 0, len(value.Values))
+	for _, universal := range value.Values {
+		name, ok := names[universal]
+		if !ok {
+			name = universal
+			id, err := identity.Validate(&Identity{PrefixedUniversal: universal})
+			if err == nil {
+				name = id.PrefixedName
+			} else if !isMissingIdentity(err) {
+				return nil, err
+			}
+			names[universal] = name
+		}
+		rv = append(rv, name)
+	}
+	return rv, nil
+}
+
+// ByPolicy groups the report by policy folder, in name order.
+func (r *ExpiryReport) ByPolicy() []ExpiryGroup {
+	return r.group(func(c *ExpiringCertificate) []string {
+		return []string{c.PolicyDN}
+	})
+}
+
+// ByContact groups the report by contact, in name order. A certificate with
+// several contacts appears in each of their groups.
+func (r *ExpiryReport) ByContact() []ExpiryGroup {
+	return r.group(func(c *ExpiringCertificate) []string {
+		if len(c.Contacts) == 0 {
+			return []string{noContact}
+		}
+		return c.Contacts
+	})
+}
+
+func (r *ExpiryReport) group(keys func(c *ExpiringCertificate) []string) []ExpiryGroup {
+	index := make(map[string]int)
+	groups := make([]ExpiryGroup, 0)
+	for i := range r.Certificates {
+		c := &r.Certificates[i]
+		for _, key := range keys(c) {
+			n, ok := index[key]
+			if !ok {
+				n = len(groups)
+				index[key] = n
+				groups = append(groups, ExpiryGroup{Name: key})
+			}
+			groups[n].Certificates = append(groups[n].Certificates, *c)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	return groups
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *ExpiryReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes the report as CSV with one row per entry. Lists are joined
+// with semicolons.
+func (r *ExpiryReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"Days Remaining", "Not After", "Subject", "SANs", "Owner DN", "Policy DN", "Contacts", "Vault ID"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, c := range r.Certificates {
+		row := []string{
+			strconv.Itoa(c.DaysRemaining), c.NotAfter.UTC().Format(time.RFC3339), c.Subject,
+			strings.Join(c.SANs, ";"), c.OwnerDN, c.PolicyDN, strings.Join(c.Contacts, ";"), strconv.Itoa(c.VaultID),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown writes the report as a Markdown document with a table for
+// each group.
+func (r *ExpiryReport) WriteMarkdown(w io.Writer, grouping ExpiryGrouping) error {
+	groups := r.ByPolicy()
+	if grouping == GroupByContact {
+		groups = r.ByContact()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Certificates expiring within %d days\n\n", r.Days)
+	unique := make(map[int]bool)
+	for _, c := range r.Certificates {
+		unique[c.VaultID] = true
+	}
+	fmt.Fprintf(&b, "Generated %s. %d certificates found.\n", r.GeneratedAt.Format(time.RFC3339), len(unique))
+
+	for _, g := range groups {
+		name := g.Name
+		if name == "" {
+			name = "(no owner)"
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownEscape(name))
+		b.WriteString("| Days | Expires | Subject | SANs | Owner | Contacts |\n")
+		b.WriteString("|---:|---|---|---|---|---|\n")
+		for _, c := range g.Certificates {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s | %s |\n", c.DaysRemaining, c.NotAfter.UTC().Format("2006-01-02"),
+				markdownEscape(c.Subject), markdownEscape(strings.Join(c.SANs, ", ")), markdownEscape(c.OwnerDN),
+				markdownEscape(strings.Join(c.Contacts, ", ")))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// subjectAltNames returns every SAN in cert as a string.
+func subjectAltNames(cert *x509.Certificate) []string {
+	rv := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		rv = append(rv, ip.String())
+	}
+	rv = append(rv, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		rv = append(rv, u.String())
+	}
+	return rv
+}
+
+// markdownEscape makes s safe to use in a Markdown table cell. Backslashes are
+// escaped so that DNs render as written.
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", " ").Replace(s)
+}
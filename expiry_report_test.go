@@ -0,0 +1,118 @@
+package venafi_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+func TestExpiryReport(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	alice := srv.AddUser("alice", nil)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Db`, config.ClassPolicy, nil)
+	if err := v.Config.WritePolicy(`\VED\Policy\Web`, config.ClassX509Certificate, "Contact",
+		[]string{alice.PrefixedUniversal}, false); err != nil {
+		t.Fatalf("WritePolicy: %v", err)
+	}
+
+	add := func(dn string, commonName string, days int) int {
+		t.Helper()
+		srv.AddObject(dn, config.ClassX509Certificate, nil)
+		cert, _ := newCertificate(t, commonName, days, commonName)
+		id, err := v.X509Store.Add(cert, dn, "")
+		if err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		return id
+	}
+	soon := add(`\VED\Policy\Web\web01`, "web01.example.com", 5)
+	add(`\VED\Policy\Db\db01`, "db01.example.com", 20)
+	add(`\VED\Policy\Web\web02`, "web02.example.com", 90)
+
+	// A certificate held by two objects is reported for each of them.
+	srv.AddObject(`\VED\Policy\Db\db02`, config.ClassX509Certificate, nil)
+	if err := v.SecretStore.OwnerAdd(soon, `\VED\Policy\Db\db02`); err != nil {
+		t.Fatalf("OwnerAdd: %v", err)
+	}
+
+	report, err := venafi.NewExpiryReport(v.X509Store, v.SecretStore, v.Config, v.Identity, 30, "")
+	if err != nil {
+		t.Fatalf("NewExpiryReport: %v", err)
+	}
+
+	var got []string
+	for _, c := range report.Certificates {
+		got = append(got, fmt.Sprintf("%s %d %s", c.OwnerDN, c.DaysRemaining, strings.Join(c.Contacts, ",")))
+	}
+	want := []string{
+		`\VED\Policy\Db\db02 5 `,
+		`\VED\Policy\Web\web01 5 local:alice`,
+		`\VED\Policy\Db\db01 20 `,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("report entries:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if c := report.Certificates[1]; c.PolicyDN != `\VED\Policy\Web` || c.Subject != "CN=web01.example.com" ||
+		len(c.SANs) != 1 || c.SANs[0] != "web01.example.com" {
+		t.Errorf("web01 entry = %+v", c)
+	}
+
+	groups := report.ByContact()
+	if len(groups) != 2 || groups[0].Name != "(no contact)" || groups[1].Name != "local:alice" {
+		t.Errorf("ByContact groups = %+v", groups)
+	}
+	if groups := report.ByPolicy(); len(groups) != 2 || len(groups[0].Certificates) != 2 {
+		t.Errorf("ByPolicy groups = %+v", groups)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 4 {
+		t.Errorf("CSV has %d rows, %v, want a header and 3 entries", len(rows), err)
+	}
+	buf.Reset()
+	if err := report.WriteMarkdown(&buf, venafi.GroupByPolicy); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	if !strings.Contains(buf.String(), "web01.example.com") {
+		t.Errorf("Markdown report does not mention web01:\n%s", buf.String())
+	}
+}
+
+func TestExpiryReportManyCertificates(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+
+	const n = 25
+	for i := 0; i < n; i++ {
+		dn := fmt.Sprintf(`\VED\Policy\Web\web%02d`, i)
+		srv.AddObject(dn, config.ClassX509Certificate, nil)
+		cert, _ := newCertificate(t, fmt.Sprintf("web%02d.example.com", i), n-i)
+		if _, err := v.X509Store.Add(cert, dn, ""); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	report, err := venafi.NewExpiryReport(v.X509Store, v.SecretStore, v.Config, v.Identity, 30, "")
+	if err != nil {
+		t.Fatalf("NewExpiryReport: %v", err)
+	}
+	if len(report.Certificates) != n {
+		t.Fatalf("report has %d entries, want %d", len(report.Certificates), n)
+	}
+	for i, c := range report.Certificates {
+		want := fmt.Sprintf(`\VED\Policy\Web\web%02d`, n-1-i)
+		if c.OwnerDN != want || c.Subject != fmt.Sprintf("CN=web%02d.example.com", n-1-i) {
+			t.Errorf("entry %d = %s %s, want %s", i, c.OwnerDN, c.Subject, want)
+		}
+	}
+}
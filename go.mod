@@ -1,3 +1,8 @@
 module github.com/tradel/venafi-tpp
 
-require github.com/hashicorp/go-hclog v0.9.2
+go 1.16
+
+require (
+	github.com/hashicorp/go-hclog v0.9.2
+	gopkg.in/yaml.v2 v2.4.0
+)
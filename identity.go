@@ -1,7 +1,11 @@
 package venafi
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 )
 
 type IdentityService struct {
@@ -9,37 +13,131 @@ type IdentityService struct {
 }
 
 type Identity struct {
-	FullName          string  `json:",omitempty"`
-	IsContainer       bool    `json:",omitempty"`
-	IsGroup           bool    `json:",omitempty"`
-	Name              string  `json:",omitempty"`
-	Prefix            string  `json:",omitempty"`
-	PrefixedName      string  `json:",omitempty"`
-	PrefixedUniversal string  `json:",omitempty"`
-	Universal         string  `json:",omitempty"`
+	FullName          string       `json:",omitempty"`
+	IsContainer       bool         `json:",omitempty"`
+	IsGroup           bool         `json:",omitempty"`
+	Name              string       `json:",omitempty"`
+	Prefix            string       `json:",omitempty"`
+	PrefixedName      string       `json:",omitempty"`
+	PrefixedUniversal string       `json:",omitempty"`
+	Universal         string       `json:",omitempty"`
+	Type              IdentityType `json:",omitempty"`
 }
 
+// IdentityType is a set of flags selecting the kinds of identity to browse
+// for.
+type IdentityType int
+
+//noinspection GoUnusedConst
+const (
+	IdentityUser              IdentityType = 1
+	IdentitySecurityGroup     IdentityType = 2
+	IdentityDistributionGroup IdentityType = 8
+	IdentityGroup                          = IdentitySecurityGroup | IdentityDistributionGroup
+	IdentityAll                            = IdentityUser | IdentityGroup
+)
+
+// identityLookupLimit caps the number of candidates Lookup asks Browse for.
+const identityLookupLimit = 100
+
+// identityNotFoundMessage starts the error message of the 400 response TPP
+// sends when Validate is given an identity that does not exist. Other 400
+// responses, such as a malformed request, are not treated as not found.
+const identityNotFoundMessage = "failed to validate identity"
+
+// SessionIdentity is the identity a session is authenticated as, together
+// with every group it belongs to, directly or through nesting.
+type SessionIdentity struct {
+	Identity Identity
+	Groups   []Identity
+}
+
+// sessionCache holds the result of Self for the current API key. It is
+// discarded when the client authenticates again.
+type sessionCache struct {
+	mu      sync.Mutex
+	apiKey  string
+	session *SessionIdentity
+}
+
+// Self returns the identity the client is authenticated as. TPP may list
+// several identities for a session, so the one whose name matches the
+// client's username is picked, ignoring case and any domain qualifier
+// ("CORP\alice", "alice@corp.example"). Sessions without a username, such as
+// those using a token, get the only user identity in the list. The result is
+// cached until the client authenticates again. A *SelfIdentityError is
+// returned if no single identity fits.
 func (s *IdentityService) Self() (*Identity, error) {
+	cache := &s.client.session
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	session, err := s.self(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	id := session.Identity
+	return &id, nil
+}
+
+// Session is like Self but also returns the groups the identity belongs to.
+func (s *IdentityService) Session() (*SessionIdentity, error) {
+	cache := &s.client.session
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	session, err := s.self(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Groups == nil {
+		groups, err := s.GetAssociatedEntries(&session.Identity)
+		if err != nil {
+			return nil, err
+		}
+		session.Groups = append(make([]Identity, 0, len(groups)), groups...)
+	}
+
+	return &SessionIdentity{session.Identity, append([]Identity(nil), session.Groups...)}, nil
+}
+
+// self returns the cached session, asking TPP for it if the API key has
+// changed. cache must be locked.
+func (s *IdentityService) self(cache *sessionCache) (*SessionIdentity, error) {
+	apiKey, err := s.client.apiKey()
+	if err != nil {
+		return nil, err
+	}
+	if cache.session != nil && cache.apiKey == apiKey {
+		return cache.session, nil
+	}
+
 	type Output struct {
 		Identities []Identity
 	}
 
 	var output Output
 
-	_, err := s.client.doJsonRequestWithBody("GET", "/vedsdk/Identity/Self", nil, &output)
+	_, err = s.client.doJsonRequestWithBody("GET", "/vedsdk/Identity/Self", nil, &output)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, identity := range output.Identities {
-		if identity.Name == s.client.Username {
-			return &identity, nil
-		}
+	id, err := pickSelf(output.Identities, s.client.Username)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("username not found in identity output: %s", s.client.Username)
+	cache.apiKey = apiKey
+	cache.session = &SessionIdentity{Identity: *id}
+	return cache.session, nil
 }
 
+// Validate looks up id by its prefixed universal ID or prefixed name and
+// returns the full identity. It returns an error wrapping ErrNotFound if no
+// such identity exists.
 func (s *IdentityService) Validate(id *Identity) (*Identity, error) {
 	type Input struct {
 		ID Identity
@@ -53,8 +151,248 @@ func (s *IdentityService) Validate(id *Identity) (*Identity, error) {
 
 	_, err := s.client.doJsonRequestWithBody("POST", "/vedsdk/Identity/Validate", input, &output)
 	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest &&
+			strings.HasPrefix(strings.ToLower(apiErr.Message), identityNotFoundMessage) {
+			apiErr.sentinel = ErrNotFound
+		}
 		return nil, err
 	}
 
 	return &output.ID, nil
-}
\ No newline at end of file
+}
+
+// Browse searches the identity providers for identities whose names start
+// with filter, returning at most limit of them. identityTypes is a combination
+// of the IdentityType flags; zero means all of them.
+func (s *IdentityService) Browse(filter string, limit int, identityTypes IdentityType) ([]Identity, error) {
+	type Input struct {
+		Filter       string
+		Limit        int
+		IdentityType IdentityType
+	}
+	type Output struct {
+		Identities []Identity
+	}
+
+	if identityTypes == 0 {
+		identityTypes = IdentityAll
+	}
+
+	var input Input = Input{filter, limit, identityTypes}
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("POST", "/vedsdk/Identity/Browse", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Identities, nil
+}
+
+// GetAssociatedEntries returns every group id belongs to, directly or through
+// nested groups.
+func (s *IdentityService) GetAssociatedEntries(id *Identity) ([]Identity, error) {
+	type Input struct {
+		ID Identity
+	}
+	type Output struct {
+		Identities []Identity
+	}
+
+	var input Input = Input{Identity{PrefixedUniversal: id.PrefixedUniversal}}
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("POST", "/vedsdk/Identity/GetAssociatedEntries", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Identities, nil
+}
+
+// ReadAttribute reads an attribute of an identity from its provider, such as
+// "Email Address" or "Telephone Number".
+func (s *IdentityService) ReadAttribute(id *Identity, attributeName string) ([]string, error) {
+	type Input struct {
+		ID            Identity
+		AttributeName string
+	}
+	type Output struct {
+		Attributes []string
+	}
+
+	var input Input = Input{Identity{PrefixedUniversal: id.PrefixedUniversal}, attributeName}
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("POST", "/vedsdk/Identity/ReadAttribute", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Attributes, nil
+}
+
+// Lookup resolves name to a single identity. name may be a prefixed universal
+// ID ("local:{guid}"), a prefixed name ("AD+corp:alice"), a plain name
+// ("alice") or a distinguished name, in full or leading part
+// ("CN=Ops,OU=Groups"). It returns an error wrapping ErrNotFound if nothing
+// matches, and an error listing the candidates if more than one does.
+func (s *IdentityService) Lookup(name string) (*Identity, error) {
+	if strings.Contains(name, ":") {
+		for _, id := range []Identity{{PrefixedUniversal: name}, {PrefixedName: name}} {
+			found, err := s.Validate(&id)
+			if err == nil {
+				return found, nil
+			}
+			if !isMissingIdentity(err) {
+				return nil, err
+			}
+		}
+		return nil, newNotFoundError(fmt.Sprintf("identity not found: %s", name))
+	}
+
+	filter := name
+	if cn := leadingCN(name); cn != "" {
+		filter = cn
+	}
+
+	candidates, err := s.Browse(filter, identityLookupLimit, IdentityAll)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Identity, 0)
+	for _, id := range candidates {
+		if identityMatches(&id, name) {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, newNotFoundError(fmt.Sprintf("identity not found: %s", name))
+	case 1:
+		return &matches[0], nil
+	}
+
+	names := make([]string, len(matches))
+	for i, id := range matches {
+		names[i] = id.PrefixedName
+	}
+	return nil, fmt.Errorf("identity %q is ambiguous: %s", name, strings.Join(names, ", "))
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// leadingCN returns the value of the first RDN of dn if it is a CN, so that
+// "CN=Ops,OU=Groups" can be browsed for as "Ops".
+func leadingCN(dn string) string {
+	first := strings.SplitN(dn, ",", 2)[0]
+	if len(first) > 3 && strings.EqualFold(first[:3], "CN=") {
+		return strings.TrimSpace(first[3:])
+	}
+	return ""
+}
+
+// identityMatches reports whether id is the identity called name, comparing
+// its names case-insensitively and allowing name to be a leading part of a
+// distinguished FullName.
+func identityMatches(id *Identity, name string) bool {
+	if strings.EqualFold(id.Name, name) || strings.EqualFold(id.PrefixedName, name) ||
+		strings.EqualFold(id.FullName, name) {
+		return true
+	}
+	return leadingCN(name) != "" && strings.HasPrefix(strings.ToLower(id.FullName), strings.ToLower(name)+",")
+}
+
+// pickSelf chooses the session's own identity from the list Identity/Self
+// returned.
+func pickSelf(ids []Identity, username string) (*Identity, error) {
+	if len(ids) == 1 {
+		return &ids[0], nil
+	}
+
+	if username != "" {
+		matches := make([]Identity, 0)
+		for _, id := range ids {
+			if selfMatches(&id, username) {
+				matches = append(matches, id)
+			}
+		}
+		if id := onlyUser(matches); id != nil {
+			return id, nil
+		}
+	}
+
+	if id := onlyUser(ids); id != nil {
+		return id, nil
+	}
+
+	return nil, &SelfIdentityError{Username: username, Candidates: ids}
+}
+
+// selfMatches reports whether id is the identity for username, ignoring case
+// and any domain qualifier on either side.
+func selfMatches(id *Identity, username string) bool {
+	if identityMatches(id, username) {
+		return true
+	}
+	_, prefixedName := splitPrefixed(id.PrefixedName)
+	account := accountName(username)
+	return strings.EqualFold(accountName(id.Name), account) || strings.EqualFold(accountName(prefixedName), account)
+}
+
+// accountName strips the domain from "DOMAIN\user" and "user@domain".
+func accountName(name string) string {
+	if i := strings.LastIndex(name, `\`); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// onlyUser returns the single non-group identity in ids, or nil if there is
+// not exactly one.
+func onlyUser(ids []Identity) *Identity {
+	var rv *Identity
+	for i := range ids {
+		if ids[i].IsGroup {
+			continue
+		}
+		if rv != nil {
+			return nil
+		}
+		rv = &ids[i]
+	}
+	return rv
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Errors
+///////////////////////////////////////////////////////////////////////////////
+
+// SelfIdentityError is returned by Self when the session's identity cannot be
+// told apart from the identities TPP returned. Candidates lists them.
+type SelfIdentityError struct {
+	Username   string
+	Candidates []Identity
+}
+
+func (e *SelfIdentityError) Error() string {
+	if len(e.Candidates) == 0 {
+		return "TPP returned no identity for this session"
+	}
+	names := make([]string, len(e.Candidates))
+	for i, id := range e.Candidates {
+		names[i] = id.PrefixedName
+	}
+	if e.Username == "" {
+		return fmt.Sprintf("cannot tell which identity this session belongs to: %s", strings.Join(names, ", "))
+	}
+	return fmt.Sprintf("cannot tell which identity belongs to user %q: %s", e.Username, strings.Join(names, ", "))
+}
@@ -0,0 +1,348 @@
+package venafi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// GetMembers returns the members of group. With resolveNested, members of
+// nested groups are included and the nested groups themselves are not.
+func (s *IdentityService) GetMembers(group *Identity, resolveNested bool) ([]Identity, error) {
+	type Input struct {
+		ID            Identity
+		ResolveNested int
+	}
+	type Output struct {
+		Identities []Identity
+	}
+
+	var input Input = Input{Identity{PrefixedUniversal: group.PrefixedUniversal}, btoi(resolveNested)}
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("POST", "/vedsdk/Identity/GetMembers", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Identities, nil
+}
+
+// GetMemberships returns the groups id is a direct member of.
+func (s *IdentityService) GetMemberships(id *Identity) ([]Identity, error) {
+	type Input struct {
+		ID Identity
+	}
+	type Output struct {
+		Identities []Identity
+	}
+
+	var input Input = Input{Identity{PrefixedUniversal: id.PrefixedUniversal}}
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("POST", "/vedsdk/Identity/GetMemberships", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Identities, nil
+}
+
+// AddGroup creates a local group called name with the given initial members.
+// TPP creates the group even if some members cannot be added; the group is
+// then returned together with an *InvalidMembersError listing them.
+func (s *IdentityService) AddGroup(name string, members []Identity) (*Identity, error) {
+	type Input struct {
+		Name    Identity
+		Members []Identity
+	}
+	type Output struct {
+		ID             Identity
+		InvalidMembers []Identity
+	}
+
+	var input Input = Input{Identity{PrefixedName: "local:" + name}, identityRefs(members)}
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("POST", "/vedsdk/Identity/AddGroup", input, &output)
+	if err != nil {
+		return nil, err
+	}
+	if len(output.InvalidMembers) > 0 {
+		return &output.ID, &InvalidMembersError{Group: name, Members: output.InvalidMembers}
+	}
+
+	return &output.ID, nil
+}
+
+// AddGroupMembers adds members to a local group. If TPP cannot add some of
+// them, the rest are still added and an *InvalidMembersError lists the ones
+// left out.
+func (s *IdentityService) AddGroupMembers(group *Identity, members []Identity) error {
+	type Input struct {
+		Group   Identity
+		Members []Identity
+	}
+	type Output struct {
+		InvalidMembers []Identity
+	}
+
+	var input Input = Input{Identity{PrefixedUniversal: group.PrefixedUniversal}, identityRefs(members)}
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("PUT", "/vedsdk/Identity/AddGroupMembers", input, &output)
+	if err != nil {
+		return err
+	}
+	if len(output.InvalidMembers) > 0 {
+		return &InvalidMembersError{Group: group.PrefixedName, Members: output.InvalidMembers}
+	}
+
+	return nil
+}
+
+// RemoveGroupMembers removes members from a local group.
+func (s *IdentityService) RemoveGroupMembers(group *Identity, members []Identity) error {
+	type Input struct {
+		Group   Identity
+		Members []Identity
+	}
+
+	var input Input = Input{Identity{PrefixedUniversal: group.PrefixedUniversal}, identityRefs(members)}
+
+	_, err := s.client.doJsonRequestWithBody("PUT", "/vedsdk/Identity/RemoveGroupMembers", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameGroup renames a local group and returns it under its new name.
+func (s *IdentityService) RenameGroup(group *Identity, newName string) (*Identity, error) {
+	type Input struct {
+		Group        Identity
+		NewGroupName string
+	}
+	type Output struct {
+		ID Identity
+	}
+
+	var input Input = Input{Identity{PrefixedUniversal: group.PrefixedUniversal}, newName}
+	var output Output
+
+	_, err := s.client.doJsonRequestWithBody("PUT", "/vedsdk/Identity/RenameGroup", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.ID, nil
+}
+
+// DeleteGroup deletes a local group. Its members are not affected.
+func (s *IdentityService) DeleteGroup(group *Identity) error {
+	prefix, universal := splitPrefixed(group.PrefixedUniversal)
+	path := fmt.Sprintf("/vedsdk/Identity/Group/%s/%s", url.PathEscape(prefix), url.PathEscape(universal))
+
+	_, err := s.client.doJsonRequestWithBody("DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GroupSyncPlan is the set of changes needed to make a local group's direct
+// members match a desired list. Create is set if the group does not exist yet;
+// Group is filled in once it does.
+type GroupSyncPlan struct {
+	GroupName string
+	Group     *Identity
+	Create    bool
+	Add       []Identity
+	Remove    []Identity
+}
+
+// Empty reports whether the group already has the desired members.
+func (p *GroupSyncPlan) Empty() bool {
+	return !p.Create && len(p.Add) == 0 && len(p.Remove) == 0
+}
+
+func (p *GroupSyncPlan) String() string {
+	if p.Empty() {
+		return fmt.Sprintf("%s: no changes.", p.GroupName)
+	}
+	lines := make([]string, 0, len(p.Add)+len(p.Remove)+1)
+	if p.Create {
+		lines = append(lines, fmt.Sprintf("+ create group %s", p.GroupName))
+	}
+	for _, id := range p.Add {
+		lines = append(lines, fmt.Sprintf("+ %s: %s", p.GroupName, id.PrefixedName))
+	}
+	for _, id := range p.Remove {
+		lines = append(lines, fmt.Sprintf("- %s: %s", p.GroupName, id.PrefixedName))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PlanGroupSync compares the direct members of the local group groupName with
+// members, which are resolved with identity's Lookup, and returns the changes
+// needed to make them match. Nothing is modified.
+func PlanGroupSync(identity IdentityAPI, groupName string, members []string) (*GroupSyncPlan, error) {
+	desired := make(map[string]Identity, len(members))
+	for _, name := range members {
+		id, err := identity.Lookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving member %q of %s: %w", name, groupName, err)
+		}
+		desired[strings.ToLower(id.PrefixedUniversal)] = *id
+	}
+
+	plan := &GroupSyncPlan{GroupName: groupName, Create: true}
+	current := make(map[string]Identity)
+	group, err := identity.Validate(&Identity{PrefixedName: "local:" + groupName})
+	if err != nil && !isMissingIdentity(err) {
+		return nil, err
+	}
+	if err == nil {
+		plan.Create = false
+		plan.Group = group
+		existing, err := identity.GetMembers(group, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range existing {
+			current[strings.ToLower(id.PrefixedUniversal)] = id
+		}
+	}
+
+	for key, id := range desired {
+		if _, ok := current[key]; !ok {
+			plan.Add = append(plan.Add, id)
+		}
+	}
+	for key, id := range current {
+		if _, ok := desired[key]; !ok {
+			plan.Remove = append(plan.Remove, id)
+		}
+	}
+	sortIdentities(plan.Add)
+	sortIdentities(plan.Remove)
+
+	return plan, nil
+}
+
+// Apply carries out the plan through identity, creating the group if needed.
+// Members are added before any are removed; if the removal fails, the members
+// just added are removed again so that the group is left as it was. If that
+// fails too, the error is a *RollbackError. If the group is created but TPP
+// rejects some members, Group is still set and the *InvalidMembersError is
+// returned.
+func (p *GroupSyncPlan) Apply(identity IdentityAPI) error {
+	if p.Group == nil {
+		group, err := identity.AddGroup(p.GroupName, p.Add)
+		if group != nil {
+			p.Group = group
+		}
+		return err
+	}
+
+	if len(p.Add) > 0 {
+		if err := identity.AddGroupMembers(p.Group, p.Add); err != nil {
+			return err
+		}
+	}
+	if len(p.Remove) > 0 {
+		if err := identity.RemoveGroupMembers(p.Group, p.Remove); err != nil {
+			return p.undoAdd(identity, err)
+		}
+	}
+	return nil
+}
+
+// undoAdd removes the members Apply added after a later step failed with
+// cause.
+func (p *GroupSyncPlan) undoAdd(identity IdentityAPI, cause error) error {
+	if len(p.Add) == 0 {
+		return cause
+	}
+	if err := identity.RemoveGroupMembers(p.Group, p.Add); err != nil {
+		return &RollbackError{Err: cause, Failures: []error{
+			fmt.Errorf("error removing added members from %s: %w", p.GroupName, err),
+		}}
+	}
+	return cause
+}
+
+// ReconcileGroup makes the direct members of the local group groupName match
+// members, creating the group if it does not exist, and returns the changes
+// it made.
+func ReconcileGroup(identity IdentityAPI, groupName string, members []string) (*GroupSyncPlan, error) {
+	plan, err := PlanGroupSync(identity, groupName, members)
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.Apply(identity); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// identityRefs reduces identities to the prefixed universal IDs TPP needs to
+// find them.
+func identityRefs(ids []Identity) []Identity {
+	rv := make([]Identity, len(ids))
+	for i, id := range ids {
+		rv[i] = Identity{PrefixedUniversal: id.PrefixedUniversal}
+	}
+	return rv
+}
+
+// splitPrefixed splits "local:{guid}" into "local" and "{guid}".
+func splitPrefixed(prefixed string) (string, string) {
+	i := strings.Index(prefixed, ":")
+	if i < 0 {
+		return "", prefixed
+	}
+	return prefixed[:i], prefixed[i+1:]
+}
+
+// isMissingIdentity reports whether err says that an identity does not exist.
+func isMissingIdentity(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func sortIdentities(ids []Identity) {
+	sort.Slice(ids, func(i, j int) bool {
+		return strings.ToLower(ids[i].PrefixedName) < strings.ToLower(ids[j].PrefixedName)
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Errors
+///////////////////////////////////////////////////////////////////////////////
+
+// InvalidMembersError is returned by AddGroup and AddGroupMembers when TPP
+// could not add some of the members to Group. Members lists them as TPP
+// reported them.
+type InvalidMembersError struct {
+	Group   string
+	Members []Identity
+}
+
+func (e *InvalidMembersError) Error() string {
+	names := make([]string, len(e.Members))
+	for i, id := range e.Members {
+		names[i] = id.PrefixedName
+		if names[i] == "" {
+			names[i] = id.PrefixedUniversal
+		}
+	}
+	return fmt.Sprintf("cannot add %d members to group %s: %s", len(e.Members), e.Group, strings.Join(names, ", "))
+}
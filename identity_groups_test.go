@@ -0,0 +1,99 @@
+package venafi_test
+
+import (
+	"errors"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+)
+
+func TestReconcileGroup(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	alice := srv.AddUser("alice", nil)
+	bob := srv.AddUser("bob", nil)
+	carol := srv.AddUser("carol", nil)
+
+	// The group is created on the first run.
+	plan, err := venafi.ReconcileGroup(v.Identity, "Web Team", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("ReconcileGroup: %v", err)
+	}
+	if !plan.Create || len(plan.Add) != 2 || plan.Group == nil {
+		t.Fatalf("first plan = %+v, want the group created with two members", plan)
+	}
+	assertMembers(t, v, plan.Group, alice, bob)
+
+	// Running it again changes nothing.
+	plan, err = venafi.PlanGroupSync(v.Identity, "Web Team", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("PlanGroupSync: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("second plan is not empty:\n%s", plan)
+	}
+
+	// Changing the desired members adds and removes the difference.
+	plan, err = venafi.ReconcileGroup(v.Identity, "Web Team", []string{"bob", carol.PrefixedUniversal})
+	if err != nil {
+		t.Fatalf("ReconcileGroup: %v", err)
+	}
+	if plan.Create || len(plan.Add) != 1 || len(plan.Remove) != 1 {
+		t.Errorf("third plan = %s, want one add and one removal", plan)
+	}
+	assertMembers(t, v, plan.Group, bob, carol)
+
+	if _, err := venafi.ReconcileGroup(v.Identity, "Web Team", []string{"nobody"}); !errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("ReconcileGroup with an unknown member: got %v, want ErrNotFound", err)
+	}
+}
+
+// assertMembers checks that group's direct members are exactly want.
+func assertMembers(t *testing.T, v *venafi.Client, group *venafi.Identity, want ...venafi.Identity) {
+	t.Helper()
+
+	members, err := v.Identity.GetMembers(group, false)
+	if err != nil {
+		t.Fatalf("GetMembers: %v", err)
+	}
+	got := make(map[string]bool, len(members))
+	for _, m := range members {
+		got[m.PrefixedUniversal] = true
+	}
+	if len(got) != len(want) {
+		t.Errorf("group has %d members, want %d", len(got), len(want))
+	}
+	for _, id := range want {
+		if !got[id.PrefixedUniversal] {
+			t.Errorf("group is missing %s", id.PrefixedName)
+		}
+	}
+}
+
+func TestAddGroupReportsInvalidMembers(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	alice := srv.AddUser("alice", nil)
+	bob := srv.AddUser("bob", nil)
+	ghost := venafi.Identity{PrefixedName: "local:ghost", PrefixedUniversal: "local:{ghost}"}
+
+	// The group is created with the members that exist.
+	group, err := v.Identity.AddGroup("Web Team", []venafi.Identity{alice, ghost})
+	var invalid *venafi.InvalidMembersError
+	if !errors.As(err, &invalid) || len(invalid.Members) != 1 ||
+		invalid.Members[0].PrefixedUniversal != ghost.PrefixedUniversal {
+		t.Fatalf("AddGroup with an unknown member: got %v, want *InvalidMembersError listing it", err)
+	}
+	if group == nil || group.PrefixedName != "local:Web Team" {
+		t.Fatalf("AddGroup returned group %+v, want the new group", group)
+	}
+	assertMembers(t, v, group, alice)
+
+	err = v.Identity.AddGroupMembers(group, []venafi.Identity{ghost, bob})
+	if !errors.As(err, &invalid) || len(invalid.Members) != 1 || invalid.Group != "local:Web Team" {
+		t.Fatalf("AddGroupMembers with an unknown member: got %v, want *InvalidMembersError", err)
+	}
+	assertMembers(t, v, group, alice, bob)
+
+	if err := v.Identity.AddGroupMembers(group, []venafi.Identity{alice}); err != nil {
+		t.Errorf("AddGroupMembers with valid members: %v", err)
+	}
+}
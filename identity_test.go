@@ -0,0 +1,289 @@
+package venafi_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/mocks"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+func TestValidateMissingIdentity(t *testing.T) {
+	v, _ := newTestClient(t, nil)
+
+	_, err := v.Identity.Validate(&venafi.Identity{PrefixedName: "local:nobody"})
+	if !errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("Validate of a missing identity: got %v, want ErrNotFound", err)
+	}
+	if _, err := v.Identity.Lookup("local:nobody"); !errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("Lookup of a missing identity: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestBadRequestIsNotMissingIdentity(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	alice := srv.AddUser("alice", nil)
+	srv.Inject("/vedsdk/Identity/Validate", venafitest.HTTPFault(http.StatusBadRequest, "Invalid request"))
+
+	if _, err := v.Identity.Validate(&alice); err == nil || errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("Validate with a bad request: got %v, want an error other than ErrNotFound", err)
+	}
+
+	// A group that cannot be checked must not be treated as missing and
+	// created again.
+	if _, err := venafi.ReconcileGroup(v.Identity, "Web Team", []string{alice.PrefixedUniversal}); err == nil {
+		t.Error("ReconcileGroup succeeded although Validate failed")
+	}
+}
+
+func TestGroupSyncApplyUndoesAddOnFailure(t *testing.T) {
+	group := venafi.Identity{PrefixedName: "local:Web Team", PrefixedUniversal: "local:{1}"}
+	alice := venafi.Identity{PrefixedName: "local:alice", PrefixedUniversal: "local:{2}"}
+	bob := venafi.Identity{PrefixedName: "local:bob", PrefixedUniversal: "local:{3}"}
+	plan := &venafi.GroupSyncPlan{GroupName: "Web Team", Group: &group,
+		Add: []venafi.Identity{alice}, Remove: []venafi.Identity{bob}}
+
+	removeErr := errors.New("remove failed")
+	ids := &mocks.IdentityAPI{
+		AddGroupMembersFunc: func(*venafi.Identity, []venafi.Identity) error { return nil },
+		RemoveGroupMembersFunc: func(_ *venafi.Identity, members []venafi.Identity) error {
+			if members[0].PrefixedUniversal == bob.PrefixedUniversal {
+				return removeErr
+			}
+			return nil
+		},
+	}
+
+	if err := plan.Apply(ids); !errors.Is(err, removeErr) {
+		t.Fatalf("Apply: got %v, want %v", err, removeErr)
+	}
+	calls := ids.RemoveGroupMembersCalls()
+	if len(calls) != 2 || calls[1].Members[0].PrefixedUniversal != alice.PrefixedUniversal {
+		t.Errorf("RemoveGroupMembers calls = %+v, want bob then alice", calls)
+	}
+
+	ids.RemoveGroupMembersFunc = func(*venafi.Identity, []venafi.Identity) error { return removeErr }
+	var rollbackErr *venafi.RollbackError
+	if err := plan.Apply(ids); !errors.As(err, &rollbackErr) {
+		t.Errorf("Apply with a failed undo: got %v, want *RollbackError", err)
+	}
+}
+
+// selfClient returns a client logged in to srv as username.
+func selfClient(t *testing.T, srv *venafitest.Server, username string) *venafi.Client {
+	t.Helper()
+
+	srv.Username = username
+	v, err := venafi.NewClient(srv.URL, username, srv.Password, srv.Client())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return v
+}
+
+func TestSelfWithTokenSession(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddGroup("Admins", srv.Identity)
+	if _, err := v.Config.IsValid(`\VED\Policy`, ""); err != nil {
+		t.Fatalf("IsValid: %v", err)
+	}
+
+	// A session set up from an existing API key has no username to match, so
+	// the only user identity among those returned is picked.
+	token, err := venafi.NewClient(srv.URL, "", "", srv.Client())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	token.APIKey = v.APIKey
+	me, err := token.Identity.Self()
+	if err != nil {
+		t.Fatalf("Self: %v", err)
+	}
+	if me.PrefixedUniversal != srv.Identity.PrefixedUniversal {
+		t.Errorf("Self = %s, want %s", me.PrefixedName, srv.Identity.PrefixedName)
+	}
+}
+
+func TestSelfMatchesUsername(t *testing.T) {
+	srv := venafitest.NewServer()
+	defer srv.Close()
+
+	alice := venafi.Identity{Name: "alice", Prefix: "AD+corp", PrefixedName: "AD+corp:alice",
+		PrefixedUniversal: "AD+corp:{a1}", FullName: "CN=Alice Smith,OU=Staff,DC=corp"}
+	bob := venafi.Identity{Name: "bob", Prefix: "AD+corp", PrefixedName: "AD+corp:bob",
+		PrefixedUniversal: "AD+corp:{b2}", FullName: "CN=Bob Jones,OU=Staff,DC=corp"}
+	team := venafi.Identity{Name: "Web Team", Prefix: "AD+corp", PrefixedName: "AD+corp:Web Team",
+		PrefixedUniversal: "AD+corp:{c3}", IsGroup: true}
+	self := venafitest.Fault{StatusCode: http.StatusOK,
+		Body: map[string]interface{}{"Identities": []venafi.Identity{bob, team, alice}}}
+
+	for _, username := range []string{"alice", "ALICE", `CORP\alice`, `corp\Alice`, "alice@corp", "Alice@CORP.example"} {
+		v := selfClient(t, srv, username)
+		srv.Inject("/vedsdk/Identity/Self", self)
+		me, err := v.Identity.Self()
+		srv.ClearFaults()
+		if err != nil {
+			t.Errorf("Self as %s: %v", username, err)
+			continue
+		}
+		if me.PrefixedUniversal != alice.PrefixedUniversal {
+			t.Errorf("Self as %s = %s, want %s", username, me.PrefixedName, alice.PrefixedName)
+		}
+	}
+}
+
+func TestSelfWithSeveralCandidates(t *testing.T) {
+	srv := venafitest.NewServer()
+	defer srv.Close()
+
+	local := venafi.Identity{Name: "alice", Prefix: "local", PrefixedName: "local:alice", PrefixedUniversal: "local:{a1}"}
+	ad := venafi.Identity{Name: "alice", Prefix: "AD+corp", PrefixedName: "AD+corp:alice", PrefixedUniversal: "AD+corp:{a2}"}
+	bob := venafi.Identity{Name: "bob", Prefix: "local", PrefixedName: "local:bob", PrefixedUniversal: "local:{b3}"}
+	srv.Inject("/vedsdk/Identity/Self", venafitest.Fault{StatusCode: http.StatusOK,
+		Body: map[string]interface{}{"Identities": []venafi.Identity{local, ad, bob}}})
+
+	// Both alices match the username, and neither is the only user.
+	for _, username := range []string{`CORP\alice`, "carol"} {
+		_, err := selfClient(t, srv, username).Identity.Self()
+		var selfErr *venafi.SelfIdentityError
+		if !errors.As(err, &selfErr) {
+			t.Errorf("Self as %s: got %v, want *SelfIdentityError", username, err)
+			continue
+		}
+		if selfErr.Username != username || len(selfErr.Candidates) != 3 {
+			t.Errorf("Self as %s: error %+v", username, selfErr)
+		}
+	}
+}
+
+func TestSelfIsCachedPerAPIKey(t *testing.T) {
+	v, srv, transport := newCountingClient(t)
+	srv.AddGroup("Admins", srv.Identity)
+
+	for i := 0; i < 3; i++ {
+		if _, err := v.Identity.Self(); err != nil {
+			t.Fatalf("Self: %v", err)
+		}
+	}
+	if _, err := v.Identity.Session(); err != nil {
+		t.Fatalf("Session: %v", err)
+	}
+	if n := transport.count("/vedsdk/Identity/Self"); n != 1 {
+		t.Errorf("Identity/Self called %d times with one API key, want 1", n)
+	}
+
+	// Authenticating again issues a new API key, which drops the cached
+	// identity.
+	v.APIKey = ""
+	srv.Identity = srv.AddUser("operator", nil)
+	me, err := v.Identity.Self()
+	if err != nil {
+		t.Fatalf("Self: %v", err)
+	}
+	if n := transport.count("/vedsdk/Identity/Self"); n != 2 {
+		t.Errorf("Identity/Self called %d times after a new API key, want 2", n)
+	}
+	if me.PrefixedName != "local:operator" {
+		t.Errorf("Self after a new API key = %s, want local:operator", me.PrefixedName)
+	}
+}
+
+// addLookupDirectory adds identities with overlapping names to srv.
+// It returns alice, alicia and the two groups called ops.
+func addLookupDirectory(srv *venafitest.Server) (venafi.Identity, venafi.Identity, venafi.Identity, venafi.Identity) {
+	alice := srv.AddUser("alice", nil)
+	alicia := srv.AddUser("alicia", nil)
+	localOps := srv.AddGroup("ops")
+	adOps := srv.AddIdentity(venafi.Identity{
+		FullName:          "CN=Ops,OU=Groups,DC=corp,DC=example",
+		IsGroup:           true,
+		Name:              "Ops",
+		Prefix:            "AD+corp",
+		PrefixedName:      "AD+corp:Ops",
+		PrefixedUniversal: "AD+corp:{0f5c}",
+		Universal:         "{0f5c}",
+	}, nil)
+	return alice, alicia, localOps, adOps
+}
+
+func TestBrowse(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	alice, alicia, localOps, adOps := addLookupDirectory(srv)
+
+	names := func(ids []venafi.Identity) []string {
+		rv := make([]string, len(ids))
+		for i, id := range ids {
+			rv[i] = id.PrefixedName
+		}
+		return rv
+	}
+	tests := []struct {
+		filter string
+		limit  int
+		types  venafi.IdentityType
+		want   []string
+	}{
+		{"ALI", 0, 0, []string{alice.PrefixedName, alicia.PrefixedName}},
+		{"ali", 1, 0, []string{alice.PrefixedName}},
+		{"ops", 0, 0, []string{adOps.PrefixedName, localOps.PrefixedName}},
+		{"CN=Ops,OU=Groups", 0, 0, []string{adOps.PrefixedName}},
+		{"", 0, venafi.IdentitySecurityGroup, []string{adOps.PrefixedName, localOps.PrefixedName}},
+	}
+	for _, tt := range tests {
+		ids, err := v.Identity.Browse(tt.filter, tt.limit, tt.types)
+		if err != nil {
+			t.Fatalf("Browse(%q): %v", tt.filter, err)
+		}
+		if got := names(ids); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Browse(%q, %d, %d) = %q, want %q", tt.filter, tt.limit, tt.types, got, tt.want)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	alice, _, localOps, adOps := addLookupDirectory(srv)
+
+	tests := []struct {
+		name string
+		want venafi.Identity
+	}{
+		// A plain name only matches whole names, so alicia is not a candidate.
+		{"alice", alice},
+		{"ALICE", alice},
+		{alice.PrefixedName, alice},
+		{alice.PrefixedUniversal, alice},
+		{"AD+corp:Ops", adOps},
+		{localOps.PrefixedUniversal, localOps},
+		// A distinguished name matches in full or by its leading part.
+		{"CN=Ops,OU=Groups,DC=corp,DC=example", adOps},
+		{"cn=ops,ou=groups", adOps},
+	}
+	for _, tt := range tests {
+		id, err := v.Identity.Lookup(tt.name)
+		if err != nil {
+			t.Errorf("Lookup(%q): %v", tt.name, err)
+			continue
+		}
+		if id.PrefixedUniversal != tt.want.PrefixedUniversal {
+			t.Errorf("Lookup(%q) = %s, want %s", tt.name, id.PrefixedName, tt.want.PrefixedName)
+		}
+	}
+
+	// Both groups are called ops.
+	_, err := v.Identity.Lookup("ops")
+	if err == nil || errors.Is(err, venafi.ErrNotFound) ||
+		!strings.Contains(err.Error(), adOps.PrefixedName) || !strings.Contains(err.Error(), localOps.PrefixedName) {
+		t.Errorf("Lookup of an ambiguous name: got %v, want an error listing both groups", err)
+	}
+
+	for _, name := range []string{"ali", "CN=Ops,OU=Other", "local:nobody"} {
+		if _, err := v.Identity.Lookup(name); !errors.Is(err, venafi.ErrNotFound) {
+			t.Errorf("Lookup(%q): got %v, want ErrNotFound", name, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package venafi
+
+import (
+	"crypto"
+	"crypto/x509"
+	"net/http"
+
+	"github.com/tradel/venafi-tpp/pkg/const/ca"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+// ConfigAPI is the set of Config operations provided by ConfigService.
+type ConfigAPI interface {
+	Create(objectDN string, className string, attributes map[string]string) (*ConfigObject, error)
+	IsValid(objectDN string, objectGUID string) (*ConfigObject, error)
+	Exists(objectDN string) bool
+	Retrieve(objectDN string) (*ConfigObject, error)
+	DefaultDN() (string, error)
+	Delete(objectDN string, recursive bool) error
+	Enumerate(objectDN string, recursive bool, filter string) ([]ConfigObject, error)
+	AddValue(objectDN string, name string, value string) error
+	ClearAttribute(objectDN string, name string) error
+	Read(objectDN string, name string) ([]string, error)
+	ReadAll(objectDN string) (map[string][]string, error)
+	Write(objectDN string, attributes map[string][]string) error
+	Find(pattern string, attributeNames ...string) ([]ConfigObject, error)
+	FindObjectsOfClass(classNames []string, objectDN string, recursive bool, pattern string) ([]ConfigObject, error)
+	FindContainers(objectDN string, recursive bool) ([]ConfigObject, error)
+	FindPolicy(objectDN string, className string, attributeName string) (*PolicyValue, error)
+	DnToGuid(objectDN string) (string, error)
+	GuidToDn(objectGUID string) (string, error)
+	IdInfo(objectID string) (*ConfigObject, error)
+	ReadDn(objectDN string, name string) ([]string, error)
+	RenameObject(oldDN string, newDN string) (*ConfigObject, error)
+	Move(objectDN string, newParentDN string) (*ConfigObject, error)
+	ReadEffectivePolicy(objectDN string, attributeName string) (*PolicyValue, error)
+	ReadPolicy(policyDN string, className string, attributeName string) (*PolicyValue, error)
+	WritePolicy(policyDN string, className string, attributeName string, values []string, locked bool) error
+	AddPolicyValue(policyDN string, className string, attributeName string, value string, locked bool) error
+	ClearPolicyAttribute(policyDN string, className string, attributeName string) error
+	RemoveValue(objectDN string, name string, value string) error
+	RemoveDnValue(objectDN string, name string, value string) error
+	WriteDn(objectDN string, name string, values []string) error
+	AttributeSyntax(name string) (config.AttributeSyntax, error)
+	ReadTyped(objectDN string, name string) ([]interface{}, error)
+	GetRevision(objectDN string) (int64, error)
+}
+
+// CertificateAPI is the set of certificate operations provided by
+// CertificateService.
+type CertificateAPI interface {
+	List() ([]Certificate, error)
+	Retrieve(certDN string) (*x509.Certificate, crypto.Signer, error)
+	Import(parentDN string, objectName string, cert *x509.Certificate, pk crypto.Signer, reconcile bool) (*ImportCertificateOutput, error)
+}
+
+// X509StoreAPI is the set of X509CertificateStore operations provided by
+// X509StoreService.
+type X509StoreAPI interface {
+	Add(cert *x509.Certificate, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error)
+	Lookup(cert *x509.Certificate, ownerDN string, name string, value string) ([]int, error)
+	LookupByCertificate(cert *x509.Certificate) ([]int, error)
+	LookupByOwnerDN(ownerDN string) ([]int, error)
+	LookupByNameValue(name string, value string) ([]int, error)
+	LookupExpiring(days int, ownerDN string) ([]int, error)
+	Retrieve(vaultID int) (*x509.Certificate, error)
+	Remove(vaultID int, ownerDN string) error
+	RetrieveItem(vaultID int) (*VaultItem, error)
+	RetrievePrivateKey(vaultID int, password string) (crypto.Signer, error)
+	AddPrivateKey(key crypto.Signer, certVaultID int, protectionKey secret_store.ProtectionKey) (int, error)
+	LookupPrivateKeys(certVaultID int) ([]int, error)
+}
+
+// IdentityAPI is the set of identity operations provided by IdentityService.
+type IdentityAPI interface {
+	Self() (*Identity, error)
+	Session() (*SessionIdentity, error)
+	Validate(id *Identity) (*Identity, error)
+	Browse(filter string, limit int, identityTypes IdentityType) ([]Identity, error)
+	GetAssociatedEntries(id *Identity) ([]Identity, error)
+	ReadAttribute(id *Identity, attributeName string) ([]string, error)
+	Lookup(name string) (*Identity, error)
+	GetMembers(group *Identity, resolveNested bool) ([]Identity, error)
+	GetMemberships(id *Identity) ([]Identity, error)
+	AddGroup(name string, members []Identity) (*Identity, error)
+	AddGroupMembers(group *Identity, members []Identity) error
+	RemoveGroupMembers(group *Identity, members []Identity) error
+	RenameGroup(group *Identity, newName string) (*Identity, error)
+	DeleteGroup(group *Identity) error
+}
+
+// PolicyAPI is the set of policy folder operations provided by PolicyService.
+type PolicyAPI interface {
+	Create(objectDN string) (*ConfigObject, error)
+	Delete(objectDN string, recursive bool) error
+	Exists(objectDN string) bool
+}
+
+// CAAPI is the set of CA template operations provided by CAService.
+type CAAPI interface {
+	Create(objectDN string, className string, driverName string, extraProperties map[string]string) (*ConfigObject, error)
+	CreateSelfSigned(objectDN string, keyUsage ca.KeyUsage, signingAlgorithm string, sanEnabled bool, validityDays int) (*ConfigObject, error)
+	CreateOpenSSL(objectDN string, hostname string, sshKeyDN string, sshPort int,
+		caConfigFilePath string, caCertDir string, caRootCertFile string, caPrivateKeyFile string, certPrivateKeyDN string,
+		tempDir string, sanEnabled bool, copyExtensions bool, maxValidityYears int) (*ConfigObject, error)
+	Delete(objectDN string, recursive bool) error
+}
+
+// SchemaAPI is the set of schema operations provided by SchemaService.
+type SchemaAPI interface {
+	HighestRevision(objectDN string, classNames ...string) (int64, error)
+	Class(className string) (*ClassDefinition, error)
+	Attributes() ([]AttributeDefinition, error)
+	Containment(className string) ([]string, error)
+	ValidateCreate(objectDN string, className string, attributes map[string]string) error
+}
+
+// PermissionsAPI is the set of object permission operations provided by
+// PermissionsService.
+type PermissionsAPI interface {
+	List(objectDN string) ([]Identity, error)
+	Get(objectDN string, principal *Identity) (*ObjectPermissions, error)
+	Effective(objectDN string, principal *Identity) (*Permissions, error)
+	Set(objectDN string, principal *Identity, perms Permissions) error
+	Remove(objectDN string, principal *Identity) error
+	Grant(objectDN string, principal *Identity, role Permissions) error
+}
+
+// SecretStoreAPI is the set of Secret Store operations provided by
+// SecretStoreService.
+type SecretStoreAPI interface {
+	Add(data []byte, vaultType secret_store.VaultType, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error)
+	Retrieve(vaultID int) ([]byte, secret_store.VaultType, error)
+	Associate(vaultID int, name string, value interface{}) error
+	Dissociate(vaultID int, name string, value interface{}) error
+	LookupByAssociation(name string, value interface{}) ([]int, error)
+	LookupByOwner(ownerDN string, vaultType secret_store.VaultType) ([]int, error)
+	Mutate(vaultID int, vaultType secret_store.VaultType) error
+	OwnerAdd(vaultID int, ownerDN string) error
+	OwnerDelete(vaultID int, ownerDN string) error
+	OwnerLookup(vaultID int) ([]string, error)
+	EncryptionKeysInUse() ([]secret_store.ProtectionKey, error)
+	ReEncrypt(vaultID int, protectionKey secret_store.ProtectionKey) error
+}
+
+// CryptoAPI is the set of protection key operations provided by
+// CryptoService.
+type CryptoAPI interface {
+	AvailableKeys() ([]secret_store.ProtectionKey, error)
+	DefaultKey() (secret_store.ProtectionKey, error)
+}
+
+var (
+	_ ConfigAPI      = (*ConfigService)(nil)
+	_ CertificateAPI = (*CertificateService)(nil)
+	_ X509StoreAPI   = (*X509StoreService)(nil)
+	_ IdentityAPI    = (*IdentityService)(nil)
+	_ PolicyAPI      = (*PolicyService)(nil)
+	_ CAAPI          = (*CAService)(nil)
+	_ SchemaAPI      = (*SchemaService)(nil)
+	_ PermissionsAPI = (*PermissionsService)(nil)
+	_ SecretStoreAPI = (*SecretStoreService)(nil)
+	_ CryptoAPI      = (*CryptoService)(nil)
+)
+
+// Services holds alternative implementations of the client's services. Any
+// field left nil keeps the default implementation.
+type Services struct {
+	X509Store   X509StoreAPI
+	Identity    IdentityAPI
+	Config      ConfigAPI
+	Policy      PolicyAPI
+	CA          CAAPI
+	Certs       CertificateAPI
+	Schema      SchemaAPI
+	Permissions PermissionsAPI
+	SecretStore SecretStoreAPI
+	Crypto      CryptoAPI
+}
+
+// NewClientWithServices is like NewClient but replaces any of the client's
+// services with the implementations given in services. The default PolicyService
+// and CAService call through the client's Config and Identity fields, so
+// replacing those also changes their behavior.
+func NewClientWithServices(httpAddress string, username string, password string, httpClient *http.Client,
+	services Services) (*Client, error) {
+	c, err := NewClient(httpAddress, username, password, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if services.X509Store != nil {
+		c.X509Store = services.X509Store
+	}
+	if services.Identity != nil {
+		c.Identity = services.Identity
+	}
+	if services.Config != nil {
+		c.Config = services.Config
+	}
+	if services.Policy != nil {
+		c.Policy = services.Policy
+	}
+	if services.CA != nil {
+		c.CA = services.CA
+	}
+	if services.Certs != nil {
+		c.Certs = services.Certs
+	}
+	if services.Schema != nil {
+		c.Schema = services.Schema
+	}
+	if services.Permissions != nil {
+		c.Permissions = services.Permissions
+	}
+	if services.SecretStore != nil {
+		c.SecretStore = services.SecretStore
+	}
+	if services.Crypto != nil {
+		c.Crypto = services.Crypto
+	}
+
+	return c, nil
+}
@@ -0,0 +1,56 @@
+package venafi_test
+
+import (
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/mocks"
+	"github.com/tradel/venafi-tpp/pkg/const/ca"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+func TestNewClientWithServices(t *testing.T) {
+	me := &venafi.Identity{PrefixedName: "local:admin", PrefixedUniversal: "local:{1}"}
+	ids := &mocks.IdentityAPI{
+		SelfFunc: func() (*venafi.Identity, error) { return me, nil },
+	}
+	cfg := &mocks.ConfigAPI{
+		CreateFunc: func(objectDN string, className string, attributes map[string]string) (*venafi.ConfigObject, error) {
+			return &venafi.ConfigObject{DN: objectDN, Class: className}, nil
+		},
+	}
+
+	// Nothing listens at this address, so any call that bypasses the mocks
+	// fails.
+	v, err := venafi.NewClientWithServices("http://127.0.0.1:1", "admin", "secret", nil,
+		venafi.Services{Identity: ids, Config: cfg})
+	if err != nil {
+		t.Fatalf("NewClientWithServices: %v", err)
+	}
+	if v.Identity != ids || v.Config != cfg {
+		t.Fatal("the client does not hold the mocks it was given")
+	}
+
+	if _, err := v.Policy.Create(`\VED\Policy\Web`); err != nil {
+		t.Fatalf("Policy.Create: %v", err)
+	}
+	if _, err := v.CA.CreateSelfSigned(`\VED\Policy\CA\Self`, ca.KeyUsageDigitalSignature, "", true, 365); err != nil {
+		t.Fatalf("CA.CreateSelfSigned: %v", err)
+	}
+
+	if n := len(ids.SelfCalls()); n != 2 {
+		t.Errorf("Self called %d times, want once each by PolicyService and CAService", n)
+	}
+	calls := cfg.CreateCalls()
+	if len(calls) != 2 {
+		t.Fatalf("Config.Create called %d times, want 2", len(calls))
+	}
+	if c := calls[0]; c.ObjectDN != `\VED\Policy\Web` || c.ClassName != config.ClassPolicy ||
+		c.Attributes["Contact"] != me.PrefixedUniversal {
+		t.Errorf("PolicyService created %+v", c)
+	}
+	if c := calls[1]; c.ObjectDN != `\VED\Policy\CA\Self` || c.ClassName != config.ClassSelfSignedCA ||
+		c.Attributes["Contact"] != me.PrefixedUniversal || c.Attributes["Driver Name"] != config.DriverSelfSigned {
+		t.Errorf("CAService created %+v", c)
+	}
+}
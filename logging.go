@@ -0,0 +1,112 @@
+package venafi
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/tradel/venafi-tpp/pkg/redact"
+)
+
+// redactingLogger wraps an hclog.Logger and masks secrets in every message
+// and string argument before passing them on.
+type redactingLogger struct {
+	hclog.Logger
+}
+
+// NewRedactingLogger returns a logger that masks passwords, API keys, private
+// keys, certificate data and vault contents before writing to the underlying
+// logger.
+func NewRedactingLogger(logger hclog.Logger) hclog.Logger {
+	if _, ok := logger.(*redactingLogger); ok {
+		return logger
+	}
+	return &redactingLogger{logger}
+}
+
+func (l *redactingLogger) Trace(msg string, args ...interface{}) {
+	l.Logger.Trace(redact.String(msg), redactArgs(args)...)
+}
+
+func (l *redactingLogger) Debug(msg string, args ...interface{}) {
+	l.Logger.Debug(redact.String(msg), redactArgs(args)...)
+}
+
+func (l *redactingLogger) Info(msg string, args ...interface{}) {
+	l.Logger.Info(redact.String(msg), redactArgs(args)...)
+}
+
+func (l *redactingLogger) Warn(msg string, args ...interface{}) {
+	l.Logger.Warn(redact.String(msg), redactArgs(args)...)
+}
+
+func (l *redactingLogger) Error(msg string, args ...interface{}) {
+	l.Logger.Error(redact.String(msg), redactArgs(args)...)
+}
+
+func (l *redactingLogger) With(args ...interface{}) hclog.Logger {
+	return &redactingLogger{l.Logger.With(redactArgs(args)...)}
+}
+
+func (l *redactingLogger) Named(name string) hclog.Logger {
+	return &redactingLogger{l.Logger.Named(name)}
+}
+
+func (l *redactingLogger) ResetNamed(name string) hclog.Logger {
+	return &redactingLogger{l.Logger.ResetNamed(name)}
+}
+
+// StandardLogger returns a standard library logger whose output is masked
+// like any other message.
+func (l *redactingLogger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter returns a writer that masks what is written to it before
+// passing it to the underlying logger's standard writer.
+func (l *redactingLogger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	if opts == nil {
+		opts = &hclog.StandardLoggerOptions{}
+	}
+	return &redactingWriter{l.Logger.StandardWriter(opts)}
+}
+
+// redactingWriter masks secrets in each write. The standard library logger
+// writes one line per call, so secrets are never split across writes.
+type redactingWriter struct {
+	w io.Writer
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := w.w.Write([]byte(redact.String(string(p)))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func redactArgs(args []interface{}) []interface{} {
+	out := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			out[i] = redact.String(v)
+		case []byte:
+			out[i] = redact.String(string(v))
+		case fmt.Stringer:
+			out[i] = redact.String(v.String())
+		default:
+			out[i] = arg
+		}
+	}
+	return out
+}
+
+// defaultLogger is used when the caller does not supply a logger. It logs at
+// Info so that request and response dumps are not emitted unless asked for.
+func defaultLogger() hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:  "venafi",
+		Level: hclog.Info,
+	})
+}
@@ -0,0 +1,80 @@
+package venafi_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"github.com/tradel/venafi-tpp/pkg/redact"
+)
+
+func TestDebugLogRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := hclog.New(&hclog.LoggerOptions{Output: &buf, Level: hclog.Debug})
+	v, srv := newTestClient(t, logger)
+
+	secret := []byte("correct horse battery staple")
+	id, err := v.SecretStore.Add(secret, secret_store.VaultTypePassword, `\VED\Policy`, "")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	got, _, err := v.SecretStore.Retrieve(id)
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Fatalf("Retrieve returned %q, want %q", got, secret)
+	}
+
+	out := buf.String()
+	for _, leak := range []string{base64.StdEncoding.EncodeToString(secret), srv.Password, v.APIKey} {
+		if strings.Contains(out, leak) {
+			t.Errorf("debug log contains secret %q", leak)
+		}
+	}
+	if !strings.Contains(out, `"Base64Data":"`+redact.Mask+`"`) {
+		t.Errorf("debug log does not contain masked Base64Data:\n%s", out)
+	}
+}
+
+func TestRedactString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`{"Username":"bob","Password":"hunter2"}`, `{"Username":"bob","Password":"` + redact.Mask + `"}`},
+		{`{"APIKey" : "abc\"def"}`, `{"APIKey" : "` + redact.Mask + `"}`},
+		{`{"Base64Data":"c2VjcmV0","VaultType":32}`, `{"Base64Data":"` + redact.Mask + `","VaultType":32}`},
+		{"X-Venafi-Api-Key: 1234\r\nAccept: */*", "X-Venafi-Api-Key: " + redact.Mask + "\r\nAccept: */*"},
+		{`{"Name":"Password"}`, `{"Name":"Password"}`},
+	}
+	for _, tt := range tests {
+		if got := redact.String(tt.in); got != tt.want {
+			t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStandardLoggerRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	logger := venafi.NewRedactingLogger(hclog.New(&hclog.LoggerOptions{Output: &buf, Level: hclog.Debug}))
+
+	logger.StandardLogger(nil).Printf(`{"Username":"bob","Password":"hunter2"}`)
+	fmt.Fprintln(logger.StandardWriter(&hclog.StandardLoggerOptions{InferLevels: true}),
+		`[DEBUG] {"APIKey":"0123-4567"}`)
+
+	out := buf.String()
+	for _, leak := range []string{"hunter2", "0123-4567"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("standard logger output contains secret %q:\n%s", leak, out)
+		}
+	}
+	if strings.Count(out, redact.Mask) != 2 || !strings.Contains(out, "[DEBUG]") {
+		t.Errorf("standard logger output is missing masked lines or levels:\n%s", out)
+	}
+}
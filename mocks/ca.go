@@ -0,0 +1,328 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/ca"
+	"sync"
+)
+
+// Ensure, that CAAPI does implement venafi.CAAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.CAAPI = &CAAPI{}
+
+// CAAPI is a mock implementation of venafi.CAAPI.
+//
+//	func TestSomethingThatUsesCAAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.CAAPI
+//		mockedCAAPI := &CAAPI{
+//			CreateFunc: func(objectDN string, className string, driverName string, extraProperties map[string]string) (*venafi.ConfigObject, error) {
+//				panic("mock out the Create method")
+//			},
+//			CreateOpenSSLFunc: func(objectDN string, hostname string, sshKeyDN string, sshPort int, caConfigFilePath string, caCertDir string, caRootCertFile string, caPrivateKeyFile string, certPrivateKeyDN string, tempDir string, sanEnabled bool, copyExtensions bool, maxValidityYears int) (*venafi.ConfigObject, error) {
+//				panic("mock out the CreateOpenSSL method")
+//			},
+//			CreateSelfSignedFunc: func(objectDN string, keyUsage ca.KeyUsage, signingAlgorithm string, sanEnabled bool, validityDays int) (*venafi.ConfigObject, error) {
+//				panic("mock out the CreateSelfSigned method")
+//			},
+//			DeleteFunc: func(objectDN string, recursive bool) error {
+//				panic("mock out the Delete method")
+//			},
+//		}
+//
+//		// use mockedCAAPI in code that requires venafi.CAAPI
+//		// and then make assertions.
+//
+//	}
+type CAAPI struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(objectDN string, className string, driverName string, extraProperties map[string]string) (*venafi.ConfigObject, error)
+
+	// CreateOpenSSLFunc mocks the CreateOpenSSL method.
+	CreateOpenSSLFunc func(objectDN string, hostname string, sshKeyDN string, sshPort int, caConfigFilePath string, caCertDir string, caRootCertFile string, caPrivateKeyFile string, certPrivateKeyDN string, tempDir string, sanEnabled bool, copyExtensions bool, maxValidityYears int) (*venafi.ConfigObject, error)
+
+	// CreateSelfSignedFunc mocks the CreateSelfSigned method.
+	CreateSelfSignedFunc func(objectDN string, keyUsage ca.KeyUsage, signingAlgorithm string, sanEnabled bool, validityDays int) (*venafi.ConfigObject, error)
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(objectDN string, recursive bool) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// ClassName is the className argument value.
+			ClassName string
+			// DriverName is the driverName argument value.
+			DriverName string
+			// ExtraProperties is the extraProperties argument value.
+			ExtraProperties map[string]string
+		}
+		// CreateOpenSSL holds details about calls to the CreateOpenSSL method.
+		CreateOpenSSL []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Hostname is the hostname argument value.
+			Hostname string
+			// SshKeyDN is the sshKeyDN argument value.
+			SshKeyDN string
+			// SshPort is the sshPort argument value.
+			SshPort int
+			// CaConfigFilePath is the caConfigFilePath argument value.
+			CaConfigFilePath string
+			// CaCertDir is the caCertDir argument value.
+			CaCertDir string
+			// CaRootCertFile is the caRootCertFile argument value.
+			CaRootCertFile string
+			// CaPrivateKeyFile is the caPrivateKeyFile argument value.
+			CaPrivateKeyFile string
+			// CertPrivateKeyDN is the certPrivateKeyDN argument value.
+			CertPrivateKeyDN string
+			// TempDir is the tempDir argument value.
+			TempDir string
+			// SanEnabled is the sanEnabled argument value.
+			SanEnabled bool
+			// CopyExtensions is the copyExtensions argument value.
+			CopyExtensions bool
+			// MaxValidityYears is the maxValidityYears argument value.
+			MaxValidityYears int
+		}
+		// CreateSelfSigned holds details about calls to the CreateSelfSigned method.
+		CreateSelfSigned []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// KeyUsage is the keyUsage argument value.
+			KeyUsage ca.KeyUsage
+			// SigningAlgorithm is the signingAlgorithm argument value.
+			SigningAlgorithm string
+			// SanEnabled is the sanEnabled argument value.
+			SanEnabled bool
+			// ValidityDays is the validityDays argument value.
+			ValidityDays int
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Recursive is the recursive argument value.
+			Recursive bool
+		}
+	}
+	lockCreate           sync.RWMutex
+	lockCreateOpenSSL    sync.RWMutex
+	lockCreateSelfSigned sync.RWMutex
+	lockDelete           sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *CAAPI) Create(objectDN string, className string, driverName string, extraProperties map[string]string) (*venafi.ConfigObject, error) {
+	if mock.CreateFunc == nil {
+		panic("CAAPI.CreateFunc: method is nil but CAAPI.Create was just called")
+	}
+	callInfo := struct {
+		ObjectDN        string
+		ClassName       string
+		DriverName      string
+		ExtraProperties map[string]string
+	}{
+		ObjectDN:        objectDN,
+		ClassName:       className,
+		DriverName:      driverName,
+		ExtraProperties: extraProperties,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(objectDN, className, driverName, extraProperties)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedCAAPI.CreateCalls())
+func (mock *CAAPI) CreateCalls() []struct {
+	ObjectDN        string
+	ClassName       string
+	DriverName      string
+	ExtraProperties map[string]string
+} {
+	var calls []struct {
+		ObjectDN        string
+		ClassName       string
+		DriverName      string
+		ExtraProperties map[string]string
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// CreateOpenSSL calls CreateOpenSSLFunc.
+func (mock *CAAPI) CreateOpenSSL(objectDN string, hostname string, sshKeyDN string, sshPort int, caConfigFilePath string, caCertDir string, caRootCertFile string, caPrivateKeyFile string, certPrivateKeyDN string, tempDir string, sanEnabled bool, copyExtensions bool, maxValidityYears int) (*venafi.ConfigObject, error) {
+	if mock.CreateOpenSSLFunc == nil {
+		panic("CAAPI.CreateOpenSSLFunc: method is nil but CAAPI.CreateOpenSSL was just called")
+	}
+	callInfo := struct {
+		ObjectDN         string
+		Hostname         string
+		SshKeyDN         string
+		SshPort          int
+		CaConfigFilePath string
+		CaCertDir        string
+		CaRootCertFile   string
+		CaPrivateKeyFile string
+		CertPrivateKeyDN string
+		TempDir          string
+		SanEnabled       bool
+		CopyExtensions   bool
+		MaxValidityYears int
+	}{
+		ObjectDN:         objectDN,
+		Hostname:         hostname,
+		SshKeyDN:         sshKeyDN,
+		SshPort:          sshPort,
+		CaConfigFilePath: caConfigFilePath,
+		CaCertDir:        caCertDir,
+		CaRootCertFile:   caRootCertFile,
+		CaPrivateKeyFile: caPrivateKeyFile,
+		CertPrivateKeyDN: certPrivateKeyDN,
+		TempDir:          tempDir,
+		SanEnabled:       sanEnabled,
+		CopyExtensions:   copyExtensions,
+		MaxValidityYears: maxValidityYears,
+	}
+	mock.lockCreateOpenSSL.Lock()
+	mock.calls.CreateOpenSSL = append(mock.calls.CreateOpenSSL, callInfo)
+	mock.lockCreateOpenSSL.Unlock()
+	return mock.CreateOpenSSLFunc(objectDN, hostname, sshKeyDN, sshPort, caConfigFilePath, caCertDir, caRootCertFile, caPrivateKeyFile, certPrivateKeyDN, tempDir, sanEnabled, copyExtensions, maxValidityYears)
+}
+
+// CreateOpenSSLCalls gets all the calls that were made to CreateOpenSSL.
+// Check the length with:
+//
+//	len(mockedCAAPI.CreateOpenSSLCalls())
+func (mock *CAAPI) CreateOpenSSLCalls() []struct {
+	ObjectDN         string
+	Hostname         string
+	SshKeyDN         string
+	SshPort          int
+	CaConfigFilePath string
+	CaCertDir        string
+	CaRootCertFile   string
+	CaPrivateKeyFile string
+	CertPrivateKeyDN string
+	TempDir          string
+	SanEnabled       bool
+	CopyExtensions   bool
+	MaxValidityYears int
+} {
+	var calls []struct {
+		ObjectDN         string
+		Hostname         string
+		SshKeyDN         string
+		SshPort          int
+		CaConfigFilePath string
+		CaCertDir        string
+		CaRootCertFile   string
+		CaPrivateKeyFile string
+		CertPrivateKeyDN string
+		TempDir          string
+		SanEnabled       bool
+		CopyExtensions   bool
+		MaxValidityYears int
+	}
+	mock.lockCreateOpenSSL.RLock()
+	calls = mock.calls.CreateOpenSSL
+	mock.lockCreateOpenSSL.RUnlock()
+	return calls
+}
+
+// CreateSelfSigned calls CreateSelfSignedFunc.
+func (mock *CAAPI) CreateSelfSigned(objectDN string, keyUsage ca.KeyUsage, signingAlgorithm string, sanEnabled bool, validityDays int) (*venafi.ConfigObject, error) {
+	if mock.CreateSelfSignedFunc == nil {
+		panic("CAAPI.CreateSelfSignedFunc: method is nil but CAAPI.CreateSelfSigned was just called")
+	}
+	callInfo := struct {
+		ObjectDN         string
+		KeyUsage         ca.KeyUsage
+		SigningAlgorithm string
+		SanEnabled       bool
+		ValidityDays     int
+	}{
+		ObjectDN:         objectDN,
+		KeyUsage:         keyUsage,
+		SigningAlgorithm: signingAlgorithm,
+		SanEnabled:       sanEnabled,
+		ValidityDays:     validityDays,
+	}
+	mock.lockCreateSelfSigned.Lock()
+	mock.calls.CreateSelfSigned = append(mock.calls.CreateSelfSigned, callInfo)
+	mock.lockCreateSelfSigned.Unlock()
+	return mock.CreateSelfSignedFunc(objectDN, keyUsage, signingAlgorithm, sanEnabled, validityDays)
+}
+
+// CreateSelfSignedCalls gets all the calls that were made to CreateSelfSigned.
+// Check the length with:
+//
+//	len(mockedCAAPI.CreateSelfSignedCalls())
+func (mock *CAAPI) CreateSelfSignedCalls() []struct {
+	ObjectDN         string
+	KeyUsage         ca.KeyUsage
+	SigningAlgorithm string
+	SanEnabled       bool
+	ValidityDays     int
+} {
+	var calls []struct {
+		ObjectDN         string
+		KeyUsage         ca.KeyUsage
+		SigningAlgorithm string
+		SanEnabled       bool
+		ValidityDays     int
+	}
+	mock.lockCreateSelfSigned.RLock()
+	calls = mock.calls.CreateSelfSigned
+	mock.lockCreateSelfSigned.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *CAAPI) Delete(objectDN string, recursive bool) error {
+	if mock.DeleteFunc == nil {
+		panic("CAAPI.DeleteFunc: method is nil but CAAPI.Delete was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Recursive bool
+	}{
+		ObjectDN:  objectDN,
+		Recursive: recursive,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(objectDN, recursive)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedCAAPI.DeleteCalls())
+func (mock *CAAPI) DeleteCalls() []struct {
+	ObjectDN  string
+	Recursive bool
+} {
+	var calls []struct {
+		ObjectDN  string
+		Recursive bool
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
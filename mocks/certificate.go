@@ -0,0 +1,182 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"crypto"
+	"crypto/x509"
+	"github.com/tradel/venafi-tpp"
+	"sync"
+)
+
+// Ensure, that CertificateAPI does implement venafi.CertificateAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.CertificateAPI = &CertificateAPI{}
+
+// CertificateAPI is a mock implementation of venafi.CertificateAPI.
+//
+//	func TestSomethingThatUsesCertificateAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.CertificateAPI
+//		mockedCertificateAPI := &CertificateAPI{
+//			ImportFunc: func(parentDN string, objectName string, cert *x509.Certificate, pk crypto.Signer, reconcile bool) (*venafi.ImportCertificateOutput, error) {
+//				panic("mock out the Import method")
+//			},
+//			ListFunc: func() ([]venafi.Certificate, error) {
+//				panic("mock out the List method")
+//			},
+//			RetrieveFunc: func(certDN string) (*x509.Certificate, crypto.Signer, error) {
+//				panic("mock out the Retrieve method")
+//			},
+//		}
+//
+//		// use mockedCertificateAPI in code that requires venafi.CertificateAPI
+//		// and then make assertions.
+//
+//	}
+type CertificateAPI struct {
+	// ImportFunc mocks the Import method.
+	ImportFunc func(parentDN string, objectName string, cert *x509.Certificate, pk crypto.Signer, reconcile bool) (*venafi.ImportCertificateOutput, error)
+
+	// ListFunc mocks the List method.
+	ListFunc func() ([]venafi.Certificate, error)
+
+	// RetrieveFunc mocks the Retrieve method.
+	RetrieveFunc func(certDN string) (*x509.Certificate, crypto.Signer, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Import holds details about calls to the Import method.
+		Import []struct {
+			// ParentDN is the parentDN argument value.
+			ParentDN string
+			// ObjectName is the objectName argument value.
+			ObjectName string
+			// Cert is the cert argument value.
+			Cert *x509.Certificate
+			// Pk is the pk argument value.
+			Pk crypto.Signer
+			// Reconcile is the reconcile argument value.
+			Reconcile bool
+		}
+		// List holds details about calls to the List method.
+		List []struct {
+		}
+		// Retrieve holds details about calls to the Retrieve method.
+		Retrieve []struct {
+			// CertDN is the certDN argument value.
+			CertDN string
+		}
+	}
+	lockImport   sync.RWMutex
+	lockList     sync.RWMutex
+	lockRetrieve sync.RWMutex
+}
+
+// Import calls ImportFunc.
+func (mock *CertificateAPI) Import(parentDN string, objectName string, cert *x509.Certificate, pk crypto.Signer, reconcile bool) (*venafi.ImportCertificateOutput, error) {
+	if mock.ImportFunc == nil {
+		panic("CertificateAPI.ImportFunc: method is nil but CertificateAPI.Import was just called")
+	}
+	callInfo := struct {
+		ParentDN   string
+		ObjectName string
+		Cert       *x509.Certificate
+		Pk         crypto.Signer
+		Reconcile  bool
+	}{
+		ParentDN:   parentDN,
+		ObjectName: objectName,
+		Cert:       cert,
+		Pk:         pk,
+		Reconcile:  reconcile,
+	}
+	mock.lockImport.Lock()
+	mock.calls.Import = append(mock.calls.Import, callInfo)
+	mock.lockImport.Unlock()
+	return mock.ImportFunc(parentDN, objectName, cert, pk, reconcile)
+}
+
+// ImportCalls gets all the calls that were made to Import.
+// Check the length with:
+//
+//	len(mockedCertificateAPI.ImportCalls())
+func (mock *CertificateAPI) ImportCalls() []struct {
+	ParentDN   string
+	ObjectName string
+	Cert       *x509.Certificate
+	Pk         crypto.Signer
+	Reconcile  bool
+} {
+	var calls []struct {
+		ParentDN   string
+		ObjectName string
+		Cert       *x509.Certificate
+		Pk         crypto.Signer
+		Reconcile  bool
+	}
+	mock.lockImport.RLock()
+	calls = mock.calls.Import
+	mock.lockImport.RUnlock()
+	return calls
+}
+
+// List calls ListFunc.
+func (mock *CertificateAPI) List() ([]venafi.Certificate, error) {
+	if mock.ListFunc == nil {
+		panic("CertificateAPI.ListFunc: method is nil but CertificateAPI.List was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockList.Lock()
+	mock.calls.List = append(mock.calls.List, callInfo)
+	mock.lockList.Unlock()
+	return mock.ListFunc()
+}
+
+// ListCalls gets all the calls that were made to List.
+// Check the length with:
+//
+//	len(mockedCertificateAPI.ListCalls())
+func (mock *CertificateAPI) ListCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockList.RLock()
+	calls = mock.calls.List
+	mock.lockList.RUnlock()
+	return calls
+}
+
+// Retrieve calls RetrieveFunc.
+func (mock *CertificateAPI) Retrieve(certDN string) (*x509.Certificate, crypto.Signer, error) {
+	if mock.RetrieveFunc == nil {
+		panic("CertificateAPI.RetrieveFunc: method is nil but CertificateAPI.Retrieve was just called")
+	}
+	callInfo := struct {
+		CertDN string
+	}{
+		CertDN: certDN,
+	}
+	mock.lockRetrieve.Lock()
+	mock.calls.Retrieve = append(mock.calls.Retrieve, callInfo)
+	mock.lockRetrieve.Unlock()
+	return mock.RetrieveFunc(certDN)
+}
+
+// RetrieveCalls gets all the calls that were made to Retrieve.
+// Check the length with:
+//
+//	len(mockedCertificateAPI.RetrieveCalls())
+func (mock *CertificateAPI) RetrieveCalls() []struct {
+	CertDN string
+} {
+	var calls []struct {
+		CertDN string
+	}
+	mock.lockRetrieve.RLock()
+	calls = mock.calls.Retrieve
+	mock.lockRetrieve.RUnlock()
+	return calls
+}
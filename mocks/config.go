@@ -0,0 +1,1723 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/tradel/venafi-tpp"
+	tppconfig "github.com/tradel/venafi-tpp/pkg/const/config"
+	"sync"
+)
+
+// Ensure, that ConfigAPI does implement venafi.ConfigAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.ConfigAPI = &ConfigAPI{}
+
+// ConfigAPI is a mock implementation of venafi.ConfigAPI.
+//
+//	func TestSomethingThatUsesConfigAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.ConfigAPI
+//		mockedConfigAPI := &ConfigAPI{
+//			AddPolicyValueFunc: func(policyDN string, className string, attributeName string, value string, locked bool) error {
+//				panic("mock out the AddPolicyValue method")
+//			},
+//			AddValueFunc: func(objectDN string, name string, value string) error {
+//				panic("mock out the AddValue method")
+//			},
+//			AttributeSyntaxFunc: func(name string) (tppconfig.AttributeSyntax, error) {
+//				panic("mock out the AttributeSyntax method")
+//			},
+//			ClearAttributeFunc: func(objectDN string, name string) error {
+//				panic("mock out the ClearAttribute method")
+//			},
+//			ClearPolicyAttributeFunc: func(policyDN string, className string, attributeName string) error {
+//				panic("mock out the ClearPolicyAttribute method")
+//			},
+//			CreateFunc: func(objectDN string, className string, attributes map[string]string) (*venafi.ConfigObject, error) {
+//				panic("mock out the Create method")
+//			},
+//			DefaultDNFunc: func() (string, error) {
+//				panic("mock out the DefaultDN method")
+//			},
+//			DeleteFunc: func(objectDN string, recursive bool) error {
+//				panic("mock out the Delete method")
+//			},
+//			DnToGuidFunc: func(objectDN string) (string, error) {
+//				panic("mock out the DnToGuid method")
+//			},
+//			EnumerateFunc: func(objectDN string, recursive bool, filter string) ([]venafi.ConfigObject, error) {
+//				panic("mock out the Enumerate method")
+//			},
+//			ExistsFunc: func(objectDN string) bool {
+//				panic("mock out the Exists method")
+//			},
+//			FindFunc: func(pattern string, attributeNames ...string) ([]venafi.ConfigObject, error) {
+//				panic("mock out the Find method")
+//			},
+//			FindContainersFunc: func(objectDN string, recursive bool) ([]venafi.ConfigObject, error) {
+//				panic("mock out the FindContainers method")
+//			},
+//			FindObjectsOfClassFunc: func(classNames []string, objectDN string, recursive bool, pattern string) ([]venafi.ConfigObject, error) {
+//				panic("mock out the FindObjectsOfClass method")
+//			},
+//			FindPolicyFunc: func(objectDN string, className string, attributeName string) (*venafi.PolicyValue, error) {
+//				panic("mock out the FindPolicy method")
+//			},
+//			GetRevisionFunc: func(objectDN string) (int64, error) {
+//				panic("mock out the GetRevision method")
+//			},
+//			GuidToDnFunc: func(objectGUID string) (string, error) {
+//				panic("mock out the GuidToDn method")
+//			},
+//			IdInfoFunc: func(objectID string) (*venafi.ConfigObject, error) {
+//				panic("mock out the IdInfo method")
+//			},
+//			IsValidFunc: func(objectDN string, objectGUID string) (*venafi.ConfigObject, error) {
+//				panic("mock out the IsValid method")
+//			},
+//			MoveFunc: func(objectDN string, newParentDN string) (*venafi.ConfigObject, error) {
+//				panic("mock out the Move method")
+//			},
+//			ReadFunc: func(objectDN string, name string) ([]string, error) {
+//				panic("mock out the Read method")
+//			},
+//			ReadAllFunc: func(objectDN string) (map[string][]string, error) {
+//				panic("mock out the ReadAll method")
+//			},
+//			ReadDnFunc: func(objectDN string, name string) ([]string, error) {
+//				panic("mock out the ReadDn method")
+//			},
+//			ReadEffectivePolicyFunc: func(objectDN string, attributeName string) (*venafi.PolicyValue, error) {
+//				panic("mock out the ReadEffectivePolicy method")
+//			},
+//			ReadPolicyFunc: func(policyDN string, className string, attributeName string) (*venafi.PolicyValue, error) {
+//				panic("mock out the ReadPolicy method")
+//			},
+//			ReadTypedFunc: func(objectDN string, name string) ([]interface{}, error) {
+//				panic("mock out the ReadTyped method")
+//			},
+//			RemoveDnValueFunc: func(objectDN string, name string, value string) error {
+//				panic("mock out the RemoveDnValue method")
+//			},
+//			RemoveValueFunc: func(objectDN string, name string, value string) error {
+//				panic("mock out the RemoveValue method")
+//			},
+//			RenameObjectFunc: func(oldDN string, newDN string) (*venafi.ConfigObject, error) {
+//				panic("mock out the RenameObject method")
+//			},
+//			RetrieveFunc: func(objectDN string) (*venafi.ConfigObject, error) {
+//				panic("mock out the Retrieve method")
+//			},
+//			WriteFunc: func(objectDN string, attributes map[string][]string) error {
+//				panic("mock out the Write method")
+//			},
+//			WriteDnFunc: func(objectDN string, name string, values []string) error {
+//				panic("mock out the WriteDn method")
+//			},
+//			WritePolicyFunc: func(policyDN string, className string, attributeName string, values []string, locked bool) error {
+//				panic("mock out the WritePolicy method")
+//			},
+//		}
+//
+//		// use mockedConfigAPI in code that requires venafi.ConfigAPI
+//		// and then make assertions.
+//
+//	}
+type ConfigAPI struct {
+	// AddPolicyValueFunc mocks the AddPolicyValue method.
+	AddPolicyValueFunc func(policyDN string, className string, attributeName string, value string, locked bool) error
+
+	// AddValueFunc mocks the AddValue method.
+	AddValueFunc func(objectDN string, name string, value string) error
+
+	// AttributeSyntaxFunc mocks the AttributeSyntax method.
+	AttributeSyntaxFunc func(name string) (tppconfig.AttributeSyntax, error)
+
+	// ClearAttributeFunc mocks the ClearAttribute method.
+	ClearAttributeFunc func(objectDN string, name string) error
+
+	// ClearPolicyAttributeFunc mocks the ClearPolicyAttribute method.
+	ClearPolicyAttributeFunc func(policyDN string, className string, attributeName string) error
+
+	// CreateFunc mocks the Create method.
+	CreateFunc func(objectDN string, className string, attributes map[string]string) (*venafi.ConfigObject, error)
+
+	// DefaultDNFunc mocks the DefaultDN method.
+	DefaultDNFunc func() (string, error)
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(objectDN string, recursive bool) error
+
+	// DnToGuidFunc mocks the DnToGuid method.
+	DnToGuidFunc func(objectDN string) (string, error)
+
+	// EnumerateFunc mocks the Enumerate method.
+	EnumerateFunc func(objectDN string, recursive bool, filter string) ([]venafi.ConfigObject, error)
+
+	// ExistsFunc mocks the Exists method.
+	ExistsFunc func(objectDN string) bool
+
+	// FindFunc mocks the Find method.
+	FindFunc func(pattern string, attributeNames ...string) ([]venafi.ConfigObject, error)
+
+	// FindContainersFunc mocks the FindContainers method.
+	FindContainersFunc func(objectDN string, recursive bool) ([]venafi.ConfigObject, error)
+
+	// FindObjectsOfClassFunc mocks the FindObjectsOfClass method.
+	FindObjectsOfClassFunc func(classNames []string, objectDN string, recursive bool, pattern string) ([]venafi.ConfigObject, error)
+
+	// FindPolicyFunc mocks the FindPolicy method.
+	FindPolicyFunc func(objectDN string, className string, attributeName string) (*venafi.PolicyValue, error)
+
+	// GetRevisionFunc mocks the GetRevision method.
+	GetRevisionFunc func(objectDN string) (int64, error)
+
+	// GuidToDnFunc mocks the GuidToDn method.
+	GuidToDnFunc func(objectGUID string) (string, error)
+
+	// IdInfoFunc mocks the IdInfo method.
+	IdInfoFunc func(objectID string) (*venafi.ConfigObject, error)
+
+	// IsValidFunc mocks the IsValid method.
+	IsValidFunc func(objectDN string, objectGUID string) (*venafi.ConfigObject, error)
+
+	// MoveFunc mocks the Move method.
+	MoveFunc func(objectDN string, newParentDN string) (*venafi.ConfigObject, error)
+
+	// ReadFunc mocks the Read method.
+	ReadFunc func(objectDN string, name string) ([]string, error)
+
+	// ReadAllFunc mocks the ReadAll method.
+	ReadAllFunc func(objectDN string) (map[string][]string, error)
+
+	// ReadDnFunc mocks the ReadDn method.
+	ReadDnFunc func(objectDN string, name string) ([]string, error)
+
+	// ReadEffectivePolicyFunc mocks the ReadEffectivePolicy method.
+	ReadEffectivePolicyFunc func(objectDN string, attributeName string) (*venafi.PolicyValue, error)
+
+	// ReadPolicyFunc mocks the ReadPolicy method.
+	ReadPolicyFunc func(policyDN string, className string, attributeName string) (*venafi.PolicyValue, error)
+
+	// ReadTypedFunc mocks the ReadTyped method.
+	ReadTypedFunc func(objectDN string, name string) ([]interface{}, error)
+
+	// RemoveDnValueFunc mocks the RemoveDnValue method.
+	RemoveDnValueFunc func(objectDN string, name string, value string) error
+
+	// RemoveValueFunc mocks the RemoveValue method.
+	RemoveValueFunc func(objectDN string, name string, value string) error
+
+	// RenameObjectFunc mocks the RenameObject method.
+	RenameObjectFunc func(oldDN string, newDN string) (*venafi.ConfigObject, error)
+
+	// RetrieveFunc mocks the Retrieve method.
+	RetrieveFunc func(objectDN string) (*venafi.ConfigObject, error)
+
+	// WriteFunc mocks the Write method.
+	WriteFunc func(objectDN string, attributes map[string][]string) error
+
+	// WriteDnFunc mocks the WriteDn method.
+	WriteDnFunc func(objectDN string, name string, values []string) error
+
+	// WritePolicyFunc mocks the WritePolicy method.
+	WritePolicyFunc func(policyDN string, className string, attributeName string, values []string, locked bool) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddPolicyValue holds details about calls to the AddPolicyValue method.
+		AddPolicyValue []struct {
+			// PolicyDN is the policyDN argument value.
+			PolicyDN string
+			// ClassName is the className argument value.
+			ClassName string
+			// AttributeName is the attributeName argument value.
+			AttributeName string
+			// Value is the value argument value.
+			Value string
+			// Locked is the locked argument value.
+			Locked bool
+		}
+		// AddValue holds details about calls to the AddValue method.
+		AddValue []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value string
+		}
+		// AttributeSyntax holds details about calls to the AttributeSyntax method.
+		AttributeSyntax []struct {
+			// Name is the name argument value.
+			Name string
+		}
+		// ClearAttribute holds details about calls to the ClearAttribute method.
+		ClearAttribute []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Name is the name argument value.
+			Name string
+		}
+		// ClearPolicyAttribute holds details about calls to the ClearPolicyAttribute method.
+		ClearPolicyAttribute []struct {
+			// PolicyDN is the policyDN argument value.
+			PolicyDN string
+			// ClassName is the className argument value.
+			ClassName string
+			// AttributeName is the attributeName argument value.
+			AttributeName string
+		}
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// ClassName is the className argument value.
+			ClassName string
+			// Attributes is the attributes argument value.
+			Attributes map[string]string
+		}
+		// DefaultDN holds details about calls to the DefaultDN method.
+		DefaultDN []struct {
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Recursive is the recursive argument value.
+			Recursive bool
+		}
+		// DnToGuid holds details about calls to the DnToGuid method.
+		DnToGuid []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+		}
+		// Enumerate holds details about calls to the Enumerate method.
+		Enumerate []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Recursive is the recursive argument value.
+			Recursive bool
+			// Filter is the filter argument value.
+			Filter string
+		}
+		// Exists holds details about calls to the Exists method.
+		Exists []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+		}
+		// Find holds details about calls to the Find method.
+		Find []struct {
+			// Pattern is the pattern argument value.
+			Pattern string
+			// AttributeNames is the attributeNames argument value.
+			AttributeNames []string
+		}
+		// FindContainers holds details about calls to the FindContainers method.
+		FindContainers []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Recursive is the recursive argument value.
+			Recursive bool
+		}
+		// FindObjectsOfClass holds details about calls to the FindObjectsOfClass method.
+		FindObjectsOfClass []struct {
+			// ClassNames is the classNames argument value.
+			ClassNames []string
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Recursive is the recursive argument value.
+			Recursive bool
+			// Pattern is the pattern argument value.
+			Pattern string
+		}
+		// FindPolicy holds details about calls to the FindPolicy method.
+		FindPolicy []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// ClassName is the className argument value.
+			ClassName string
+			// AttributeName is the attributeName argument value.
+			AttributeName string
+		}
+		// GetRevision holds details about calls to the GetRevision method.
+		GetRevision []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+		}
+		// GuidToDn holds details about calls to the GuidToDn method.
+		GuidToDn []struct {
+			// ObjectGUID is the objectGUID argument value.
+			ObjectGUID string
+		}
+		// IdInfo holds details about calls to the IdInfo method.
+		IdInfo []struct {
+			// ObjectID is the objectID argument value.
+			ObjectID string
+		}
+		// IsValid holds details about calls to the IsValid method.
+		IsValid []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// ObjectGUID is the objectGUID argument value.
+			ObjectGUID string
+		}
+		// Move holds details about calls to the Move method.
+		Move []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// NewParentDN is the newParentDN argument value.
+			NewParentDN string
+		}
+		// Read holds details about calls to the Read method.
+		Read []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Name is the name argument value.
+			Name string
+		}
+		// ReadAll holds details about calls to the ReadAll method.
+		ReadAll []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+		}
+		// ReadDn holds details about calls to the ReadDn method.
+		ReadDn []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Name is the name argument value.
+			Name string
+		}
+		// ReadEffectivePolicy holds details about calls to the ReadEffectivePolicy method.
+		ReadEffectivePolicy []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// AttributeName is the attributeName argument value.
+			AttributeName string
+		}
+		// ReadPolicy holds details about calls to the ReadPolicy method.
+		ReadPolicy []struct {
+			// PolicyDN is the policyDN argument value.
+			PolicyDN string
+			// ClassName is the className argument value.
+			ClassName string
+			// AttributeName is the attributeName argument value.
+			AttributeName string
+		}
+		// ReadTyped holds details about calls to the ReadTyped method.
+		ReadTyped []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Name is the name argument value.
+			Name string
+		}
+		// RemoveDnValue holds details about calls to the RemoveDnValue method.
+		RemoveDnValue []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value string
+		}
+		// RemoveValue holds details about calls to the RemoveValue method.
+		RemoveValue []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value string
+		}
+		// RenameObject holds details about calls to the RenameObject method.
+		RenameObject []struct {
+			// OldDN is the oldDN argument value.
+			OldDN string
+			// NewDN is the newDN argument value.
+			NewDN string
+		}
+		// Retrieve holds details about calls to the Retrieve method.
+		Retrieve []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+		}
+		// Write holds details about calls to the Write method.
+		Write []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Attributes is the attributes argument value.
+			Attributes map[string][]string
+		}
+		// WriteDn holds details about calls to the WriteDn method.
+		WriteDn []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Name is the name argument value.
+			Name string
+			// Values is the values argument value.
+			Values []string
+		}
+		// WritePolicy holds details about calls to the WritePolicy method.
+		WritePolicy []struct {
+			// PolicyDN is the policyDN argument value.
+			PolicyDN string
+			// ClassName is the className argument value.
+			ClassName string
+			// AttributeName is the attributeName argument value.
+			AttributeName string
+			// Values is the values argument value.
+			Values []string
+			// Locked is the locked argument value.
+			Locked bool
+		}
+	}
+	lockAddPolicyValue       sync.RWMutex
+	lockAddValue             sync.RWMutex
+	lockAttributeSyntax      sync.RWMutex
+	lockClearAttribute       sync.RWMutex
+	lockClearPolicyAttribute sync.RWMutex
+	lockCreate               sync.RWMutex
+	lockDefaultDN            sync.RWMutex
+	lockDelete               sync.RWMutex
+	lockDnToGuid             sync.RWMutex
+	lockEnumerate            sync.RWMutex
+	lockExists               sync.RWMutex
+	lockFind                 sync.RWMutex
+	lockFindContainers       sync.RWMutex
+	lockFindObjectsOfClass   sync.RWMutex
+	lockFindPolicy           sync.RWMutex
+	lockGetRevision          sync.RWMutex
+	lockGuidToDn             sync.RWMutex
+	lockIdInfo               sync.RWMutex
+	lockIsValid              sync.RWMutex
+	lockMove                 sync.RWMutex
+	lockRead                 sync.RWMutex
+	lockReadAll              sync.RWMutex
+	lockReadDn               sync.RWMutex
+	lockReadEffectivePolicy  sync.RWMutex
+	lockReadPolicy           sync.RWMutex
+	lockReadTyped            sync.RWMutex
+	lockRemoveDnValue        sync.RWMutex
+	lockRemoveValue          sync.RWMutex
+	lockRenameObject         sync.RWMutex
+	lockRetrieve             sync.RWMutex
+	lockWrite                sync.RWMutex
+	lockWriteDn              sync.RWMutex
+	lockWritePolicy          sync.RWMutex
+}
+
+// AddPolicyValue calls AddPolicyValueFunc.
+func (mock *ConfigAPI) AddPolicyValue(policyDN string, className string, attributeName string, value string, locked bool) error {
+	if mock.AddPolicyValueFunc == nil {
+		panic("ConfigAPI.AddPolicyValueFunc: method is nil but ConfigAPI.AddPolicyValue was just called")
+	}
+	callInfo := struct {
+		PolicyDN      string
+		ClassName     string
+		AttributeName string
+		Value         string
+		Locked        bool
+	}{
+		PolicyDN:      policyDN,
+		ClassName:     className,
+		AttributeName: attributeName,
+		Value:         value,
+		Locked:        locked,
+	}
+	mock.lockAddPolicyValue.Lock()
+	mock.calls.AddPolicyValue = append(mock.calls.AddPolicyValue, callInfo)
+	mock.lockAddPolicyValue.Unlock()
+	return mock.AddPolicyValueFunc(policyDN, className, attributeName, value, locked)
+}
+
+// AddPolicyValueCalls gets all the calls that were made to AddPolicyValue.
+// Check the length with:
+//
+//	len(mockedConfigAPI.AddPolicyValueCalls())
+func (mock *ConfigAPI) AddPolicyValueCalls() []struct {
+	PolicyDN      string
+	ClassName     string
+	AttributeName string
+	Value         string
+	Locked        bool
+} {
+	var calls []struct {
+		PolicyDN      string
+		ClassName     string
+		AttributeName string
+		Value         string
+		Locked        bool
+	}
+	mock.lockAddPolicyValue.RLock()
+	calls = mock.calls.AddPolicyValue
+	mock.lockAddPolicyValue.RUnlock()
+	return calls
+}
+
+// AddValue calls AddValueFunc.
+func (mock *ConfigAPI) AddValue(objectDN string, name string, value string) error {
+	if mock.AddValueFunc == nil {
+		panic("ConfigAPI.AddValueFunc: method is nil but ConfigAPI.AddValue was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+		Name     string
+		Value    string
+	}{
+		ObjectDN: objectDN,
+		Name:     name,
+		Value:    value,
+	}
+	mock.lockAddValue.Lock()
+	mock.calls.AddValue = append(mock.calls.AddValue, callInfo)
+	mock.lockAddValue.Unlock()
+	return mock.AddValueFunc(objectDN, name, value)
+}
+
+// AddValueCalls gets all the calls that were made to AddValue.
+// Check the length with:
+//
+//	len(mockedConfigAPI.AddValueCalls())
+func (mock *ConfigAPI) AddValueCalls() []struct {
+	ObjectDN string
+	Name     string
+	Value    string
+} {
+	var calls []struct {
+		ObjectDN string
+		Name     string
+		Value    string
+	}
+	mock.lockAddValue.RLock()
+	calls = mock.calls.AddValue
+	mock.lockAddValue.RUnlock()
+	return calls
+}
+
+// AttributeSyntax calls AttributeSyntaxFunc.
+func (mock *ConfigAPI) AttributeSyntax(name string) (tppconfig.AttributeSyntax, error) {
+	if mock.AttributeSyntaxFunc == nil {
+		panic("ConfigAPI.AttributeSyntaxFunc: method is nil but ConfigAPI.AttributeSyntax was just called")
+	}
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockAttributeSyntax.Lock()
+	mock.calls.AttributeSyntax = append(mock.calls.AttributeSyntax, callInfo)
+	mock.lockAttributeSyntax.Unlock()
+	return mock.AttributeSyntaxFunc(name)
+}
+
+// AttributeSyntaxCalls gets all the calls that were made to AttributeSyntax.
+// Check the length with:
+//
+//	len(mockedConfigAPI.AttributeSyntaxCalls())
+func (mock *ConfigAPI) AttributeSyntaxCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
+	}
+	mock.lockAttributeSyntax.RLock()
+	calls = mock.calls.AttributeSyntax
+	mock.lockAttributeSyntax.RUnlock()
+	return calls
+}
+
+// ClearAttribute calls ClearAttributeFunc.
+func (mock *ConfigAPI) ClearAttribute(objectDN string, name string) error {
+	if mock.ClearAttributeFunc == nil {
+		panic("ConfigAPI.ClearAttributeFunc: method is nil but ConfigAPI.ClearAttribute was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+		Name     string
+	}{
+		ObjectDN: objectDN,
+		Name:     name,
+	}
+	mock.lockClearAttribute.Lock()
+	mock.calls.ClearAttribute = append(mock.calls.ClearAttribute, callInfo)
+	mock.lockClearAttribute.Unlock()
+	return mock.ClearAttributeFunc(objectDN, name)
+}
+
+// ClearAttributeCalls gets all the calls that were made to ClearAttribute.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ClearAttributeCalls())
+func (mock *ConfigAPI) ClearAttributeCalls() []struct {
+	ObjectDN string
+	Name     string
+} {
+	var calls []struct {
+		ObjectDN string
+		Name     string
+	}
+	mock.lockClearAttribute.RLock()
+	calls = mock.calls.ClearAttribute
+	mock.lockClearAttribute.RUnlock()
+	return calls
+}
+
+// ClearPolicyAttribute calls ClearPolicyAttributeFunc.
+func (mock *ConfigAPI) ClearPolicyAttribute(policyDN string, className string, attributeName string) error {
+	if mock.ClearPolicyAttributeFunc == nil {
+		panic("ConfigAPI.ClearPolicyAttributeFunc: method is nil but ConfigAPI.ClearPolicyAttribute was just called")
+	}
+	callInfo := struct {
+		PolicyDN      string
+		ClassName     string
+		AttributeName string
+	}{
+		PolicyDN:      policyDN,
+		ClassName:     className,
+		AttributeName: attributeName,
+	}
+	mock.lockClearPolicyAttribute.Lock()
+	mock.calls.ClearPolicyAttribute = append(mock.calls.ClearPolicyAttribute, callInfo)
+	mock.lockClearPolicyAttribute.Unlock()
+	return mock.ClearPolicyAttributeFunc(policyDN, className, attributeName)
+}
+
+// ClearPolicyAttributeCalls gets all the calls that were made to ClearPolicyAttribute.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ClearPolicyAttributeCalls())
+func (mock *ConfigAPI) ClearPolicyAttributeCalls() []struct {
+	PolicyDN      string
+	ClassName     string
+	AttributeName string
+} {
+	var calls []struct {
+		PolicyDN      string
+		ClassName     string
+		AttributeName string
+	}
+	mock.lockClearPolicyAttribute.RLock()
+	calls = mock.calls.ClearPolicyAttribute
+	mock.lockClearPolicyAttribute.RUnlock()
+	return calls
+}
+
+// Create calls CreateFunc.
+func (mock *ConfigAPI) Create(objectDN string, className string, attributes map[string]string) (*venafi.ConfigObject, error) {
+	if mock.CreateFunc == nil {
+		panic("ConfigAPI.CreateFunc: method is nil but ConfigAPI.Create was just called")
+	}
+	callInfo := struct {
+		ObjectDN   string
+		ClassName  string
+		Attributes map[string]string
+	}{
+		ObjectDN:   objectDN,
+		ClassName:  className,
+		Attributes: attributes,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(objectDN, className, attributes)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedConfigAPI.CreateCalls())
+func (mock *ConfigAPI) CreateCalls() []struct {
+	ObjectDN   string
+	ClassName  string
+	Attributes map[string]string
+} {
+	var calls []struct {
+		ObjectDN   string
+		ClassName  string
+		Attributes map[string]string
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// DefaultDN calls DefaultDNFunc.
+func (mock *ConfigAPI) DefaultDN() (string, error) {
+	if mock.DefaultDNFunc == nil {
+		panic("ConfigAPI.DefaultDNFunc: method is nil but ConfigAPI.DefaultDN was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockDefaultDN.Lock()
+	mock.calls.DefaultDN = append(mock.calls.DefaultDN, callInfo)
+	mock.lockDefaultDN.Unlock()
+	return mock.DefaultDNFunc()
+}
+
+// DefaultDNCalls gets all the calls that were made to DefaultDN.
+// Check the length with:
+//
+//	len(mockedConfigAPI.DefaultDNCalls())
+func (mock *ConfigAPI) DefaultDNCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockDefaultDN.RLock()
+	calls = mock.calls.DefaultDN
+	mock.lockDefaultDN.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *ConfigAPI) Delete(objectDN string, recursive bool) error {
+	if mock.DeleteFunc == nil {
+		panic("ConfigAPI.DeleteFunc: method is nil but ConfigAPI.Delete was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Recursive bool
+	}{
+		ObjectDN:  objectDN,
+		Recursive: recursive,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(objectDN, recursive)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedConfigAPI.DeleteCalls())
+func (mock *ConfigAPI) DeleteCalls() []struct {
+	ObjectDN  string
+	Recursive bool
+} {
+	var calls []struct {
+		ObjectDN  string
+		Recursive bool
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// DnToGuid calls DnToGuidFunc.
+func (mock *ConfigAPI) DnToGuid(objectDN string) (string, error) {
+	if mock.DnToGuidFunc == nil {
+		panic("ConfigAPI.DnToGuidFunc: method is nil but ConfigAPI.DnToGuid was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+	}{
+		ObjectDN: objectDN,
+	}
+	mock.lockDnToGuid.Lock()
+	mock.calls.DnToGuid = append(mock.calls.DnToGuid, callInfo)
+	mock.lockDnToGuid.Unlock()
+	return mock.DnToGuidFunc(objectDN)
+}
+
+// DnToGuidCalls gets all the calls that were made to DnToGuid.
+// Check the length with:
+//
+//	len(mockedConfigAPI.DnToGuidCalls())
+func (mock *ConfigAPI) DnToGuidCalls() []struct {
+	ObjectDN string
+} {
+	var calls []struct {
+		ObjectDN string
+	}
+	mock.lockDnToGuid.RLock()
+	calls = mock.calls.DnToGuid
+	mock.lockDnToGuid.RUnlock()
+	return calls
+}
+
+// Enumerate calls EnumerateFunc.
+func (mock *ConfigAPI) Enumerate(objectDN string, recursive bool, filter string) ([]venafi.ConfigObject, error) {
+	if mock.EnumerateFunc == nil {
+		panic("ConfigAPI.EnumerateFunc: method is nil but ConfigAPI.Enumerate was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Recursive bool
+		Filter    string
+	}{
+		ObjectDN:  objectDN,
+		Recursive: recursive,
+		Filter:    filter,
+	}
+	mock.lockEnumerate.Lock()
+	mock.calls.Enumerate = append(mock.calls.Enumerate, callInfo)
+	mock.lockEnumerate.Unlock()
+	return mock.EnumerateFunc(objectDN, recursive, filter)
+}
+
+// EnumerateCalls gets all the calls that were made to Enumerate.
+// Check the length with:
+//
+//	len(mockedConfigAPI.EnumerateCalls())
+func (mock *ConfigAPI) EnumerateCalls() []struct {
+	ObjectDN  string
+	Recursive bool
+	Filter    string
+} {
+	var calls []struct {
+		ObjectDN  string
+		Recursive bool
+		Filter    string
+	}
+	mock.lockEnumerate.RLock()
+	calls = mock.calls.Enumerate
+	mock.lockEnumerate.RUnlock()
+	return calls
+}
+
+// Exists calls ExistsFunc.
+func (mock *ConfigAPI) Exists(objectDN string) bool {
+	if mock.ExistsFunc == nil {
+		panic("ConfigAPI.ExistsFunc: method is nil but ConfigAPI.Exists was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+	}{
+		ObjectDN: objectDN,
+	}
+	mock.lockExists.Lock()
+	mock.calls.Exists = append(mock.calls.Exists, callInfo)
+	mock.lockExists.Unlock()
+	return mock.ExistsFunc(objectDN)
+}
+
+// ExistsCalls gets all the calls that were made to Exists.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ExistsCalls())
+func (mock *ConfigAPI) ExistsCalls() []struct {
+	ObjectDN string
+} {
+	var calls []struct {
+		ObjectDN string
+	}
+	mock.lockExists.RLock()
+	calls = mock.calls.Exists
+	mock.lockExists.RUnlock()
+	return calls
+}
+
+// Find calls FindFunc.
+func (mock *ConfigAPI) Find(pattern string, attributeNames ...string) ([]venafi.ConfigObject, error) {
+	if mock.FindFunc == nil {
+		panic("ConfigAPI.FindFunc: method is nil but ConfigAPI.Find was just called")
+	}
+	callInfo := struct {
+		Pattern        string
+		AttributeNames []string
+	}{
+		Pattern:        pattern,
+		AttributeNames: attributeNames,
+	}
+	mock.lockFind.Lock()
+	mock.calls.Find = append(mock.calls.Find, callInfo)
+	mock.lockFind.Unlock()
+	return mock.FindFunc(pattern, attributeNames...)
+}
+
+// FindCalls gets all the calls that were made to Find.
+// Check the length with:
+//
+//	len(mockedConfigAPI.FindCalls())
+func (mock *ConfigAPI) FindCalls() []struct {
+	Pattern        string
+	AttributeNames []string
+} {
+	var calls []struct {
+		Pattern        string
+		AttributeNames []string
+	}
+	mock.lockFind.RLock()
+	calls = mock.calls.Find
+	mock.lockFind.RUnlock()
+	return calls
+}
+
+// FindContainers calls FindContainersFunc.
+func (mock *ConfigAPI) FindContainers(objectDN string, recursive bool) ([]venafi.ConfigObject, error) {
+	if mock.FindContainersFunc == nil {
+		panic("ConfigAPI.FindContainersFunc: method is nil but ConfigAPI.FindContainers was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Recursive bool
+	}{
+		ObjectDN:  objectDN,
+		Recursive: recursive,
+	}
+	mock.lockFindContainers.Lock()
+	mock.calls.FindContainers = append(mock.calls.FindContainers, callInfo)
+	mock.lockFindContainers.Unlock()
+	return mock.FindContainersFunc(objectDN, recursive)
+}
+
+// FindContainersCalls gets all the calls that were made to FindContainers.
+// Check the length with:
+//
+//	len(mockedConfigAPI.FindContainersCalls())
+func (mock *ConfigAPI) FindContainersCalls() []struct {
+	ObjectDN  string
+	Recursive bool
+} {
+	var calls []struct {
+		ObjectDN  string
+		Recursive bool
+	}
+	mock.lockFindContainers.RLock()
+	calls = mock.calls.FindContainers
+	mock.lockFindContainers.RUnlock()
+	return calls
+}
+
+// FindObjectsOfClass calls FindObjectsOfClassFunc.
+func (mock *ConfigAPI) FindObjectsOfClass(classNames []string, objectDN string, recursive bool, pattern string) ([]venafi.ConfigObject, error) {
+	if mock.FindObjectsOfClassFunc == nil {
+		panic("ConfigAPI.FindObjectsOfClassFunc: method is nil but ConfigAPI.FindObjectsOfClass was just called")
+	}
+	callInfo := struct {
+		ClassNames []string
+		ObjectDN   string
+		Recursive  bool
+		Pattern    string
+	}{
+		ClassNames: classNames,
+		ObjectDN:   objectDN,
+		Recursive:  recursive,
+		Pattern:    pattern,
+	}
+	mock.lockFindObjectsOfClass.Lock()
+	mock.calls.FindObjectsOfClass = append(mock.calls.FindObjectsOfClass, callInfo)
+	mock.lockFindObjectsOfClass.Unlock()
+	return mock.FindObjectsOfClassFunc(classNames, objectDN, recursive, pattern)
+}
+
+// FindObjectsOfClassCalls gets all the calls that were made to FindObjectsOfClass.
+// Check the length with:
+//
+//	len(mockedConfigAPI.FindObjectsOfClassCalls())
+func (mock *ConfigAPI) FindObjectsOfClassCalls() []struct {
+	ClassNames []string
+	ObjectDN   string
+	Recursive  bool
+	Pattern    string
+} {
+	var calls []struct {
+		ClassNames []string
+		ObjectDN   string
+		Recursive  bool
+		Pattern    string
+	}
+	mock.lockFindObjectsOfClass.RLock()
+	calls = mock.calls.FindObjectsOfClass
+	mock.lockFindObjectsOfClass.RUnlock()
+	return calls
+}
+
+// FindPolicy calls FindPolicyFunc.
+func (mock *ConfigAPI) FindPolicy(objectDN string, className string, attributeName string) (*venafi.PolicyValue, error) {
+	if mock.FindPolicyFunc == nil {
+		panic("ConfigAPI.FindPolicyFunc: method is nil but ConfigAPI.FindPolicy was just called")
+	}
+	callInfo := struct {
+		ObjectDN      string
+		ClassName     string
+		AttributeName string
+	}{
+		ObjectDN:      objectDN,
+		ClassName:     className,
+		AttributeName: attributeName,
+	}
+	mock.lockFindPolicy.Lock()
+	mock.calls.FindPolicy = append(mock.calls.FindPolicy, callInfo)
+	mock.lockFindPolicy.Unlock()
+	return mock.FindPolicyFunc(objectDN, className, attributeName)
+}
+
+// FindPolicyCalls gets all the calls that were made to FindPolicy.
+// Check the length with:
+//
+//	len(mockedConfigAPI.FindPolicyCalls())
+func (mock *ConfigAPI) FindPolicyCalls() []struct {
+	ObjectDN      string
+	ClassName     string
+	AttributeName string
+} {
+	var calls []struct {
+		ObjectDN      string
+		ClassName     string
+		AttributeName string
+	}
+	mock.lockFindPolicy.RLock()
+	calls = mock.calls.FindPolicy
+	mock.lockFindPolicy.RUnlock()
+	return calls
+}
+
+// GetRevision calls GetRevisionFunc.
+func (mock *ConfigAPI) GetRevision(objectDN string) (int64, error) {
+	if mock.GetRevisionFunc == nil {
+		panic("ConfigAPI.GetRevisionFunc: method is nil but ConfigAPI.GetRevision was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+	}{
+		ObjectDN: objectDN,
+	}
+	mock.lockGetRevision.Lock()
+	mock.calls.GetRevision = append(mock.calls.GetRevision, callInfo)
+	mock.lockGetRevision.Unlock()
+	return mock.GetRevisionFunc(objectDN)
+}
+
+// GetRevisionCalls gets all the calls that were made to GetRevision.
+// Check the length with:
+//
+//	len(mockedConfigAPI.GetRevisionCalls())
+func (mock *ConfigAPI) GetRevisionCalls() []struct {
+	ObjectDN string
+} {
+	var calls []struct {
+		ObjectDN string
+	}
+	mock.lockGetRevision.RLock()
+	calls = mock.calls.GetRevision
+	mock.lockGetRevision.RUnlock()
+	return calls
+}
+
+// GuidToDn calls GuidToDnFunc.
+func (mock *ConfigAPI) GuidToDn(objectGUID string) (string, error) {
+	if mock.GuidToDnFunc == nil {
+		panic("ConfigAPI.GuidToDnFunc: method is nil but ConfigAPI.GuidToDn was just called")
+	}
+	callInfo := struct {
+		ObjectGUID string
+	}{
+		ObjectGUID: objectGUID,
+	}
+	mock.lockGuidToDn.Lock()
+	mock.calls.GuidToDn = append(mock.calls.GuidToDn, callInfo)
+	mock.lockGuidToDn.Unlock()
+	return mock.GuidToDnFunc(objectGUID)
+}
+
+// GuidToDnCalls gets all the calls that were made to GuidToDn.
+// Check the length with:
+//
+//	len(mockedConfigAPI.GuidToDnCalls())
+func (mock *ConfigAPI) GuidToDnCalls() []struct {
+	ObjectGUID string
+} {
+	var calls []struct {
+		ObjectGUID string
+	}
+	mock.lockGuidToDn.RLock()
+	calls = mock.calls.GuidToDn
+	mock.lockGuidToDn.RUnlock()
+	return calls
+}
+
+// IdInfo calls IdInfoFunc.
+func (mock *ConfigAPI) IdInfo(objectID string) (*venafi.ConfigObject, error) {
+	if mock.IdInfoFunc == nil {
+		panic("ConfigAPI.IdInfoFunc: method is nil but ConfigAPI.IdInfo was just called")
+	}
+	callInfo := struct {
+		ObjectID string
+	}{
+		ObjectID: objectID,
+	}
+	mock.lockIdInfo.Lock()
+	mock.calls.IdInfo = append(mock.calls.IdInfo, callInfo)
+	mock.lockIdInfo.Unlock()
+	return mock.IdInfoFunc(objectID)
+}
+
+// IdInfoCalls gets all the calls that were made to IdInfo.
+// Check the length with:
+//
+//	len(mockedConfigAPI.IdInfoCalls())
+func (mock *ConfigAPI) IdInfoCalls() []struct {
+	ObjectID string
+} {
+	var calls []struct {
+		ObjectID string
+	}
+	mock.lockIdInfo.RLock()
+	calls = mock.calls.IdInfo
+	mock.lockIdInfo.RUnlock()
+	return calls
+}
+
+// IsValid calls IsValidFunc.
+func (mock *ConfigAPI) IsValid(objectDN string, objectGUID string) (*venafi.ConfigObject, error) {
+	if mock.IsValidFunc == nil {
+		panic("ConfigAPI.IsValidFunc: method is nil but ConfigAPI.IsValid was just called")
+	}
+	callInfo := struct {
+		ObjectDN   string
+		ObjectGUID string
+	}{
+		ObjectDN:   objectDN,
+		ObjectGUID: objectGUID,
+	}
+	mock.lockIsValid.Lock()
+	mock.calls.IsValid = append(mock.calls.IsValid, callInfo)
+	mock.lockIsValid.Unlock()
+	return mock.IsValidFunc(objectDN, objectGUID)
+}
+
+// IsValidCalls gets all the calls that were made to IsValid.
+// Check the length with:
+//
+//	len(mockedConfigAPI.IsValidCalls())
+func (mock *ConfigAPI) IsValidCalls() []struct {
+	ObjectDN   string
+	ObjectGUID string
+} {
+	var calls []struct {
+		ObjectDN   string
+		ObjectGUID string
+	}
+	mock.lockIsValid.RLock()
+	calls = mock.calls.IsValid
+	mock.lockIsValid.RUnlock()
+	return calls
+}
+
+// Move calls MoveFunc.
+func (mock *ConfigAPI) Move(objectDN string, newParentDN string) (*venafi.ConfigObject, error) {
+	if mock.MoveFunc == nil {
+		panic("ConfigAPI.MoveFunc: method is nil but ConfigAPI.Move was just called")
+	}
+	callInfo := struct {
+		ObjectDN    string
+		NewParentDN string
+	}{
+		ObjectDN:    objectDN,
+		NewParentDN: newParentDN,
+	}
+	mock.lockMove.Lock()
+	mock.calls.Move = append(mock.calls.Move, callInfo)
+	mock.lockMove.Unlock()
+	return mock.MoveFunc(objectDN, newParentDN)
+}
+
+// MoveCalls gets all the calls that were made to Move.
+// Check the length with:
+//
+//	len(mockedConfigAPI.MoveCalls())
+func (mock *ConfigAPI) MoveCalls() []struct {
+	ObjectDN    string
+	NewParentDN string
+} {
+	var calls []struct {
+		ObjectDN    string
+		NewParentDN string
+	}
+	mock.lockMove.RLock()
+	calls = mock.calls.Move
+	mock.lockMove.RUnlock()
+	return calls
+}
+
+// Read calls ReadFunc.
+func (mock *ConfigAPI) Read(objectDN string, name string) ([]string, error) {
+	if mock.ReadFunc == nil {
+		panic("ConfigAPI.ReadFunc: method is nil but ConfigAPI.Read was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+		Name     string
+	}{
+		ObjectDN: objectDN,
+		Name:     name,
+	}
+	mock.lockRead.Lock()
+	mock.calls.Read = append(mock.calls.Read, callInfo)
+	mock.lockRead.Unlock()
+	return mock.ReadFunc(objectDN, name)
+}
+
+// ReadCalls gets all the calls that were made to Read.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ReadCalls())
+func (mock *ConfigAPI) ReadCalls() []struct {
+	ObjectDN string
+	Name     string
+} {
+	var calls []struct {
+		ObjectDN string
+		Name     string
+	}
+	mock.lockRead.RLock()
+	calls = mock.calls.Read
+	mock.lockRead.RUnlock()
+	return calls
+}
+
+// ReadAll calls ReadAllFunc.
+func (mock *ConfigAPI) ReadAll(objectDN string) (map[string][]string, error) {
+	if mock.ReadAllFunc == nil {
+		panic("ConfigAPI.ReadAllFunc: method is nil but ConfigAPI.ReadAll was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+	}{
+		ObjectDN: objectDN,
+	}
+	mock.lockReadAll.Lock()
+	mock.calls.ReadAll = append(mock.calls.ReadAll, callInfo)
+	mock.lockReadAll.Unlock()
+	return mock.ReadAllFunc(objectDN)
+}
+
+// ReadAllCalls gets all the calls that were made to ReadAll.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ReadAllCalls())
+func (mock *ConfigAPI) ReadAllCalls() []struct {
+	ObjectDN string
+} {
+	var calls []struct {
+		ObjectDN string
+	}
+	mock.lockReadAll.RLock()
+	calls = mock.calls.ReadAll
+	mock.lockReadAll.RUnlock()
+	return calls
+}
+
+// ReadDn calls ReadDnFunc.
+func (mock *ConfigAPI) ReadDn(objectDN string, name string) ([]string, error) {
+	if mock.ReadDnFunc == nil {
+		panic("ConfigAPI.ReadDnFunc: method is nil but ConfigAPI.ReadDn was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+		Name     string
+	}{
+		ObjectDN: objectDN,
+		Name:     name,
+	}
+	mock.lockReadDn.Lock()
+	mock.calls.ReadDn = append(mock.calls.ReadDn, callInfo)
+	mock.lockReadDn.Unlock()
+	return mock.ReadDnFunc(objectDN, name)
+}
+
+// ReadDnCalls gets all the calls that were made to ReadDn.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ReadDnCalls())
+func (mock *ConfigAPI) ReadDnCalls() []struct {
+	ObjectDN string
+	Name     string
+} {
+	var calls []struct {
+		ObjectDN string
+		Name     string
+	}
+	mock.lockReadDn.RLock()
+	calls = mock.calls.ReadDn
+	mock.lockReadDn.RUnlock()
+	return calls
+}
+
+// ReadEffectivePolicy calls ReadEffectivePolicyFunc.
+func (mock *ConfigAPI) ReadEffectivePolicy(objectDN string, attributeName string) (*venafi.PolicyValue, error) {
+	if mock.ReadEffectivePolicyFunc == nil {
+		panic("ConfigAPI.ReadEffectivePolicyFunc: method is nil but ConfigAPI.ReadEffectivePolicy was just called")
+	}
+	callInfo := struct {
+		ObjectDN      string
+		AttributeName string
+	}{
+		ObjectDN:      objectDN,
+		AttributeName: attributeName,
+	}
+	mock.lockReadEffectivePolicy.Lock()
+	mock.calls.ReadEffectivePolicy = append(mock.calls.ReadEffectivePolicy, callInfo)
+	mock.lockReadEffectivePolicy.Unlock()
+	return mock.ReadEffectivePolicyFunc(objectDN, attributeName)
+}
+
+// ReadEffectivePolicyCalls gets all the calls that were made to ReadEffectivePolicy.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ReadEffectivePolicyCalls())
+func (mock *ConfigAPI) ReadEffectivePolicyCalls() []struct {
+	ObjectDN      string
+	AttributeName string
+} {
+	var calls []struct {
+		ObjectDN      string
+		AttributeName string
+	}
+	mock.lockReadEffectivePolicy.RLock()
+	calls = mock.calls.ReadEffectivePolicy
+	mock.lockReadEffectivePolicy.RUnlock()
+	return calls
+}
+
+// ReadPolicy calls ReadPolicyFunc.
+func (mock *ConfigAPI) ReadPolicy(policyDN string, className string, attributeName string) (*venafi.PolicyValue, error) {
+	if mock.ReadPolicyFunc == nil {
+		panic("ConfigAPI.ReadPolicyFunc: method is nil but ConfigAPI.ReadPolicy was just called")
+	}
+	callInfo := struct {
+		PolicyDN      string
+		ClassName     string
+		AttributeName string
+	}{
+		PolicyDN:      policyDN,
+		ClassName:     className,
+		AttributeName: attributeName,
+	}
+	mock.lockReadPolicy.Lock()
+	mock.calls.ReadPolicy = append(mock.calls.ReadPolicy, callInfo)
+	mock.lockReadPolicy.Unlock()
+	return mock.ReadPolicyFunc(policyDN, className, attributeName)
+}
+
+// ReadPolicyCalls gets all the calls that were made to ReadPolicy.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ReadPolicyCalls())
+func (mock *ConfigAPI) ReadPolicyCalls() []struct {
+	PolicyDN      string
+	ClassName     string
+	AttributeName string
+} {
+	var calls []struct {
+		PolicyDN      string
+		ClassName     string
+		AttributeName string
+	}
+	mock.lockReadPolicy.RLock()
+	calls = mock.calls.ReadPolicy
+	mock.lockReadPolicy.RUnlock()
+	return calls
+}
+
+// ReadTyped calls ReadTypedFunc.
+func (mock *ConfigAPI) ReadTyped(objectDN string, name string) ([]interface{}, error) {
+	if mock.ReadTypedFunc == nil {
+		panic("ConfigAPI.ReadTypedFunc: method is nil but ConfigAPI.ReadTyped was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+		Name     string
+	}{
+		ObjectDN: objectDN,
+		Name:     name,
+	}
+	mock.lockReadTyped.Lock()
+	mock.calls.ReadTyped = append(mock.calls.ReadTyped, callInfo)
+	mock.lockReadTyped.Unlock()
+	return mock.ReadTypedFunc(objectDN, name)
+}
+
+// ReadTypedCalls gets all the calls that were made to ReadTyped.
+// Check the length with:
+//
+//	len(mockedConfigAPI.ReadTypedCalls())
+func (mock *ConfigAPI) ReadTypedCalls() []struct {
+	ObjectDN string
+	Name     string
+} {
+	var calls []struct {
+		ObjectDN string
+		Name     string
+	}
+	mock.lockReadTyped.RLock()
+	calls = mock.calls.ReadTyped
+	mock.lockReadTyped.RUnlock()
+	return calls
+}
+
+// RemoveDnValue calls RemoveDnValueFunc.
+func (mock *ConfigAPI) RemoveDnValue(objectDN string, name string, value string) error {
+	if mock.RemoveDnValueFunc == nil {
+		panic("ConfigAPI.RemoveDnValueFunc: method is nil but ConfigAPI.RemoveDnValue was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+		Name     string
+		Value    string
+	}{
+		ObjectDN: objectDN,
+		Name:     name,
+		Value:    value,
+	}
+	mock.lockRemoveDnValue.Lock()
+	mock.calls.RemoveDnValue = append(mock.calls.RemoveDnValue, callInfo)
+	mock.lockRemoveDnValue.Unlock()
+	return mock.RemoveDnValueFunc(objectDN, name, value)
+}
+
+// RemoveDnValueCalls gets all the calls that were made to RemoveDnValue.
+// Check the length with:
+//
+//	len(mockedConfigAPI.RemoveDnValueCalls())
+func (mock *ConfigAPI) RemoveDnValueCalls() []struct {
+	ObjectDN string
+	Name     string
+	Value    string
+} {
+	var calls []struct {
+		ObjectDN string
+		Name     string
+		Value    string
+	}
+	mock.lockRemoveDnValue.RLock()
+	calls = mock.calls.RemoveDnValue
+	mock.lockRemoveDnValue.RUnlock()
+	return calls
+}
+
+// RemoveValue calls RemoveValueFunc.
+func (mock *ConfigAPI) RemoveValue(objectDN string, name string, value string) error {
+	if mock.RemoveValueFunc == nil {
+		panic("ConfigAPI.RemoveValueFunc: method is nil but ConfigAPI.RemoveValue was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+		Name     string
+		Value    string
+	}{
+		ObjectDN: objectDN,
+		Name:     name,
+		Value:    value,
+	}
+	mock.lockRemoveValue.Lock()
+	mock.calls.RemoveValue = append(mock.calls.RemoveValue, callInfo)
+	mock.lockRemoveValue.Unlock()
+	return mock.RemoveValueFunc(objectDN, name, value)
+}
+
+// RemoveValueCalls gets all the calls that were made to RemoveValue.
+// Check the length with:
+//
+//	len(mockedConfigAPI.RemoveValueCalls())
+func (mock *ConfigAPI) RemoveValueCalls() []struct {
+	ObjectDN string
+	Name     string
+	Value    string
+} {
+	var calls []struct {
+		ObjectDN string
+		Name     string
+		Value    string
+	}
+	mock.lockRemoveValue.RLock()
+	calls = mock.calls.RemoveValue
+	mock.lockRemoveValue.RUnlock()
+	return calls
+}
+
+// RenameObject calls RenameObjectFunc.
+func (mock *ConfigAPI) RenameObject(oldDN string, newDN string) (*venafi.ConfigObject, error) {
+	if mock.RenameObjectFunc == nil {
+		panic("ConfigAPI.RenameObjectFunc: method is nil but ConfigAPI.RenameObject was just called")
+	}
+	callInfo := struct {
+		OldDN string
+		NewDN string
+	}{
+		OldDN: oldDN,
+		NewDN: newDN,
+	}
+	mock.lockRenameObject.Lock()
+	mock.calls.RenameObject = append(mock.calls.RenameObject, callInfo)
+	mock.lockRenameObject.Unlock()
+	return mock.RenameObjectFunc(oldDN, newDN)
+}
+
+// RenameObjectCalls gets all the calls that were made to RenameObject.
+// Check the length with:
+//
+//	len(mockedConfigAPI.RenameObjectCalls())
+func (mock *ConfigAPI) RenameObjectCalls() []struct {
+	OldDN string
+	NewDN string
+} {
+	var calls []struct {
+		OldDN string
+		NewDN string
+	}
+	mock.lockRenameObject.RLock()
+	calls = mock.calls.RenameObject
+	mock.lockRenameObject.RUnlock()
+	return calls
+}
+
+// Retrieve calls RetrieveFunc.
+func (mock *ConfigAPI) Retrieve(objectDN string) (*venafi.ConfigObject, error) {
+	if mock.RetrieveFunc == nil {
+		panic("ConfigAPI.RetrieveFunc: method is nil but ConfigAPI.Retrieve was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+	}{
+		ObjectDN: objectDN,
+	}
+	mock.lockRetrieve.Lock()
+	mock.calls.Retrieve = append(mock.calls.Retrieve, callInfo)
+	mock.lockRetrieve.Unlock()
+	return mock.RetrieveFunc(objectDN)
+}
+
+// RetrieveCalls gets all the calls that were made to Retrieve.
+// Check the length with:
+//
+//	len(mockedConfigAPI.RetrieveCalls())
+func (mock *ConfigAPI) RetrieveCalls() []struct {
+	ObjectDN string
+} {
+	var calls []struct {
+		ObjectDN string
+	}
+	mock.lockRetrieve.RLock()
+	calls = mock.calls.Retrieve
+	mock.lockRetrieve.RUnlock()
+	return calls
+}
+
+// Write calls WriteFunc.
+func (mock *ConfigAPI) Write(objectDN string, attributes map[string][]string) error {
+	if mock.WriteFunc == nil {
+		panic("ConfigAPI.WriteFunc: method is nil but ConfigAPI.Write was just called")
+	}
+	callInfo := struct {
+		ObjectDN   string
+		Attributes map[string][]string
+	}{
+		ObjectDN:   objectDN,
+		Attributes: attributes,
+	}
+	mock.lockWrite.Lock()
+	mock.calls.Write = append(mock.calls.Write, callInfo)
+	mock.lockWrite.Unlock()
+	return mock.WriteFunc(objectDN, attributes)
+}
+
+// WriteCalls gets all the calls that were made to Write.
+// Check the length with:
+//
+//	len(mockedConfigAPI.WriteCalls())
+func (mock *ConfigAPI) WriteCalls() []struct {
+	ObjectDN   string
+	Attributes map[string][]string
+} {
+	var calls []struct {
+		ObjectDN   string
+		Attributes map[string][]string
+	}
+	mock.lockWrite.RLock()
+	calls = mock.calls.Write
+	mock.lockWrite.RUnlock()
+	return calls
+}
+
+// WriteDn calls WriteDnFunc.
+func (mock *ConfigAPI) WriteDn(objectDN string, name string, values []string) error {
+	if mock.WriteDnFunc == nil {
+		panic("ConfigAPI.WriteDnFunc: method is nil but ConfigAPI.WriteDn was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+		Name     string
+		Values   []string
+	}{
+		ObjectDN: objectDN,
+		Name:     name,
+		Values:   values,
+	}
+	mock.lockWriteDn.Lock()
+	mock.calls.WriteDn = append(mock.calls.WriteDn, callInfo)
+	mock.lockWriteDn.Unlock()
+	return mock.WriteDnFunc(objectDN, name, values)
+}
+
+// WriteDnCalls gets all the calls that were made to WriteDn.
+// Check the length with:
+//
+//	len(mockedConfigAPI.WriteDnCalls())
+func (mock *ConfigAPI) WriteDnCalls() []struct {
+	ObjectDN string
+	Name     string
+	Values   []string
+} {
+	var calls []struct {
+		ObjectDN string
+		Name     string
+		Values   []string
+	}
+	mock.lockWriteDn.RLock()
+	calls = mock.calls.WriteDn
+	mock.lockWriteDn.RUnlock()
+	return calls
+}
+
+// WritePolicy calls WritePolicyFunc.
+func (mock *ConfigAPI) WritePolicy(policyDN string, className string, attributeName string, values []string, locked bool) error {
+	if mock.WritePolicyFunc == nil {
+		panic("ConfigAPI.WritePolicyFunc: method is nil but ConfigAPI.WritePolicy was just called")
+	}
+	callInfo := struct {
+		PolicyDN      string
+		ClassName     string
+		AttributeName string
+		Values        []string
+		Locked        bool
+	}{
+		PolicyDN:      policyDN,
+		ClassName:     className,
+		AttributeName: attributeName,
+		Values:        values,
+		Locked:        locked,
+	}
+	mock.lockWritePolicy.Lock()
+	mock.calls.WritePolicy = append(mock.calls.WritePolicy, callInfo)
+	mock.lockWritePolicy.Unlock()
+	return mock.WritePolicyFunc(policyDN, className, attributeName, values, locked)
+}
+
+// WritePolicyCalls gets all the calls that were made to WritePolicy.
+// Check the length with:
+//
+//	len(mockedConfigAPI.WritePolicyCalls())
+func (mock *ConfigAPI) WritePolicyCalls() []struct {
+	PolicyDN      string
+	ClassName     string
+	AttributeName string
+	Values        []string
+	Locked        bool
+} {
+	var calls []struct {
+		PolicyDN      string
+		ClassName     string
+		AttributeName string
+		Values        []string
+		Locked        bool
+	}
+	mock.lockWritePolicy.RLock()
+	calls = mock.calls.WritePolicy
+	mock.lockWritePolicy.RUnlock()
+	return calls
+}
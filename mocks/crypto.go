@@ -0,0 +1,106 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"sync"
+)
+
+// Ensure, that CryptoAPI does implement venafi.CryptoAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.CryptoAPI = &CryptoAPI{}
+
+// CryptoAPI is a mock implementation of venafi.CryptoAPI.
+//
+//	func TestSomethingThatUsesCryptoAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.CryptoAPI
+//		mockedCryptoAPI := &CryptoAPI{
+//			AvailableKeysFunc: func() ([]secret_store.ProtectionKey, error) {
+//				panic("mock out the AvailableKeys method")
+//			},
+//			DefaultKeyFunc: func() (secret_store.ProtectionKey, error) {
+//				panic("mock out the DefaultKey method")
+//			},
+//		}
+//
+//		// use mockedCryptoAPI in code that requires venafi.CryptoAPI
+//		// and then make assertions.
+//
+//	}
+type CryptoAPI struct {
+	// AvailableKeysFunc mocks the AvailableKeys method.
+	AvailableKeysFunc func() ([]secret_store.ProtectionKey, error)
+
+	// DefaultKeyFunc mocks the DefaultKey method.
+	DefaultKeyFunc func() (secret_store.ProtectionKey, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AvailableKeys holds details about calls to the AvailableKeys method.
+		AvailableKeys []struct {
+		}
+		// DefaultKey holds details about calls to the DefaultKey method.
+		DefaultKey []struct {
+		}
+	}
+	lockAvailableKeys sync.RWMutex
+	lockDefaultKey    sync.RWMutex
+}
+
+// AvailableKeys calls AvailableKeysFunc.
+func (mock *CryptoAPI) AvailableKeys() ([]secret_store.ProtectionKey, error) {
+	if mock.AvailableKeysFunc == nil {
+		panic("CryptoAPI.AvailableKeysFunc: method is nil but CryptoAPI.AvailableKeys was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockAvailableKeys.Lock()
+	mock.calls.AvailableKeys = append(mock.calls.AvailableKeys, callInfo)
+	mock.lockAvailableKeys.Unlock()
+	return mock.AvailableKeysFunc()
+}
+
+// AvailableKeysCalls gets all the calls that were made to AvailableKeys.
+// Check the length with:
+//
+//	len(mockedCryptoAPI.AvailableKeysCalls())
+func (mock *CryptoAPI) AvailableKeysCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockAvailableKeys.RLock()
+	calls = mock.calls.AvailableKeys
+	mock.lockAvailableKeys.RUnlock()
+	return calls
+}
+
+// DefaultKey calls DefaultKeyFunc.
+func (mock *CryptoAPI) DefaultKey() (secret_store.ProtectionKey, error) {
+	if mock.DefaultKeyFunc == nil {
+		panic("CryptoAPI.DefaultKeyFunc: method is nil but CryptoAPI.DefaultKey was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockDefaultKey.Lock()
+	mock.calls.DefaultKey = append(mock.calls.DefaultKey, callInfo)
+	mock.lockDefaultKey.Unlock()
+	return mock.DefaultKeyFunc()
+}
+
+// DefaultKeyCalls gets all the calls that were made to DefaultKey.
+// Check the length with:
+//
+//	len(mockedCryptoAPI.DefaultKeyCalls())
+func (mock *CryptoAPI) DefaultKeyCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockDefaultKey.RLock()
+	calls = mock.calls.DefaultKey
+	mock.lockDefaultKey.RUnlock()
+	return calls
+}
@@ -0,0 +1,28 @@
+// Package mocks provides test doubles for the venafi service interfaces,
+// generated with moq. Set the Func fields a test needs and pass the mocks to
+// venafi.NewClientWithServices; calling a method whose Func field is nil
+// panics. Each mock also records its calls, which tests can inspect with the
+// matching Calls method:
+//
+//	ids := &mocks.IdentityAPI{
+//		SelfFunc: func() (*venafi.Identity, error) {
+//			return &venafi.Identity{PrefixedUniversal: "local:{1234}"}, nil
+//		},
+//	}
+//	v, err := venafi.NewClientWithServices(addr, user, pass, nil, venafi.Services{Identity: ids})
+//	...
+//	if len(ids.SelfCalls()) != 1 { ... }
+//
+// Run go generate after changing an interface in interfaces.go.
+package mocks
+
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out ca.go .. CAAPI:CAAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out certificate.go .. CertificateAPI:CertificateAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out config.go .. ConfigAPI:ConfigAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out crypto.go .. CryptoAPI:CryptoAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out identity.go .. IdentityAPI:IdentityAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out permissions.go .. PermissionsAPI:PermissionsAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out policy.go .. PolicyAPI:PolicyAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out schema.go .. SchemaAPI:SchemaAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out secret_store.go .. SecretStoreAPI:SecretStoreAPI
+//go:generate go run github.com/matryer/moq@v0.5.3 -pkg mocks -out x509_store.go .. X509StoreAPI:X509StoreAPI
@@ -0,0 +1,681 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/tradel/venafi-tpp"
+	"sync"
+)
+
+// Ensure, that IdentityAPI does implement venafi.IdentityAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.IdentityAPI = &IdentityAPI{}
+
+// IdentityAPI is a mock implementation of venafi.IdentityAPI.
+//
+//	func TestSomethingThatUsesIdentityAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.IdentityAPI
+//		mockedIdentityAPI := &IdentityAPI{
+//			AddGroupFunc: func(name string, members []venafi.Identity) (*venafi.Identity, error) {
+//				panic("mock out the AddGroup method")
+//			},
+//			AddGroupMembersFunc: func(group *venafi.Identity, members []venafi.Identity) error {
+//				panic("mock out the AddGroupMembers method")
+//			},
+//			BrowseFunc: func(filter string, limit int, identityTypes venafi.IdentityType) ([]venafi.Identity, error) {
+//				panic("mock out the Browse method")
+//			},
+//			DeleteGroupFunc: func(group *venafi.Identity) error {
+//				panic("mock out the DeleteGroup method")
+//			},
+//			GetAssociatedEntriesFunc: func(id *venafi.Identity) ([]venafi.Identity, error) {
+//				panic("mock out the GetAssociatedEntries method")
+//			},
+//			GetMembersFunc: func(group *venafi.Identity, resolveNested bool) ([]venafi.Identity, error) {
+//				panic("mock out the GetMembers method")
+//			},
+//			GetMembershipsFunc: func(id *venafi.Identity) ([]venafi.Identity, error) {
+//				panic("mock out the GetMemberships method")
+//			},
+//			LookupFunc: func(name string) (*venafi.Identity, error) {
+//				panic("mock out the Lookup method")
+//			},
+//			ReadAttributeFunc: func(id *venafi.Identity, attributeName string) ([]string, error) {
+//				panic("mock out the ReadAttribute method")
+//			},
+//			RemoveGroupMembersFunc: func(group *venafi.Identity, members []venafi.Identity) error {
+//				panic("mock out the RemoveGroupMembers method")
+//			},
+//			RenameGroupFunc: func(group *venafi.Identity, newName string) (*venafi.Identity, error) {
+//				panic("mock out the RenameGroup method")
+//			},
+//			SelfFunc: func() (*venafi.Identity, error) {
+//				panic("mock out the Self method")
+//			},
+//			SessionFunc: func() (*venafi.SessionIdentity, error) {
+//				panic("mock out the Session method")
+//			},
+//			ValidateFunc: func(id *venafi.Identity) (*venafi.Identity, error) {
+//				panic("mock out the Validate method")
+//			},
+//		}
+//
+//		// use mockedIdentityAPI in code that requires venafi.IdentityAPI
+//		// and then make assertions.
+//
+//	}
+type IdentityAPI struct {
+	// AddGroupFunc mocks the AddGroup method.
+	AddGroupFunc func(name string, members []venafi.Identity) (*venafi.Identity, error)
+
+	// AddGroupMembersFunc mocks the AddGroupMembers method.
+	AddGroupMembersFunc func(group *venafi.Identity, members []venafi.Identity) error
+
+	// BrowseFunc mocks the Browse method.
+	BrowseFunc func(filter string, limit int, identityTypes venafi.IdentityType) ([]venafi.Identity, error)
+
+	// DeleteGroupFunc mocks the DeleteGroup method.
+	DeleteGroupFunc func(group *venafi.Identity) error
+
+	// GetAssociatedEntriesFunc mocks the GetAssociatedEntries method.
+	GetAssociatedEntriesFunc func(id *venafi.Identity) ([]venafi.Identity, error)
+
+	// GetMembersFunc mocks the GetMembers method.
+	GetMembersFunc func(group *venafi.Identity, resolveNested bool) ([]venafi.Identity, error)
+
+	// GetMembershipsFunc mocks the GetMemberships method.
+	GetMembershipsFunc func(id *venafi.Identity) ([]venafi.Identity, error)
+
+	// LookupFunc mocks the Lookup method.
+	LookupFunc func(name string) (*venafi.Identity, error)
+
+	// ReadAttributeFunc mocks the ReadAttribute method.
+	ReadAttributeFunc func(id *venafi.Identity, attributeName string) ([]string, error)
+
+	// RemoveGroupMembersFunc mocks the RemoveGroupMembers method.
+	RemoveGroupMembersFunc func(group *venafi.Identity, members []venafi.Identity) error
+
+	// RenameGroupFunc mocks the RenameGroup method.
+	RenameGroupFunc func(group *venafi.Identity, newName string) (*venafi.Identity, error)
+
+	// SelfFunc mocks the Self method.
+	SelfFunc func() (*venafi.Identity, error)
+
+	// SessionFunc mocks the Session method.
+	SessionFunc func() (*venafi.SessionIdentity, error)
+
+	// ValidateFunc mocks the Validate method.
+	ValidateFunc func(id *venafi.Identity) (*venafi.Identity, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// AddGroup holds details about calls to the AddGroup method.
+		AddGroup []struct {
+			// Name is the name argument value.
+			Name string
+			// Members is the members argument value.
+			Members []venafi.Identity
+		}
+		// AddGroupMembers holds details about calls to the AddGroupMembers method.
+		AddGroupMembers []struct {
+			// Group is the group argument value.
+			Group *venafi.Identity
+			// Members is the members argument value.
+			Members []venafi.Identity
+		}
+		// Browse holds details about calls to the Browse method.
+		Browse []struct {
+			// Filter is the filter argument value.
+			Filter string
+			// Limit is the limit argument value.
+			Limit int
+			// IdentityTypes is the identityTypes argument value.
+			IdentityTypes venafi.IdentityType
+		}
+		// DeleteGroup holds details about calls to the DeleteGroup method.
+		DeleteGroup []struct {
+			// Group is the group argument value.
+			Group *venafi.Identity
+		}
+		// GetAssociatedEntries holds details about calls to the GetAssociatedEntries method.
+		GetAssociatedEntries []struct {
+			// ID is the id argument value.
+			ID *venafi.Identity
+		}
+		// GetMembers holds details about calls to the GetMembers method.
+		GetMembers []struct {
+			// Group is the group argument value.
+			Group *venafi.Identity
+			// ResolveNested is the resolveNested argument value.
+			ResolveNested bool
+		}
+		// GetMemberships holds details about calls to the GetMemberships method.
+		GetMemberships []struct {
+			// ID is the id argument value.
+			ID *venafi.Identity
+		}
+		// Lookup holds details about calls to the Lookup method.
+		Lookup []struct {
+			// Name is the name argument value.
+			Name string
+		}
+		// ReadAttribute holds details about calls to the ReadAttribute method.
+		ReadAttribute []struct {
+			// ID is the id argument value.
+			ID *venafi.Identity
+			// AttributeName is the attributeName argument value.
+			AttributeName string
+		}
+		// RemoveGroupMembers holds details about calls to the RemoveGroupMembers method.
+		RemoveGroupMembers []struct {
+			// Group is the group argument value.
+			Group *venafi.Identity
+			// Members is the members argument value.
+			Members []venafi.Identity
+		}
+		// RenameGroup holds details about calls to the RenameGroup method.
+		RenameGroup []struct {
+			// Group is the group argument value.
+			Group *venafi.Identity
+			// NewName is the newName argument value.
+			NewName string
+		}
+		// Self holds details about calls to the Self method.
+		Self []struct {
+		}
+		// Session holds details about calls to the Session method.
+		Session []struct {
+		}
+		// Validate holds details about calls to the Validate method.
+		Validate []struct {
+			// ID is the id argument value.
+			ID *venafi.Identity
+		}
+	}
+	lockAddGroup             sync.RWMutex
+	lockAddGroupMembers      sync.RWMutex
+	lockBrowse               sync.RWMutex
+	lockDeleteGroup          sync.RWMutex
+	lockGetAssociatedEntries sync.RWMutex
+	lockGetMembers           sync.RWMutex
+	lockGetMemberships       sync.RWMutex
+	lockLookup               sync.RWMutex
+	lockReadAttribute        sync.RWMutex
+	lockRemoveGroupMembers   sync.RWMutex
+	lockRenameGroup          sync.RWMutex
+	lockSelf                 sync.RWMutex
+	lockSession              sync.RWMutex
+	lockValidate             sync.RWMutex
+}
+
+// AddGroup calls AddGroupFunc.
+func (mock *IdentityAPI) AddGroup(name string, members []venafi.Identity) (*venafi.Identity, error) {
+	if mock.AddGroupFunc == nil {
+		panic("IdentityAPI.AddGroupFunc: method is nil but IdentityAPI.AddGroup was just called")
+	}
+	callInfo := struct {
+		Name    string
+		Members []venafi.Identity
+	}{
+		Name:    name,
+		Members: members,
+	}
+	mock.lockAddGroup.Lock()
+	mock.calls.AddGroup = append(mock.calls.AddGroup, callInfo)
+	mock.lockAddGroup.Unlock()
+	return mock.AddGroupFunc(name, members)
+}
+
+// AddGroupCalls gets all the calls that were made to AddGroup.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.AddGroupCalls())
+func (mock *IdentityAPI) AddGroupCalls() []struct {
+	Name    string
+	Members []venafi.Identity
+} {
+	var calls []struct {
+		Name    string
+		Members []venafi.Identity
+	}
+	mock.lockAddGroup.RLock()
+	calls = mock.calls.AddGroup
+	mock.lockAddGroup.RUnlock()
+	return calls
+}
+
+// AddGroupMembers calls AddGroupMembersFunc.
+func (mock *IdentityAPI) AddGroupMembers(group *venafi.Identity, members []venafi.Identity) error {
+	if mock.AddGroupMembersFunc == nil {
+		panic("IdentityAPI.AddGroupMembersFunc: method is nil but IdentityAPI.AddGroupMembers was just called")
+	}
+	callInfo := struct {
+		Group   *venafi.Identity
+		Members []venafi.Identity
+	}{
+		Group:   group,
+		Members: members,
+	}
+	mock.lockAddGroupMembers.Lock()
+	mock.calls.AddGroupMembers = append(mock.calls.AddGroupMembers, callInfo)
+	mock.lockAddGroupMembers.Unlock()
+	return mock.AddGroupMembersFunc(group, members)
+}
+
+// AddGroupMembersCalls gets all the calls that were made to AddGroupMembers.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.AddGroupMembersCalls())
+func (mock *IdentityAPI) AddGroupMembersCalls() []struct {
+	Group   *venafi.Identity
+	Members []venafi.Identity
+} {
+	var calls []struct {
+		Group   *venafi.Identity
+		Members []venafi.Identity
+	}
+	mock.lockAddGroupMembers.RLock()
+	calls = mock.calls.AddGroupMembers
+	mock.lockAddGroupMembers.RUnlock()
+	return calls
+}
+
+// Browse calls BrowseFunc.
+func (mock *IdentityAPI) Browse(filter string, limit int, identityTypes venafi.IdentityType) ([]venafi.Identity, error) {
+	if mock.BrowseFunc == nil {
+		panic("IdentityAPI.BrowseFunc: method is nil but IdentityAPI.Browse was just called")
+	}
+	callInfo := struct {
+		Filter        string
+		Limit         int
+		IdentityTypes venafi.IdentityType
+	}{
+		Filter:        filter,
+		Limit:         limit,
+		IdentityTypes: identityTypes,
+	}
+	mock.lockBrowse.Lock()
+	mock.calls.Browse = append(mock.calls.Browse, callInfo)
+	mock.lockBrowse.Unlock()
+	return mock.BrowseFunc(filter, limit, identityTypes)
+}
+
+// BrowseCalls gets all the calls that were made to Browse.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.BrowseCalls())
+func (mock *IdentityAPI) BrowseCalls() []struct {
+	Filter        string
+	Limit         int
+	IdentityTypes venafi.IdentityType
+} {
+	var calls []struct {
+		Filter        string
+		Limit         int
+		IdentityTypes venafi.IdentityType
+	}
+	mock.lockBrowse.RLock()
+	calls = mock.calls.Browse
+	mock.lockBrowse.RUnlock()
+	return calls
+}
+
+// DeleteGroup calls DeleteGroupFunc.
+func (mock *IdentityAPI) DeleteGroup(group *venafi.Identity) error {
+	if mock.DeleteGroupFunc == nil {
+		panic("IdentityAPI.DeleteGroupFunc: method is nil but IdentityAPI.DeleteGroup was just called")
+	}
+	callInfo := struct {
+		Group *venafi.Identity
+	}{
+		Group: group,
+	}
+	mock.lockDeleteGroup.Lock()
+	mock.calls.DeleteGroup = append(mock.calls.DeleteGroup, callInfo)
+	mock.lockDeleteGroup.Unlock()
+	return mock.DeleteGroupFunc(group)
+}
+
+// DeleteGroupCalls gets all the calls that were made to DeleteGroup.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.DeleteGroupCalls())
+func (mock *IdentityAPI) DeleteGroupCalls() []struct {
+	Group *venafi.Identity
+} {
+	var calls []struct {
+		Group *venafi.Identity
+	}
+	mock.lockDeleteGroup.RLock()
+	calls = mock.calls.DeleteGroup
+	mock.lockDeleteGroup.RUnlock()
+	return calls
+}
+
+// GetAssociatedEntries calls GetAssociatedEntriesFunc.
+func (mock *IdentityAPI) GetAssociatedEntries(id *venafi.Identity) ([]venafi.Identity, error) {
+	if mock.GetAssociatedEntriesFunc == nil {
+		panic("IdentityAPI.GetAssociatedEntriesFunc: method is nil but IdentityAPI.GetAssociatedEntries was just called")
+	}
+	callInfo := struct {
+		ID *venafi.Identity
+	}{
+		ID: id,
+	}
+	mock.lockGetAssociatedEntries.Lock()
+	mock.calls.GetAssociatedEntries = append(mock.calls.GetAssociatedEntries, callInfo)
+	mock.lockGetAssociatedEntries.Unlock()
+	return mock.GetAssociatedEntriesFunc(id)
+}
+
+// GetAssociatedEntriesCalls gets all the calls that were made to GetAssociatedEntries.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.GetAssociatedEntriesCalls())
+func (mock *IdentityAPI) GetAssociatedEntriesCalls() []struct {
+	ID *venafi.Identity
+} {
+	var calls []struct {
+		ID *venafi.Identity
+	}
+	mock.lockGetAssociatedEntries.RLock()
+	calls = mock.calls.GetAssociatedEntries
+	mock.lockGetAssociatedEntries.RUnlock()
+	return calls
+}
+
+// GetMembers calls GetMembersFunc.
+func (mock *IdentityAPI) GetMembers(group *venafi.Identity, resolveNested bool) ([]venafi.Identity, error) {
+	if mock.GetMembersFunc == nil {
+		panic("IdentityAPI.GetMembersFunc: method is nil but IdentityAPI.GetMembers was just called")
+	}
+	callInfo := struct {
+		Group         *venafi.Identity
+		ResolveNested bool
+	}{
+		Group:         group,
+		ResolveNested: resolveNested,
+	}
+	mock.lockGetMembers.Lock()
+	mock.calls.GetMembers = append(mock.calls.GetMembers, callInfo)
+	mock.lockGetMembers.Unlock()
+	return mock.GetMembersFunc(group, resolveNested)
+}
+
+// GetMembersCalls gets all the calls that were made to GetMembers.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.GetMembersCalls())
+func (mock *IdentityAPI) GetMembersCalls() []struct {
+	Group         *venafi.Identity
+	ResolveNested bool
+} {
+	var calls []struct {
+		Group         *venafi.Identity
+		ResolveNested bool
+	}
+	mock.lockGetMembers.RLock()
+	calls = mock.calls.GetMembers
+	mock.lockGetMembers.RUnlock()
+	return calls
+}
+
+// GetMemberships calls GetMembershipsFunc.
+func (mock *IdentityAPI) GetMemberships(id *venafi.Identity) ([]venafi.Identity, error) {
+	if mock.GetMembershipsFunc == nil {
+		panic("IdentityAPI.GetMembershipsFunc: method is nil but IdentityAPI.GetMemberships was just called")
+	}
+	callInfo := struct {
+		ID *venafi.Identity
+	}{
+		ID: id,
+	}
+	mock.lockGetMemberships.Lock()
+	mock.calls.GetMemberships = append(mock.calls.GetMemberships, callInfo)
+	mock.lockGetMemberships.Unlock()
+	return mock.GetMembershipsFunc(id)
+}
+
+// GetMembershipsCalls gets all the calls that were made to GetMemberships.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.GetMembershipsCalls())
+func (mock *IdentityAPI) GetMembershipsCalls() []struct {
+	ID *venafi.Identity
+} {
+	var calls []struct {
+		ID *venafi.Identity
+	}
+	mock.lockGetMemberships.RLock()
+	calls = mock.calls.GetMemberships
+	mock.lockGetMemberships.RUnlock()
+	return calls
+}
+
+// Lookup calls LookupFunc.
+func (mock *IdentityAPI) Lookup(name string) (*venafi.Identity, error) {
+	if mock.LookupFunc == nil {
+		panic("IdentityAPI.LookupFunc: method is nil but IdentityAPI.Lookup was just called")
+	}
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockLookup.Lock()
+	mock.calls.Lookup = append(mock.calls.Lookup, callInfo)
+	mock.lockLookup.Unlock()
+	return mock.LookupFunc(name)
+}
+
+// LookupCalls gets all the calls that were made to Lookup.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.LookupCalls())
+func (mock *IdentityAPI) LookupCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
+	}
+	mock.lockLookup.RLock()
+	calls = mock.calls.Lookup
+	mock.lockLookup.RUnlock()
+	return calls
+}
+
+// ReadAttribute calls ReadAttributeFunc.
+func (mock *IdentityAPI) ReadAttribute(id *venafi.Identity, attributeName string) ([]string, error) {
+	if mock.ReadAttributeFunc == nil {
+		panic("IdentityAPI.ReadAttributeFunc: method is nil but IdentityAPI.ReadAttribute was just called")
+	}
+	callInfo := struct {
+		ID            *venafi.Identity
+		AttributeName string
+	}{
+		ID:            id,
+		AttributeName: attributeName,
+	}
+	mock.lockReadAttribute.Lock()
+	mock.calls.ReadAttribute = append(mock.calls.ReadAttribute, callInfo)
+	mock.lockReadAttribute.Unlock()
+	return mock.ReadAttributeFunc(id, attributeName)
+}
+
+// ReadAttributeCalls gets all the calls that were made to ReadAttribute.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.ReadAttributeCalls())
+func (mock *IdentityAPI) ReadAttributeCalls() []struct {
+	ID            *venafi.Identity
+	AttributeName string
+} {
+	var calls []struct {
+		ID            *venafi.Identity
+		AttributeName string
+	}
+	mock.lockReadAttribute.RLock()
+	calls = mock.calls.ReadAttribute
+	mock.lockReadAttribute.RUnlock()
+	return calls
+}
+
+// RemoveGroupMembers calls RemoveGroupMembersFunc.
+func (mock *IdentityAPI) RemoveGroupMembers(group *venafi.Identity, members []venafi.Identity) error {
+	if mock.RemoveGroupMembersFunc == nil {
+		panic("IdentityAPI.RemoveGroupMembersFunc: method is nil but IdentityAPI.RemoveGroupMembers was just called")
+	}
+	callInfo := struct {
+		Group   *venafi.Identity
+		Members []venafi.Identity
+	}{
+		Group:   group,
+		Members: members,
+	}
+	mock.lockRemoveGroupMembers.Lock()
+	mock.calls.RemoveGroupMembers = append(mock.calls.RemoveGroupMembers, callInfo)
+	mock.lockRemoveGroupMembers.Unlock()
+	return mock.RemoveGroupMembersFunc(group, members)
+}
+
+// RemoveGroupMembersCalls gets all the calls that were made to RemoveGroupMembers.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.RemoveGroupMembersCalls())
+func (mock *IdentityAPI) RemoveGroupMembersCalls() []struct {
+	Group   *venafi.Identity
+	Members []venafi.Identity
+} {
+	var calls []struct {
+		Group   *venafi.Identity
+		Members []venafi.Identity
+	}
+	mock.lockRemoveGroupMembers.RLock()
+	calls = mock.calls.RemoveGroupMembers
+	mock.lockRemoveGroupMembers.RUnlock()
+	return calls
+}
+
+// RenameGroup calls RenameGroupFunc.
+func (mock *IdentityAPI) RenameGroup(group *venafi.Identity, newName string) (*venafi.Identity, error) {
+	if mock.RenameGroupFunc == nil {
+		panic("IdentityAPI.RenameGroupFunc: method is nil but IdentityAPI.RenameGroup was just called")
+	}
+	callInfo := struct {
+		Group   *venafi.Identity
+		NewName string
+	}{
+		Group:   group,
+		NewName: newName,
+	}
+	mock.lockRenameGroup.Lock()
+	mock.calls.RenameGroup = append(mock.calls.RenameGroup, callInfo)
+	mock.lockRenameGroup.Unlock()
+	return mock.RenameGroupFunc(group, newName)
+}
+
+// RenameGroupCalls gets all the calls that were made to RenameGroup.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.RenameGroupCalls())
+func (mock *IdentityAPI) RenameGroupCalls() []struct {
+	Group   *venafi.Identity
+	NewName string
+} {
+	var calls []struct {
+		Group   *venafi.Identity
+		NewName string
+	}
+	mock.lockRenameGroup.RLock()
+	calls = mock.calls.RenameGroup
+	mock.lockRenameGroup.RUnlock()
+	return calls
+}
+
+// Self calls SelfFunc.
+func (mock *IdentityAPI) Self() (*venafi.Identity, error) {
+	if mock.SelfFunc == nil {
+		panic("IdentityAPI.SelfFunc: method is nil but IdentityAPI.Self was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockSelf.Lock()
+	mock.calls.Self = append(mock.calls.Self, callInfo)
+	mock.lockSelf.Unlock()
+	return mock.SelfFunc()
+}
+
+// SelfCalls gets all the calls that were made to Self.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.SelfCalls())
+func (mock *IdentityAPI) SelfCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockSelf.RLock()
+	calls = mock.calls.Self
+	mock.lockSelf.RUnlock()
+	return calls
+}
+
+// Session calls SessionFunc.
+func (mock *IdentityAPI) Session() (*venafi.SessionIdentity, error) {
+	if mock.SessionFunc == nil {
+		panic("IdentityAPI.SessionFunc: method is nil but IdentityAPI.Session was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockSession.Lock()
+	mock.calls.Session = append(mock.calls.Session, callInfo)
+	mock.lockSession.Unlock()
+	return mock.SessionFunc()
+}
+
+// SessionCalls gets all the calls that were made to Session.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.SessionCalls())
+func (mock *IdentityAPI) SessionCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockSession.RLock()
+	calls = mock.calls.Session
+	mock.lockSession.RUnlock()
+	return calls
+}
+
+// Validate calls ValidateFunc.
+func (mock *IdentityAPI) Validate(id *venafi.Identity) (*venafi.Identity, error) {
+	if mock.ValidateFunc == nil {
+		panic("IdentityAPI.ValidateFunc: method is nil but IdentityAPI.Validate was just called")
+	}
+	callInfo := struct {
+		ID *venafi.Identity
+	}{
+		ID: id,
+	}
+	mock.lockValidate.Lock()
+	mock.calls.Validate = append(mock.calls.Validate, callInfo)
+	mock.lockValidate.Unlock()
+	return mock.ValidateFunc(id)
+}
+
+// ValidateCalls gets all the calls that were made to Validate.
+// Check the length with:
+//
+//	len(mockedIdentityAPI.ValidateCalls())
+func (mock *IdentityAPI) ValidateCalls() []struct {
+	ID *venafi.Identity
+} {
+	var calls []struct {
+		ID *venafi.Identity
+	}
+	mock.lockValidate.RLock()
+	calls = mock.calls.Validate
+	mock.lockValidate.RUnlock()
+	return calls
+}
@@ -0,0 +1,337 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/tradel/venafi-tpp"
+	"sync"
+)
+
+// Ensure, that PermissionsAPI does implement venafi.PermissionsAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.PermissionsAPI = &PermissionsAPI{}
+
+// PermissionsAPI is a mock implementation of venafi.PermissionsAPI.
+//
+//	func TestSomethingThatUsesPermissionsAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.PermissionsAPI
+//		mockedPermissionsAPI := &PermissionsAPI{
+//			EffectiveFunc: func(objectDN string, principal *venafi.Identity) (*venafi.Permissions, error) {
+//				panic("mock out the Effective method")
+//			},
+//			GetFunc: func(objectDN string, principal *venafi.Identity) (*venafi.ObjectPermissions, error) {
+//				panic("mock out the Get method")
+//			},
+//			GrantFunc: func(objectDN string, principal *venafi.Identity, role venafi.Permissions) error {
+//				panic("mock out the Grant method")
+//			},
+//			ListFunc: func(objectDN string) ([]venafi.Identity, error) {
+//				panic("mock out the List method")
+//			},
+//			RemoveFunc: func(objectDN string, principal *venafi.Identity) error {
+//				panic("mock out the Remove method")
+//			},
+//			SetFunc: func(objectDN string, principal *venafi.Identity, perms venafi.Permissions) error {
+//				panic("mock out the Set method")
+//			},
+//		}
+//
+//		// use mockedPermissionsAPI in code that requires venafi.PermissionsAPI
+//		// and then make assertions.
+//
+//	}
+type PermissionsAPI struct {
+	// EffectiveFunc mocks the Effective method.
+	EffectiveFunc func(objectDN string, principal *venafi.Identity) (*venafi.Permissions, error)
+
+	// GetFunc mocks the Get method.
+	GetFunc func(objectDN string, principal *venafi.Identity) (*venafi.ObjectPermissions, error)
+
+	// GrantFunc mocks the Grant method.
+	GrantFunc func(objectDN string, principal *venafi.Identity, role venafi.Permissions) error
+
+	// ListFunc mocks the List method.
+	ListFunc func(objectDN string) ([]venafi.Identity, error)
+
+	// RemoveFunc mocks the Remove method.
+	RemoveFunc func(objectDN string, principal *venafi.Identity) error
+
+	// SetFunc mocks the Set method.
+	SetFunc func(objectDN string, principal *venafi.Identity, perms venafi.Permissions) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Effective holds details about calls to the Effective method.
+		Effective []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Principal is the principal argument value.
+			Principal *venafi.Identity
+		}
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Principal is the principal argument value.
+			Principal *venafi.Identity
+		}
+		// Grant holds details about calls to the Grant method.
+		Grant []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Principal is the principal argument value.
+			Principal *venafi.Identity
+			// Role is the role argument value.
+			Role venafi.Permissions
+		}
+		// List holds details about calls to the List method.
+		List []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+		}
+		// Remove holds details about calls to the Remove method.
+		Remove []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Principal is the principal argument value.
+			Principal *venafi.Identity
+		}
+		// Set holds details about calls to the Set method.
+		Set []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Principal is the principal argument value.
+			Principal *venafi.Identity
+			// Perms is the perms argument value.
+			Perms venafi.Permissions
+		}
+	}
+	lockEffective sync.RWMutex
+	lockGet       sync.RWMutex
+	lockGrant     sync.RWMutex
+	lockList      sync.RWMutex
+	lockRemove    sync.RWMutex
+	lockSet       sync.RWMutex
+}
+
+// Effective calls EffectiveFunc.
+func (mock *PermissionsAPI) Effective(objectDN string, principal *venafi.Identity) (*venafi.Permissions, error) {
+	if mock.EffectiveFunc == nil {
+		panic("PermissionsAPI.EffectiveFunc: method is nil but PermissionsAPI.Effective was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+	}{
+		ObjectDN:  objectDN,
+		Principal: principal,
+	}
+	mock.lockEffective.Lock()
+	mock.calls.Effective = append(mock.calls.Effective, callInfo)
+	mock.lockEffective.Unlock()
+	return mock.EffectiveFunc(objectDN, principal)
+}
+
+// EffectiveCalls gets all the calls that were made to Effective.
+// Check the length with:
+//
+//	len(mockedPermissionsAPI.EffectiveCalls())
+func (mock *PermissionsAPI) EffectiveCalls() []struct {
+	ObjectDN  string
+	Principal *venafi.Identity
+} {
+	var calls []struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+	}
+	mock.lockEffective.RLock()
+	calls = mock.calls.Effective
+	mock.lockEffective.RUnlock()
+	return calls
+}
+
+// Get calls GetFunc.
+func (mock *PermissionsAPI) Get(objectDN string, principal *venafi.Identity) (*venafi.ObjectPermissions, error) {
+	if mock.GetFunc == nil {
+		panic("PermissionsAPI.GetFunc: method is nil but PermissionsAPI.Get was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+	}{
+		ObjectDN:  objectDN,
+		Principal: principal,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(objectDN, principal)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedPermissionsAPI.GetCalls())
+func (mock *PermissionsAPI) GetCalls() []struct {
+	ObjectDN  string
+	Principal *venafi.Identity
+} {
+	var calls []struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
+// Grant calls GrantFunc.
+func (mock *PermissionsAPI) Grant(objectDN string, principal *venafi.Identity, role venafi.Permissions) error {
+	if mock.GrantFunc == nil {
+		panic("PermissionsAPI.GrantFunc: method is nil but PermissionsAPI.Grant was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+		Role      venafi.Permissions
+	}{
+		ObjectDN:  objectDN,
+		Principal: principal,
+		Role:      role,
+	}
+	mock.lockGrant.Lock()
+	mock.calls.Grant = append(mock.calls.Grant, callInfo)
+	mock.lockGrant.Unlock()
+	return mock.GrantFunc(objectDN, principal, role)
+}
+
+// GrantCalls gets all the calls that were made to Grant.
+// Check the length with:
+//
+//	len(mockedPermissionsAPI.GrantCalls())
+func (mock *PermissionsAPI) GrantCalls() []struct {
+	ObjectDN  string
+	Principal *venafi.Identity
+	Role      venafi.Permissions
+} {
+	var calls []struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+		Role      venafi.Permissions
+	}
+	mock.lockGrant.RLock()
+	calls = mock.calls.Grant
+	mock.lockGrant.RUnlock()
+	return calls
+}
+
+// List calls ListFunc.
+func (mock *PermissionsAPI) List(objectDN string) ([]venafi.Identity, error) {
+	if mock.ListFunc == nil {
+		panic("PermissionsAPI.ListFunc: method is nil but PermissionsAPI.List was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+	}{
+		ObjectDN: objectDN,
+	}
+	mock.lockList.Lock()
+	mock.calls.List = append(mock.calls.List, callInfo)
+	mock.lockList.Unlock()
+	return mock.ListFunc(objectDN)
+}
+
+// ListCalls gets all the calls that were made to List.
+// Check the length with:
+//
+//	len(mockedPermissionsAPI.ListCalls())
+func (mock *PermissionsAPI) ListCalls() []struct {
+	ObjectDN string
+} {
+	var calls []struct {
+		ObjectDN string
+	}
+	mock.lockList.RLock()
+	calls = mock.calls.List
+	mock.lockList.RUnlock()
+	return calls
+}
+
+// Remove calls RemoveFunc.
+func (mock *PermissionsAPI) Remove(objectDN string, principal *venafi.Identity) error {
+	if mock.RemoveFunc == nil {
+		panic("PermissionsAPI.RemoveFunc: method is nil but PermissionsAPI.Remove was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+	}{
+		ObjectDN:  objectDN,
+		Principal: principal,
+	}
+	mock.lockRemove.Lock()
+	mock.calls.Remove = append(mock.calls.Remove, callInfo)
+	mock.lockRemove.Unlock()
+	return mock.RemoveFunc(objectDN, principal)
+}
+
+// RemoveCalls gets all the calls that were made to Remove.
+// Check the length with:
+//
+//	len(mockedPermissionsAPI.RemoveCalls())
+func (mock *PermissionsAPI) RemoveCalls() []struct {
+	ObjectDN  string
+	Principal *venafi.Identity
+} {
+	var calls []struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+	}
+	mock.lockRemove.RLock()
+	calls = mock.calls.Remove
+	mock.lockRemove.RUnlock()
+	return calls
+}
+
+// Set calls SetFunc.
+func (mock *PermissionsAPI) Set(objectDN string, principal *venafi.Identity, perms venafi.Permissions) error {
+	if mock.SetFunc == nil {
+		panic("PermissionsAPI.SetFunc: method is nil but PermissionsAPI.Set was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+		Perms     venafi.Permissions
+	}{
+		ObjectDN:  objectDN,
+		Principal: principal,
+		Perms:     perms,
+	}
+	mock.lockSet.Lock()
+	mock.calls.Set = append(mock.calls.Set, callInfo)
+	mock.lockSet.Unlock()
+	return mock.SetFunc(objectDN, principal, perms)
+}
+
+// SetCalls gets all the calls that were made to Set.
+// Check the length with:
+//
+//	len(mockedPermissionsAPI.SetCalls())
+func (mock *PermissionsAPI) SetCalls() []struct {
+	ObjectDN  string
+	Principal *venafi.Identity
+	Perms     venafi.Permissions
+} {
+	var calls []struct {
+		ObjectDN  string
+		Principal *venafi.Identity
+		Perms     venafi.Permissions
+	}
+	mock.lockSet.RLock()
+	calls = mock.calls.Set
+	mock.lockSet.RUnlock()
+	return calls
+}
@@ -0,0 +1,169 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/tradel/venafi-tpp"
+	"sync"
+)
+
+// Ensure, that PolicyAPI does implement venafi.PolicyAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.PolicyAPI = &PolicyAPI{}
+
+// PolicyAPI is a mock implementation of venafi.PolicyAPI.
+//
+//	func TestSomethingThatUsesPolicyAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.PolicyAPI
+//		mockedPolicyAPI := &PolicyAPI{
+//			CreateFunc: func(objectDN string) (*venafi.ConfigObject, error) {
+//				panic("mock out the Create method")
+//			},
+//			DeleteFunc: func(objectDN string, recursive bool) error {
+//				panic("mock out the Delete method")
+//			},
+//			ExistsFunc: func(objectDN string) bool {
+//				panic("mock out the Exists method")
+//			},
+//		}
+//
+//		// use mockedPolicyAPI in code that requires venafi.PolicyAPI
+//		// and then make assertions.
+//
+//	}
+type PolicyAPI struct {
+	// CreateFunc mocks the Create method.
+	CreateFunc func(objectDN string) (*venafi.ConfigObject, error)
+
+	// DeleteFunc mocks the Delete method.
+	DeleteFunc func(objectDN string, recursive bool) error
+
+	// ExistsFunc mocks the Exists method.
+	ExistsFunc func(objectDN string) bool
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Create holds details about calls to the Create method.
+		Create []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+		}
+		// Delete holds details about calls to the Delete method.
+		Delete []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// Recursive is the recursive argument value.
+			Recursive bool
+		}
+		// Exists holds details about calls to the Exists method.
+		Exists []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+		}
+	}
+	lockCreate sync.RWMutex
+	lockDelete sync.RWMutex
+	lockExists sync.RWMutex
+}
+
+// Create calls CreateFunc.
+func (mock *PolicyAPI) Create(objectDN string) (*venafi.ConfigObject, error) {
+	if mock.CreateFunc == nil {
+		panic("PolicyAPI.CreateFunc: method is nil but PolicyAPI.Create was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+	}{
+		ObjectDN: objectDN,
+	}
+	mock.lockCreate.Lock()
+	mock.calls.Create = append(mock.calls.Create, callInfo)
+	mock.lockCreate.Unlock()
+	return mock.CreateFunc(objectDN)
+}
+
+// CreateCalls gets all the calls that were made to Create.
+// Check the length with:
+//
+//	len(mockedPolicyAPI.CreateCalls())
+func (mock *PolicyAPI) CreateCalls() []struct {
+	ObjectDN string
+} {
+	var calls []struct {
+		ObjectDN string
+	}
+	mock.lockCreate.RLock()
+	calls = mock.calls.Create
+	mock.lockCreate.RUnlock()
+	return calls
+}
+
+// Delete calls DeleteFunc.
+func (mock *PolicyAPI) Delete(objectDN string, recursive bool) error {
+	if mock.DeleteFunc == nil {
+		panic("PolicyAPI.DeleteFunc: method is nil but PolicyAPI.Delete was just called")
+	}
+	callInfo := struct {
+		ObjectDN  string
+		Recursive bool
+	}{
+		ObjectDN:  objectDN,
+		Recursive: recursive,
+	}
+	mock.lockDelete.Lock()
+	mock.calls.Delete = append(mock.calls.Delete, callInfo)
+	mock.lockDelete.Unlock()
+	return mock.DeleteFunc(objectDN, recursive)
+}
+
+// DeleteCalls gets all the calls that were made to Delete.
+// Check the length with:
+//
+//	len(mockedPolicyAPI.DeleteCalls())
+func (mock *PolicyAPI) DeleteCalls() []struct {
+	ObjectDN  string
+	Recursive bool
+} {
+	var calls []struct {
+		ObjectDN  string
+		Recursive bool
+	}
+	mock.lockDelete.RLock()
+	calls = mock.calls.Delete
+	mock.lockDelete.RUnlock()
+	return calls
+}
+
+// Exists calls ExistsFunc.
+func (mock *PolicyAPI) Exists(objectDN string) bool {
+	if mock.ExistsFunc == nil {
+		panic("PolicyAPI.ExistsFunc: method is nil but PolicyAPI.Exists was just called")
+	}
+	callInfo := struct {
+		ObjectDN string
+	}{
+		ObjectDN: objectDN,
+	}
+	mock.lockExists.Lock()
+	mock.calls.Exists = append(mock.calls.Exists, callInfo)
+	mock.lockExists.Unlock()
+	return mock.ExistsFunc(objectDN)
+}
+
+// ExistsCalls gets all the calls that were made to Exists.
+// Check the length with:
+//
+//	len(mockedPolicyAPI.ExistsCalls())
+func (mock *PolicyAPI) ExistsCalls() []struct {
+	ObjectDN string
+} {
+	var calls []struct {
+		ObjectDN string
+	}
+	mock.lockExists.RLock()
+	calls = mock.calls.Exists
+	mock.lockExists.RUnlock()
+	return calls
+}
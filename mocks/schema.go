@@ -0,0 +1,262 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/tradel/venafi-tpp"
+	"sync"
+)
+
+// Ensure, that SchemaAPI does implement venafi.SchemaAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.SchemaAPI = &SchemaAPI{}
+
+// SchemaAPI is a mock implementation of venafi.SchemaAPI.
+//
+//	func TestSomethingThatUsesSchemaAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.SchemaAPI
+//		mockedSchemaAPI := &SchemaAPI{
+//			AttributesFunc: func() ([]venafi.AttributeDefinition, error) {
+//				panic("mock out the Attributes method")
+//			},
+//			ClassFunc: func(className string) (*venafi.ClassDefinition, error) {
+//				panic("mock out the Class method")
+//			},
+//			ContainmentFunc: func(className string) ([]string, error) {
+//				panic("mock out the Containment method")
+//			},
+//			HighestRevisionFunc: func(objectDN string, classNames ...string) (int64, error) {
+//				panic("mock out the HighestRevision method")
+//			},
+//			ValidateCreateFunc: func(objectDN string, className string, attributes map[string]string) error {
+//				panic("mock out the ValidateCreate method")
+//			},
+//		}
+//
+//		// use mockedSchemaAPI in code that requires venafi.SchemaAPI
+//		// and then make assertions.
+//
+//	}
+type SchemaAPI struct {
+	// AttributesFunc mocks the Attributes method.
+	AttributesFunc func() ([]venafi.AttributeDefinition, error)
+
+	// ClassFunc mocks the Class method.
+	ClassFunc func(className string) (*venafi.ClassDefinition, error)
+
+	// ContainmentFunc mocks the Containment method.
+	ContainmentFunc func(className string) ([]string, error)
+
+	// HighestRevisionFunc mocks the HighestRevision method.
+	HighestRevisionFunc func(objectDN string, classNames ...string) (int64, error)
+
+	// ValidateCreateFunc mocks the ValidateCreate method.
+	ValidateCreateFunc func(objectDN string, className string, attributes map[string]string) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Attributes holds details about calls to the Attributes method.
+		Attributes []struct {
+		}
+		// Class holds details about calls to the Class method.
+		Class []struct {
+			// ClassName is the className argument value.
+			ClassName string
+		}
+		// Containment holds details about calls to the Containment method.
+		Containment []struct {
+			// ClassName is the className argument value.
+			ClassName string
+		}
+		// HighestRevision holds details about calls to the HighestRevision method.
+		HighestRevision []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// ClassNames is the classNames argument value.
+			ClassNames []string
+		}
+		// ValidateCreate holds details about calls to the ValidateCreate method.
+		ValidateCreate []struct {
+			// ObjectDN is the objectDN argument value.
+			ObjectDN string
+			// ClassName is the className argument value.
+			ClassName string
+			// Attributes is the attributes argument value.
+			Attributes map[string]string
+		}
+	}
+	lockAttributes      sync.RWMutex
+	lockClass           sync.RWMutex
+	lockContainment     sync.RWMutex
+	lockHighestRevision sync.RWMutex
+	lockValidateCreate  sync.RWMutex
+}
+
+// Attributes calls AttributesFunc.
+func (mock *SchemaAPI) Attributes() ([]venafi.AttributeDefinition, error) {
+	if mock.AttributesFunc == nil {
+		panic("SchemaAPI.AttributesFunc: method is nil but SchemaAPI.Attributes was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockAttributes.Lock()
+	mock.calls.Attributes = append(mock.calls.Attributes, callInfo)
+	mock.lockAttributes.Unlock()
+	return mock.AttributesFunc()
+}
+
+// AttributesCalls gets all the calls that were made to Attributes.
+// Check the length with:
+//
+//	len(mockedSchemaAPI.AttributesCalls())
+func (mock *SchemaAPI) AttributesCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockAttributes.RLock()
+	calls = mock.calls.Attributes
+	mock.lockAttributes.RUnlock()
+	return calls
+}
+
+// Class calls ClassFunc.
+func (mock *SchemaAPI) Class(className string) (*venafi.ClassDefinition, error) {
+	if mock.ClassFunc == nil {
+		panic("SchemaAPI.ClassFunc: method is nil but SchemaAPI.Class was just called")
+	}
+	callInfo := struct {
+		ClassName string
+	}{
+		ClassName: className,
+	}
+	mock.lockClass.Lock()
+	mock.calls.Class = append(mock.calls.Class, callInfo)
+	mock.lockClass.Unlock()
+	return mock.ClassFunc(className)
+}
+
+// ClassCalls gets all the calls that were made to Class.
+// Check the length with:
+//
+//	len(mockedSchemaAPI.ClassCalls())
+func (mock *SchemaAPI) ClassCalls() []struct {
+	ClassName string
+} {
+	var calls []struct {
+		ClassName string
+	}
+	mock.lockClass.RLock()
+	calls = mock.calls.Class
+	mock.lockClass.RUnlock()
+	return calls
+}
+
+// Containment calls ContainmentFunc.
+func (mock *SchemaAPI) Containment(className string) ([]string, error) {
+	if mock.ContainmentFunc == nil {
+		panic("SchemaAPI.ContainmentFunc: method is nil but SchemaAPI.Containment was just called")
+	}
+	callInfo := struct {
+		ClassName string
+	}{
+		ClassName: className,
+	}
+	mock.lockContainment.Lock()
+	mock.calls.Containment = append(mock.calls.Containment, callInfo)
+	mock.lockContainment.Unlock()
+	return mock.ContainmentFunc(className)
+}
+
+// ContainmentCalls gets all the calls that were made to Containment.
+// Check the length with:
+//
+//	len(mockedSchemaAPI.ContainmentCalls())
+func (mock *SchemaAPI) ContainmentCalls() []struct {
+	ClassName string
+} {
+	var calls []struct {
+		ClassName string
+	}
+	mock.lockContainment.RLock()
+	calls = mock.calls.Containment
+	mock.lockContainment.RUnlock()
+	return calls
+}
+
+// HighestRevision calls HighestRevisionFunc.
+func (mock *SchemaAPI) HighestRevision(objectDN string, classNames ...string) (int64, error) {
+	if mock.HighestRevisionFunc == nil {
+		panic("SchemaAPI.HighestRevisionFunc: method is nil but SchemaAPI.HighestRevision was just called")
+	}
+	callInfo := struct {
+		ObjectDN   string
+		ClassNames []string
+	}{
+		ObjectDN:   objectDN,
+		ClassNames: classNames,
+	}
+	mock.lockHighestRevision.Lock()
+	mock.calls.HighestRevision = append(mock.calls.HighestRevision, callInfo)
+	mock.lockHighestRevision.Unlock()
+	return mock.HighestRevisionFunc(objectDN, classNames...)
+}
+
+// HighestRevisionCalls gets all the calls that were made to HighestRevision.
+// Check the length with:
+//
+//	len(mockedSchemaAPI.HighestRevisionCalls())
+func (mock *SchemaAPI) HighestRevisionCalls() []struct {
+	ObjectDN   string
+	ClassNames []string
+} {
+	var calls []struct {
+		ObjectDN   string
+		ClassNames []string
+	}
+	mock.lockHighestRevision.RLock()
+	calls = mock.calls.HighestRevision
+	mock.lockHighestRevision.RUnlock()
+	return calls
+}
+
+// ValidateCreate calls ValidateCreateFunc.
+func (mock *SchemaAPI) ValidateCreate(objectDN string, className string, attributes map[string]string) error {
+	if mock.ValidateCreateFunc == nil {
+		panic("SchemaAPI.ValidateCreateFunc: method is nil but SchemaAPI.ValidateCreate was just called")
+	}
+	callInfo := struct {
+		ObjectDN   string
+		ClassName  string
+		Attributes map[string]string
+	}{
+		ObjectDN:   objectDN,
+		ClassName:  className,
+		Attributes: attributes,
+	}
+	mock.lockValidateCreate.Lock()
+	mock.calls.ValidateCreate = append(mock.calls.ValidateCreate, callInfo)
+	mock.lockValidateCreate.Unlock()
+	return mock.ValidateCreateFunc(objectDN, className, attributes)
+}
+
+// ValidateCreateCalls gets all the calls that were made to ValidateCreate.
+// Check the length with:
+//
+//	len(mockedSchemaAPI.ValidateCreateCalls())
+func (mock *SchemaAPI) ValidateCreateCalls() []struct {
+	ObjectDN   string
+	ClassName  string
+	Attributes map[string]string
+} {
+	var calls []struct {
+		ObjectDN   string
+		ClassName  string
+		Attributes map[string]string
+	}
+	mock.lockValidateCreate.RLock()
+	calls = mock.calls.ValidateCreate
+	mock.lockValidateCreate.RUnlock()
+	return calls
+}
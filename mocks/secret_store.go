@@ -0,0 +1,631 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"sync"
+)
+
+// Ensure, that SecretStoreAPI does implement venafi.SecretStoreAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.SecretStoreAPI = &SecretStoreAPI{}
+
+// SecretStoreAPI is a mock implementation of venafi.SecretStoreAPI.
+//
+//	func TestSomethingThatUsesSecretStoreAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.SecretStoreAPI
+//		mockedSecretStoreAPI := &SecretStoreAPI{
+//			AddFunc: func(data []byte, vaultType secret_store.VaultType, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error) {
+//				panic("mock out the Add method")
+//			},
+//			AssociateFunc: func(vaultID int, name string, value interface{}) error {
+//				panic("mock out the Associate method")
+//			},
+//			DissociateFunc: func(vaultID int, name string, value interface{}) error {
+//				panic("mock out the Dissociate method")
+//			},
+//			EncryptionKeysInUseFunc: func() ([]secret_store.ProtectionKey, error) {
+//				panic("mock out the EncryptionKeysInUse method")
+//			},
+//			LookupByAssociationFunc: func(name string, value interface{}) ([]int, error) {
+//				panic("mock out the LookupByAssociation method")
+//			},
+//			LookupByOwnerFunc: func(ownerDN string, vaultType secret_store.VaultType) ([]int, error) {
+//				panic("mock out the LookupByOwner method")
+//			},
+//			MutateFunc: func(vaultID int, vaultType secret_store.VaultType) error {
+//				panic("mock out the Mutate method")
+//			},
+//			OwnerAddFunc: func(vaultID int, ownerDN string) error {
+//				panic("mock out the OwnerAdd method")
+//			},
+//			OwnerDeleteFunc: func(vaultID int, ownerDN string) error {
+//				panic("mock out the OwnerDelete method")
+//			},
+//			OwnerLookupFunc: func(vaultID int) ([]string, error) {
+//				panic("mock out the OwnerLookup method")
+//			},
+//			ReEncryptFunc: func(vaultID int, protectionKey secret_store.ProtectionKey) error {
+//				panic("mock out the ReEncrypt method")
+//			},
+//			RetrieveFunc: func(vaultID int) ([]byte, secret_store.VaultType, error) {
+//				panic("mock out the Retrieve method")
+//			},
+//		}
+//
+//		// use mockedSecretStoreAPI in code that requires venafi.SecretStoreAPI
+//		// and then make assertions.
+//
+//	}
+type SecretStoreAPI struct {
+	// AddFunc mocks the Add method.
+	AddFunc func(data []byte, vaultType secret_store.VaultType, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error)
+
+	// AssociateFunc mocks the Associate method.
+	AssociateFunc func(vaultID int, name string, value interface{}) error
+
+	// DissociateFunc mocks the Dissociate method.
+	DissociateFunc func(vaultID int, name string, value interface{}) error
+
+	// EncryptionKeysInUseFunc mocks the EncryptionKeysInUse method.
+	EncryptionKeysInUseFunc func() ([]secret_store.ProtectionKey, error)
+
+	// LookupByAssociationFunc mocks the LookupByAssociation method.
+	LookupByAssociationFunc func(name string, value interface{}) ([]int, error)
+
+	// LookupByOwnerFunc mocks the LookupByOwner method.
+	LookupByOwnerFunc func(ownerDN string, vaultType secret_store.VaultType) ([]int, error)
+
+	// MutateFunc mocks the Mutate method.
+	MutateFunc func(vaultID int, vaultType secret_store.VaultType) error
+
+	// OwnerAddFunc mocks the OwnerAdd method.
+	OwnerAddFunc func(vaultID int, ownerDN string) error
+
+	// OwnerDeleteFunc mocks the OwnerDelete method.
+	OwnerDeleteFunc func(vaultID int, ownerDN string) error
+
+	// OwnerLookupFunc mocks the OwnerLookup method.
+	OwnerLookupFunc func(vaultID int) ([]string, error)
+
+	// ReEncryptFunc mocks the ReEncrypt method.
+	ReEncryptFunc func(vaultID int, protectionKey secret_store.ProtectionKey) error
+
+	// RetrieveFunc mocks the Retrieve method.
+	RetrieveFunc func(vaultID int) ([]byte, secret_store.VaultType, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Add holds details about calls to the Add method.
+		Add []struct {
+			// Data is the data argument value.
+			Data []byte
+			// VaultType is the vaultType argument value.
+			VaultType secret_store.VaultType
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+			// ProtectionKey is the protectionKey argument value.
+			ProtectionKey secret_store.ProtectionKey
+		}
+		// Associate holds details about calls to the Associate method.
+		Associate []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value interface{}
+		}
+		// Dissociate holds details about calls to the Dissociate method.
+		Dissociate []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value interface{}
+		}
+		// EncryptionKeysInUse holds details about calls to the EncryptionKeysInUse method.
+		EncryptionKeysInUse []struct {
+		}
+		// LookupByAssociation holds details about calls to the LookupByAssociation method.
+		LookupByAssociation []struct {
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value interface{}
+		}
+		// LookupByOwner holds details about calls to the LookupByOwner method.
+		LookupByOwner []struct {
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+			// VaultType is the vaultType argument value.
+			VaultType secret_store.VaultType
+		}
+		// Mutate holds details about calls to the Mutate method.
+		Mutate []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+			// VaultType is the vaultType argument value.
+			VaultType secret_store.VaultType
+		}
+		// OwnerAdd holds details about calls to the OwnerAdd method.
+		OwnerAdd []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+		}
+		// OwnerDelete holds details about calls to the OwnerDelete method.
+		OwnerDelete []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+		}
+		// OwnerLookup holds details about calls to the OwnerLookup method.
+		OwnerLookup []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+		}
+		// ReEncrypt holds details about calls to the ReEncrypt method.
+		ReEncrypt []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+			// ProtectionKey is the protectionKey argument value.
+			ProtectionKey secret_store.ProtectionKey
+		}
+		// Retrieve holds details about calls to the Retrieve method.
+		Retrieve []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+		}
+	}
+	lockAdd                 sync.RWMutex
+	lockAssociate           sync.RWMutex
+	lockDissociate          sync.RWMutex
+	lockEncryptionKeysInUse sync.RWMutex
+	lockLookupByAssociation sync.RWMutex
+	lockLookupByOwner       sync.RWMutex
+	lockMutate              sync.RWMutex
+	lockOwnerAdd            sync.RWMutex
+	lockOwnerDelete         sync.RWMutex
+	lockOwnerLookup         sync.RWMutex
+	lockReEncrypt           sync.RWMutex
+	lockRetrieve            sync.RWMutex
+}
+
+// Add calls AddFunc.
+func (mock *SecretStoreAPI) Add(data []byte, vaultType secret_store.VaultType, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error) {
+	if mock.AddFunc == nil {
+		panic("SecretStoreAPI.AddFunc: method is nil but SecretStoreAPI.Add was just called")
+	}
+	callInfo := struct {
+		Data          []byte
+		VaultType     secret_store.VaultType
+		OwnerDN       string
+		ProtectionKey secret_store.ProtectionKey
+	}{
+		Data:          data,
+		VaultType:     vaultType,
+		OwnerDN:       ownerDN,
+		ProtectionKey: protectionKey,
+	}
+	mock.lockAdd.Lock()
+	mock.calls.Add = append(mock.calls.Add, callInfo)
+	mock.lockAdd.Unlock()
+	return mock.AddFunc(data, vaultType, ownerDN, protectionKey)
+}
+
+// AddCalls gets all the calls that were made to Add.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.AddCalls())
+func (mock *SecretStoreAPI) AddCalls() []struct {
+	Data          []byte
+	VaultType     secret_store.VaultType
+	OwnerDN       string
+	ProtectionKey secret_store.ProtectionKey
+} {
+	var calls []struct {
+		Data          []byte
+		VaultType     secret_store.VaultType
+		OwnerDN       string
+		ProtectionKey secret_store.ProtectionKey
+	}
+	mock.lockAdd.RLock()
+	calls = mock.calls.Add
+	mock.lockAdd.RUnlock()
+	return calls
+}
+
+// Associate calls AssociateFunc.
+func (mock *SecretStoreAPI) Associate(vaultID int, name string, value interface{}) error {
+	if mock.AssociateFunc == nil {
+		panic("SecretStoreAPI.AssociateFunc: method is nil but SecretStoreAPI.Associate was just called")
+	}
+	callInfo := struct {
+		VaultID int
+		Name    string
+		Value   interface{}
+	}{
+		VaultID: vaultID,
+		Name:    name,
+		Value:   value,
+	}
+	mock.lockAssociate.Lock()
+	mock.calls.Associate = append(mock.calls.Associate, callInfo)
+	mock.lockAssociate.Unlock()
+	return mock.AssociateFunc(vaultID, name, value)
+}
+
+// AssociateCalls gets all the calls that were made to Associate.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.AssociateCalls())
+func (mock *SecretStoreAPI) AssociateCalls() []struct {
+	VaultID int
+	Name    string
+	Value   interface{}
+} {
+	var calls []struct {
+		VaultID int
+		Name    string
+		Value   interface{}
+	}
+	mock.lockAssociate.RLock()
+	calls = mock.calls.Associate
+	mock.lockAssociate.RUnlock()
+	return calls
+}
+
+// Dissociate calls DissociateFunc.
+func (mock *SecretStoreAPI) Dissociate(vaultID int, name string, value interface{}) error {
+	if mock.DissociateFunc == nil {
+		panic("SecretStoreAPI.DissociateFunc: method is nil but SecretStoreAPI.Dissociate was just called")
+	}
+	callInfo := struct {
+		VaultID int
+		Name    string
+		Value   interface{}
+	}{
+		VaultID: vaultID,
+		Name:    name,
+		Value:   value,
+	}
+	mock.lockDissociate.Lock()
+	mock.calls.Dissociate = append(mock.calls.Dissociate, callInfo)
+	mock.lockDissociate.Unlock()
+	return mock.DissociateFunc(vaultID, name, value)
+}
+
+// DissociateCalls gets all the calls that were made to Dissociate.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.DissociateCalls())
+func (mock *SecretStoreAPI) DissociateCalls() []struct {
+	VaultID int
+	Name    string
+	Value   interface{}
+} {
+	var calls []struct {
+		VaultID int
+		Name    string
+		Value   interface{}
+	}
+	mock.lockDissociate.RLock()
+	calls = mock.calls.Dissociate
+	mock.lockDissociate.RUnlock()
+	return calls
+}
+
+// EncryptionKeysInUse calls EncryptionKeysInUseFunc.
+func (mock *SecretStoreAPI) EncryptionKeysInUse() ([]secret_store.ProtectionKey, error) {
+	if mock.EncryptionKeysInUseFunc == nil {
+		panic("SecretStoreAPI.EncryptionKeysInUseFunc: method is nil but SecretStoreAPI.EncryptionKeysInUse was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockEncryptionKeysInUse.Lock()
+	mock.calls.EncryptionKeysInUse = append(mock.calls.EncryptionKeysInUse, callInfo)
+	mock.lockEncryptionKeysInUse.Unlock()
+	return mock.EncryptionKeysInUseFunc()
+}
+
+// EncryptionKeysInUseCalls gets all the calls that were made to EncryptionKeysInUse.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.EncryptionKeysInUseCalls())
+func (mock *SecretStoreAPI) EncryptionKeysInUseCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockEncryptionKeysInUse.RLock()
+	calls = mock.calls.EncryptionKeysInUse
+	mock.lockEncryptionKeysInUse.RUnlock()
+	return calls
+}
+
+// LookupByAssociation calls LookupByAssociationFunc.
+func (mock *SecretStoreAPI) LookupByAssociation(name string, value interface{}) ([]int, error) {
+	if mock.LookupByAssociationFunc == nil {
+		panic("SecretStoreAPI.LookupByAssociationFunc: method is nil but SecretStoreAPI.LookupByAssociation was just called")
+	}
+	callInfo := struct {
+		Name  string
+		Value interface{}
+	}{
+		Name:  name,
+		Value: value,
+	}
+	mock.lockLookupByAssociation.Lock()
+	mock.calls.LookupByAssociation = append(mock.calls.LookupByAssociation, callInfo)
+	mock.lockLookupByAssociation.Unlock()
+	return mock.LookupByAssociationFunc(name, value)
+}
+
+// LookupByAssociationCalls gets all the calls that were made to LookupByAssociation.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.LookupByAssociationCalls())
+func (mock *SecretStoreAPI) LookupByAssociationCalls() []struct {
+	Name  string
+	Value interface{}
+} {
+	var calls []struct {
+		Name  string
+		Value interface{}
+	}
+	mock.lockLookupByAssociation.RLock()
+	calls = mock.calls.LookupByAssociation
+	mock.lockLookupByAssociation.RUnlock()
+	return calls
+}
+
+// LookupByOwner calls LookupByOwnerFunc.
+func (mock *SecretStoreAPI) LookupByOwner(ownerDN string, vaultType secret_store.VaultType) ([]int, error) {
+	if mock.LookupByOwnerFunc == nil {
+		panic("SecretStoreAPI.LookupByOwnerFunc: method is nil but SecretStoreAPI.LookupByOwner was just called")
+	}
+	callInfo := struct {
+		OwnerDN   string
+		VaultType secret_store.VaultType
+	}{
+		OwnerDN:   ownerDN,
+		VaultType: vaultType,
+	}
+	mock.lockLookupByOwner.Lock()
+	mock.calls.LookupByOwner = append(mock.calls.LookupByOwner, callInfo)
+	mock.lockLookupByOwner.Unlock()
+	return mock.LookupByOwnerFunc(ownerDN, vaultType)
+}
+
+// LookupByOwnerCalls gets all the calls that were made to LookupByOwner.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.LookupByOwnerCalls())
+func (mock *SecretStoreAPI) LookupByOwnerCalls() []struct {
+	OwnerDN   string
+	VaultType secret_store.VaultType
+} {
+	var calls []struct {
+		OwnerDN   string
+		VaultType secret_store.VaultType
+	}
+	mock.lockLookupByOwner.RLock()
+	calls = mock.calls.LookupByOwner
+	mock.lockLookupByOwner.RUnlock()
+	return calls
+}
+
+// Mutate calls MutateFunc.
+func (mock *SecretStoreAPI) Mutate(vaultID int, vaultType secret_store.VaultType) error {
+	if mock.MutateFunc == nil {
+		panic("SecretStoreAPI.MutateFunc: method is nil but SecretStoreAPI.Mutate was just called")
+	}
+	callInfo := struct {
+		VaultID   int
+		VaultType secret_store.VaultType
+	}{
+		VaultID:   vaultID,
+		VaultType: vaultType,
+	}
+	mock.lockMutate.Lock()
+	mock.calls.Mutate = append(mock.calls.Mutate, callInfo)
+	mock.lockMutate.Unlock()
+	return mock.MutateFunc(vaultID, vaultType)
+}
+
+// MutateCalls gets all the calls that were made to Mutate.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.MutateCalls())
+func (mock *SecretStoreAPI) MutateCalls() []struct {
+	VaultID   int
+	VaultType secret_store.VaultType
+} {
+	var calls []struct {
+		VaultID   int
+		VaultType secret_store.VaultType
+	}
+	mock.lockMutate.RLock()
+	calls = mock.calls.Mutate
+	mock.lockMutate.RUnlock()
+	return calls
+}
+
+// OwnerAdd calls OwnerAddFunc.
+func (mock *SecretStoreAPI) OwnerAdd(vaultID int, ownerDN string) error {
+	if mock.OwnerAddFunc == nil {
+		panic("SecretStoreAPI.OwnerAddFunc: method is nil but SecretStoreAPI.OwnerAdd was just called")
+	}
+	callInfo := struct {
+		VaultID int
+		OwnerDN string
+	}{
+		VaultID: vaultID,
+		OwnerDN: ownerDN,
+	}
+	mock.lockOwnerAdd.Lock()
+	mock.calls.OwnerAdd = append(mock.calls.OwnerAdd, callInfo)
+	mock.lockOwnerAdd.Unlock()
+	return mock.OwnerAddFunc(vaultID, ownerDN)
+}
+
+// OwnerAddCalls gets all the calls that were made to OwnerAdd.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.OwnerAddCalls())
+func (mock *SecretStoreAPI) OwnerAddCalls() []struct {
+	VaultID int
+	OwnerDN string
+} {
+	var calls []struct {
+		VaultID int
+		OwnerDN string
+	}
+	mock.lockOwnerAdd.RLock()
+	calls = mock.calls.OwnerAdd
+	mock.lockOwnerAdd.RUnlock()
+	return calls
+}
+
+// OwnerDelete calls OwnerDeleteFunc.
+func (mock *SecretStoreAPI) OwnerDelete(vaultID int, ownerDN string) error {
+	if mock.OwnerDeleteFunc == nil {
+		panic("SecretStoreAPI.OwnerDeleteFunc: method is nil but SecretStoreAPI.OwnerDelete was just called")
+	}
+	callInfo := struct {
+		VaultID int
+		OwnerDN string
+	}{
+		VaultID: vaultID,
+		OwnerDN: ownerDN,
+	}
+	mock.lockOwnerDelete.Lock()
+	mock.calls.OwnerDelete = append(mock.calls.OwnerDelete, callInfo)
+	mock.lockOwnerDelete.Unlock()
+	return mock.OwnerDeleteFunc(vaultID, ownerDN)
+}
+
+// OwnerDeleteCalls gets all the calls that were made to OwnerDelete.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.OwnerDeleteCalls())
+func (mock *SecretStoreAPI) OwnerDeleteCalls() []struct {
+	VaultID int
+	OwnerDN string
+} {
+	var calls []struct {
+		VaultID int
+		OwnerDN string
+	}
+	mock.lockOwnerDelete.RLock()
+	calls = mock.calls.OwnerDelete
+	mock.lockOwnerDelete.RUnlock()
+	return calls
+}
+
+// OwnerLookup calls OwnerLookupFunc.
+func (mock *SecretStoreAPI) OwnerLookup(vaultID int) ([]string, error) {
+	if mock.OwnerLookupFunc == nil {
+		panic("SecretStoreAPI.OwnerLookupFunc: method is nil but SecretStoreAPI.OwnerLookup was just called")
+	}
+	callInfo := struct {
+		VaultID int
+	}{
+		VaultID: vaultID,
+	}
+	mock.lockOwnerLookup.Lock()
+	mock.calls.OwnerLookup = append(mock.calls.OwnerLookup, callInfo)
+	mock.lockOwnerLookup.Unlock()
+	return mock.OwnerLookupFunc(vaultID)
+}
+
+// OwnerLookupCalls gets all the calls that were made to OwnerLookup.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.OwnerLookupCalls())
+func (mock *SecretStoreAPI) OwnerLookupCalls() []struct {
+	VaultID int
+} {
+	var calls []struct {
+		VaultID int
+	}
+	mock.lockOwnerLookup.RLock()
+	calls = mock.calls.OwnerLookup
+	mock.lockOwnerLookup.RUnlock()
+	return calls
+}
+
+// ReEncrypt calls ReEncryptFunc.
+func (mock *SecretStoreAPI) ReEncrypt(vaultID int, protectionKey secret_store.ProtectionKey) error {
+	if mock.ReEncryptFunc == nil {
+		panic("SecretStoreAPI.ReEncryptFunc: method is nil but SecretStoreAPI.ReEncrypt was just called")
+	}
+	callInfo := struct {
+		VaultID       int
+		ProtectionKey secret_store.ProtectionKey
+	}{
+		VaultID:       vaultID,
+		ProtectionKey: protectionKey,
+	}
+	mock.lockReEncrypt.Lock()
+	mock.calls.ReEncrypt = append(mock.calls.ReEncrypt, callInfo)
+	mock.lockReEncrypt.Unlock()
+	return mock.ReEncryptFunc(vaultID, protectionKey)
+}
+
+// ReEncryptCalls gets all the calls that were made to ReEncrypt.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.ReEncryptCalls())
+func (mock *SecretStoreAPI) ReEncryptCalls() []struct {
+	VaultID       int
+	ProtectionKey secret_store.ProtectionKey
+} {
+	var calls []struct {
+		VaultID       int
+		ProtectionKey secret_store.ProtectionKey
+	}
+	mock.lockReEncrypt.RLock()
+	calls = mock.calls.ReEncrypt
+	mock.lockReEncrypt.RUnlock()
+	return calls
+}
+
+// Retrieve calls RetrieveFunc.
+func (mock *SecretStoreAPI) Retrieve(vaultID int) ([]byte, secret_store.VaultType, error) {
+	if mock.RetrieveFunc == nil {
+		panic("SecretStoreAPI.RetrieveFunc: method is nil but SecretStoreAPI.Retrieve was just called")
+	}
+	callInfo := struct {
+		VaultID int
+	}{
+		VaultID: vaultID,
+	}
+	mock.lockRetrieve.Lock()
+	mock.calls.Retrieve = append(mock.calls.Retrieve, callInfo)
+	mock.lockRetrieve.Unlock()
+	return mock.RetrieveFunc(vaultID)
+}
+
+// RetrieveCalls gets all the calls that were made to Retrieve.
+// Check the length with:
+//
+//	len(mockedSecretStoreAPI.RetrieveCalls())
+func (mock *SecretStoreAPI) RetrieveCalls() []struct {
+	VaultID int
+} {
+	var calls []struct {
+		VaultID int
+	}
+	mock.lockRetrieve.RLock()
+	calls = mock.calls.Retrieve
+	mock.lockRetrieve.RUnlock()
+	return calls
+}
@@ -0,0 +1,628 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mocks
+
+import (
+	"crypto"
+	"crypto/x509"
+	"github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"sync"
+)
+
+// Ensure, that X509StoreAPI does implement venafi.X509StoreAPI.
+// If this is not the case, regenerate this file with moq.
+var _ venafi.X509StoreAPI = &X509StoreAPI{}
+
+// X509StoreAPI is a mock implementation of venafi.X509StoreAPI.
+//
+//	func TestSomethingThatUsesX509StoreAPI(t *testing.T) {
+//
+//		// make and configure a mocked venafi.X509StoreAPI
+//		mockedX509StoreAPI := &X509StoreAPI{
+//			AddFunc: func(cert *x509.Certificate, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error) {
+//				panic("mock out the Add method")
+//			},
+//			AddPrivateKeyFunc: func(key crypto.Signer, certVaultID int, protectionKey secret_store.ProtectionKey) (int, error) {
+//				panic("mock out the AddPrivateKey method")
+//			},
+//			LookupFunc: func(cert *x509.Certificate, ownerDN string, name string, value string) ([]int, error) {
+//				panic("mock out the Lookup method")
+//			},
+//			LookupByCertificateFunc: func(cert *x509.Certificate) ([]int, error) {
+//				panic("mock out the LookupByCertificate method")
+//			},
+//			LookupByNameValueFunc: func(name string, value string) ([]int, error) {
+//				panic("mock out the LookupByNameValue method")
+//			},
+//			LookupByOwnerDNFunc: func(ownerDN string) ([]int, error) {
+//				panic("mock out the LookupByOwnerDN method")
+//			},
+//			LookupExpiringFunc: func(days int, ownerDN string) ([]int, error) {
+//				panic("mock out the LookupExpiring method")
+//			},
+//			LookupPrivateKeysFunc: func(certVaultID int) ([]int, error) {
+//				panic("mock out the LookupPrivateKeys method")
+//			},
+//			RemoveFunc: func(vaultID int, ownerDN string) error {
+//				panic("mock out the Remove method")
+//			},
+//			RetrieveFunc: func(vaultID int) (*x509.Certificate, error) {
+//				panic("mock out the Retrieve method")
+//			},
+//			RetrieveItemFunc: func(vaultID int) (*venafi.VaultItem, error) {
+//				panic("mock out the RetrieveItem method")
+//			},
+//			RetrievePrivateKeyFunc: func(vaultID int, password string) (crypto.Signer, error) {
+//				panic("mock out the RetrievePrivateKey method")
+//			},
+//		}
+//
+//		// use mockedX509StoreAPI in code that requires venafi.X509StoreAPI
+//		// and then make assertions.
+//
+//	}
+type X509StoreAPI struct {
+	// AddFunc mocks the Add method.
+	AddFunc func(cert *x509.Certificate, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error)
+
+	// AddPrivateKeyFunc mocks the AddPrivateKey method.
+	AddPrivateKeyFunc func(key crypto.Signer, certVaultID int, protectionKey secret_store.ProtectionKey) (int, error)
+
+	// LookupFunc mocks the Lookup method.
+	LookupFunc func(cert *x509.Certificate, ownerDN string, name string, value string) ([]int, error)
+
+	// LookupByCertificateFunc mocks the LookupByCertificate method.
+	LookupByCertificateFunc func(cert *x509.Certificate) ([]int, error)
+
+	// LookupByNameValueFunc mocks the LookupByNameValue method.
+	LookupByNameValueFunc func(name string, value string) ([]int, error)
+
+	// LookupByOwnerDNFunc mocks the LookupByOwnerDN method.
+	LookupByOwnerDNFunc func(ownerDN string) ([]int, error)
+
+	// LookupExpiringFunc mocks the LookupExpiring method.
+	LookupExpiringFunc func(days int, ownerDN string) ([]int, error)
+
+	// LookupPrivateKeysFunc mocks the LookupPrivateKeys method.
+	LookupPrivateKeysFunc func(certVaultID int) ([]int, error)
+
+	// RemoveFunc mocks the Remove method.
+	RemoveFunc func(vaultID int, ownerDN string) error
+
+	// RetrieveFunc mocks the Retrieve method.
+	RetrieveFunc func(vaultID int) (*x509.Certificate, error)
+
+	// RetrieveItemFunc mocks the RetrieveItem method.
+	RetrieveItemFunc func(vaultID int) (*venafi.VaultItem, error)
+
+	// RetrievePrivateKeyFunc mocks the RetrievePrivateKey method.
+	RetrievePrivateKeyFunc func(vaultID int, password string) (crypto.Signer, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Add holds details about calls to the Add method.
+		Add []struct {
+			// Cert is the cert argument value.
+			Cert *x509.Certificate
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+			// ProtectionKey is the protectionKey argument value.
+			ProtectionKey secret_store.ProtectionKey
+		}
+		// AddPrivateKey holds details about calls to the AddPrivateKey method.
+		AddPrivateKey []struct {
+			// Key is the key argument value.
+			Key crypto.Signer
+			// CertVaultID is the certVaultID argument value.
+			CertVaultID int
+			// ProtectionKey is the protectionKey argument value.
+			ProtectionKey secret_store.ProtectionKey
+		}
+		// Lookup holds details about calls to the Lookup method.
+		Lookup []struct {
+			// Cert is the cert argument value.
+			Cert *x509.Certificate
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value string
+		}
+		// LookupByCertificate holds details about calls to the LookupByCertificate method.
+		LookupByCertificate []struct {
+			// Cert is the cert argument value.
+			Cert *x509.Certificate
+		}
+		// LookupByNameValue holds details about calls to the LookupByNameValue method.
+		LookupByNameValue []struct {
+			// Name is the name argument value.
+			Name string
+			// Value is the value argument value.
+			Value string
+		}
+		// LookupByOwnerDN holds details about calls to the LookupByOwnerDN method.
+		LookupByOwnerDN []struct {
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+		}
+		// LookupExpiring holds details about calls to the LookupExpiring method.
+		LookupExpiring []struct {
+			// Days is the days argument value.
+			Days int
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+		}
+		// LookupPrivateKeys holds details about calls to the LookupPrivateKeys method.
+		LookupPrivateKeys []struct {
+			// CertVaultID is the certVaultID argument value.
+			CertVaultID int
+		}
+		// Remove holds details about calls to the Remove method.
+		Remove []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+			// OwnerDN is the ownerDN argument value.
+			OwnerDN string
+		}
+		// Retrieve holds details about calls to the Retrieve method.
+		Retrieve []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+		}
+		// RetrieveItem holds details about calls to the RetrieveItem method.
+		RetrieveItem []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+		}
+		// RetrievePrivateKey holds details about calls to the RetrievePrivateKey method.
+		RetrievePrivateKey []struct {
+			// VaultID is the vaultID argument value.
+			VaultID int
+			// Password is the password argument value.
+			Password string
+		}
+	}
+	lockAdd                 sync.RWMutex
+	lockAddPrivateKey       sync.RWMutex
+	lockLookup              sync.RWMutex
+	lockLookupByCertificate sync.RWMutex
+	lockLookupByNameValue   sync.RWMutex
+	lockLookupByOwnerDN     sync.RWMutex
+	lockLookupExpiring      sync.RWMutex
+	lockLookupPrivateKeys   sync.RWMutex
+	lockRemove              sync.RWMutex
+	lockRetrieve            sync.RWMutex
+	lockRetrieveItem        sync.RWMutex
+	lockRetrievePrivateKey  sync.RWMutex
+}
+
+// Add calls AddFunc.
+func (mock *X509StoreAPI) Add(cert *x509.Certificate, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error) {
+	if mock.AddFunc == nil {
+		panic("X509StoreAPI.AddFunc: method is nil but X509StoreAPI.Add was just called")
+	}
+	callInfo := struct {
+		Cert          *x509.Certificate
+		OwnerDN       string
+		ProtectionKey secret_store.ProtectionKey
+	}{
+		Cert:          cert,
+		OwnerDN:       ownerDN,
+		ProtectionKey: protectionKey,
+	}
+	mock.lockAdd.Lock()
+	mock.calls.Add = append(mock.calls.Add, callInfo)
+	mock.lockAdd.Unlock()
+	return mock.AddFunc(cert, ownerDN, protectionKey)
+}
+
+// AddCalls gets all the calls that were made to Add.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.AddCalls())
+func (mock *X509StoreAPI) AddCalls() []struct {
+	Cert          *x509.Certificate
+	OwnerDN       string
+	ProtectionKey secret_store.ProtectionKey
+} {
+	var calls []struct {
+		Cert          *x509.Certificate
+		OwnerDN       string
+		ProtectionKey secret_store.ProtectionKey
+	}
+	mock.lockAdd.RLock()
+	calls = mock.calls.Add
+	mock.lockAdd.RUnlock()
+	return calls
+}
+
+// AddPrivateKey calls AddPrivateKeyFunc.
+func (mock *X509StoreAPI) AddPrivateKey(key crypto.Signer, certVaultID int, protectionKey secret_store.ProtectionKey) (int, error) {
+	if mock.AddPrivateKeyFunc == nil {
+		panic("X509StoreAPI.AddPrivateKeyFunc: method is nil but X509StoreAPI.AddPrivateKey was just called")
+	}
+	callInfo := struct {
+		Key           crypto.Signer
+		CertVaultID   int
+		ProtectionKey secret_store.ProtectionKey
+	}{
+		Key:           key,
+		CertVaultID:   certVaultID,
+		ProtectionKey: protectionKey,
+	}
+	mock.lockAddPrivateKey.Lock()
+	mock.calls.AddPrivateKey = append(mock.calls.AddPrivateKey, callInfo)
+	mock.lockAddPrivateKey.Unlock()
+	return mock.AddPrivateKeyFunc(key, certVaultID, protectionKey)
+}
+
+// AddPrivateKeyCalls gets all the calls that were made to AddPrivateKey.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.AddPrivateKeyCalls())
+func (mock *X509StoreAPI) AddPrivateKeyCalls() []struct {
+	Key           crypto.Signer
+	CertVaultID   int
+	ProtectionKey secret_store.ProtectionKey
+} {
+	var calls []struct {
+		Key           crypto.Signer
+		CertVaultID   int
+		ProtectionKey secret_store.ProtectionKey
+	}
+	mock.lockAddPrivateKey.RLock()
+	calls = mock.calls.AddPrivateKey
+	mock.lockAddPrivateKey.RUnlock()
+	return calls
+}
+
+// Lookup calls LookupFunc.
+func (mock *X509StoreAPI) Lookup(cert *x509.Certificate, ownerDN string, name string, value string) ([]int, error) {
+	if mock.LookupFunc == nil {
+		panic("X509StoreAPI.LookupFunc: method is nil but X509StoreAPI.Lookup was just called")
+	}
+	callInfo := struct {
+		Cert    *x509.Certificate
+		OwnerDN string
+		Name    string
+		Value   string
+	}{
+		Cert:    cert,
+		OwnerDN: ownerDN,
+		Name:    name,
+		Value:   value,
+	}
+	mock.lockLookup.Lock()
+	mock.calls.Lookup = append(mock.calls.Lookup, callInfo)
+	mock.lockLookup.Unlock()
+	return mock.LookupFunc(cert, ownerDN, name, value)
+}
+
+// LookupCalls gets all the calls that were made to Lookup.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.LookupCalls())
+func (mock *X509StoreAPI) LookupCalls() []struct {
+	Cert    *x509.Certificate
+	OwnerDN string
+	Name    string
+	Value   string
+} {
+	var calls []struct {
+		Cert    *x509.Certificate
+		OwnerDN string
+		Name    string
+		Value   string
+	}
+	mock.lockLookup.RLock()
+	calls = mock.calls.Lookup
+	mock.lockLookup.RUnlock()
+	return calls
+}
+
+// LookupByCertificate calls LookupByCertificateFunc.
+func (mock *X509StoreAPI) LookupByCertificate(cert *x509.Certificate) ([]int, error) {
+	if mock.LookupByCertificateFunc == nil {
+		panic("X509StoreAPI.LookupByCertificateFunc: method is nil but X509StoreAPI.LookupByCertificate was just called")
+	}
+	callInfo := struct {
+		Cert *x509.Certificate
+	}{
+		Cert: cert,
+	}
+	mock.lockLookupByCertificate.Lock()
+	mock.calls.LookupByCertificate = append(mock.calls.LookupByCertificate, callInfo)
+	mock.lockLookupByCertificate.Unlock()
+	return mock.LookupByCertificateFunc(cert)
+}
+
+// LookupByCertificateCalls gets all the calls that were made to LookupByCertificate.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.LookupByCertificateCalls())
+func (mock *X509StoreAPI) LookupByCertificateCalls() []struct {
+	Cert *x509.Certificate
+} {
+	var calls []struct {
+		Cert *x509.Certificate
+	}
+	mock.lockLookupByCertificate.RLock()
+	calls = mock.calls.LookupByCertificate
+	mock.lockLookupByCertificate.RUnlock()
+	return calls
+}
+
+// LookupByNameValue calls LookupByNameValueFunc.
+func (mock *X509StoreAPI) LookupByNameValue(name string, value string) ([]int, error) {
+	if mock.LookupByNameValueFunc == nil {
+		panic("X509StoreAPI.LookupByNameValueFunc: method is nil but X509StoreAPI.LookupByNameValue was just called")
+	}
+	callInfo := struct {
+		Name  string
+		Value string
+	}{
+		Name:  name,
+		Value: value,
+	}
+	mock.lockLookupByNameValue.Lock()
+	mock.calls.LookupByNameValue = append(mock.calls.LookupByNameValue, callInfo)
+	mock.lockLookupByNameValue.Unlock()
+	return mock.LookupByNameValueFunc(name, value)
+}
+
+// LookupByNameValueCalls gets all the calls that were made to LookupByNameValue.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.LookupByNameValueCalls())
+func (mock *X509StoreAPI) LookupByNameValueCalls() []struct {
+	Name  string
+	Value string
+} {
+	var calls []struct {
+		Name  string
+		Value string
+	}
+	mock.lockLookupByNameValue.RLock()
+	calls = mock.calls.LookupByNameValue
+	mock.lockLookupByNameValue.RUnlock()
+	return calls
+}
+
+// LookupByOwnerDN calls LookupByOwnerDNFunc.
+func (mock *X509StoreAPI) LookupByOwnerDN(ownerDN string) ([]int, error) {
+	if mock.LookupByOwnerDNFunc == nil {
+		panic("X509StoreAPI.LookupByOwnerDNFunc: method is nil but X509StoreAPI.LookupByOwnerDN was just called")
+	}
+	callInfo := struct {
+		OwnerDN string
+	}{
+		OwnerDN: ownerDN,
+	}
+	mock.lockLookupByOwnerDN.Lock()
+	mock.calls.LookupByOwnerDN = append(mock.calls.LookupByOwnerDN, callInfo)
+	mock.lockLookupByOwnerDN.Unlock()
+	return mock.LookupByOwnerDNFunc(ownerDN)
+}
+
+// LookupByOwnerDNCalls gets all the calls that were made to LookupByOwnerDN.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.LookupByOwnerDNCalls())
+func (mock *X509StoreAPI) LookupByOwnerDNCalls() []struct {
+	OwnerDN string
+} {
+	var calls []struct {
+		OwnerDN string
+	}
+	mock.lockLookupByOwnerDN.RLock()
+	calls = mock.calls.LookupByOwnerDN
+	mock.lockLookupByOwnerDN.RUnlock()
+	return calls
+}
+
+// LookupExpiring calls LookupExpiringFunc.
+func (mock *X509StoreAPI) LookupExpiring(days int, ownerDN string) ([]int, error) {
+	if mock.LookupExpiringFunc == nil {
+		panic("X509StoreAPI.LookupExpiringFunc: method is nil but X509StoreAPI.LookupExpiring was just called")
+	}
+	callInfo := struct {
+		Days    int
+		OwnerDN string
+	}{
+		Days:    days,
+		OwnerDN: ownerDN,
+	}
+	mock.lockLookupExpiring.Lock()
+	mock.calls.LookupExpiring = append(mock.calls.LookupExpiring, callInfo)
+	mock.lockLookupExpiring.Unlock()
+	return mock.LookupExpiringFunc(days, ownerDN)
+}
+
+// LookupExpiringCalls gets all the calls that were made to LookupExpiring.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.LookupExpiringCalls())
+func (mock *X509StoreAPI) LookupExpiringCalls() []struct {
+	Days    int
+	OwnerDN string
+} {
+	var calls []struct {
+		Days    int
+		OwnerDN string
+	}
+	mock.lockLookupExpiring.RLock()
+	calls = mock.calls.LookupExpiring
+	mock.lockLookupExpiring.RUnlock()
+	return calls
+}
+
+// LookupPrivateKeys calls LookupPrivateKeysFunc.
+func (mock *X509StoreAPI) LookupPrivateKeys(certVaultID int) ([]int, error) {
+	if mock.LookupPrivateKeysFunc == nil {
+		panic("X509StoreAPI.LookupPrivateKeysFunc: method is nil but X509StoreAPI.LookupPrivateKeys was just called")
+	}
+	callInfo := struct {
+		CertVaultID int
+	}{
+		CertVaultID: certVaultID,
+	}
+	mock.lockLookupPrivateKeys.Lock()
+	mock.calls.LookupPrivateKeys = append(mock.calls.LookupPrivateKeys, callInfo)
+	mock.lockLookupPrivateKeys.Unlock()
+	return mock.LookupPrivateKeysFunc(certVaultID)
+}
+
+// LookupPrivateKeysCalls gets all the calls that were made to LookupPrivateKeys.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.LookupPrivateKeysCalls())
+func (mock *X509StoreAPI) LookupPrivateKeysCalls() []struct {
+	CertVaultID int
+} {
+	var calls []struct {
+		CertVaultID int
+	}
+	mock.lockLookupPrivateKeys.RLock()
+	calls = mock.calls.LookupPrivateKeys
+	mock.lockLookupPrivateKeys.RUnlock()
+	return calls
+}
+
+// Remove calls RemoveFunc.
+func (mock *X509StoreAPI) Remove(vaultID int, ownerDN string) error {
+	if mock.RemoveFunc == nil {
+		panic("X509StoreAPI.RemoveFunc: method is nil but X509StoreAPI.Remove was just called")
+	}
+	callInfo := struct {
+		VaultID int
+		OwnerDN string
+	}{
+		VaultID: vaultID,
+		OwnerDN: ownerDN,
+	}
+	mock.lockRemove.Lock()
+	mock.calls.Remove = append(mock.calls.Remove, callInfo)
+	mock.lockRemove.Unlock()
+	return mock.RemoveFunc(vaultID, ownerDN)
+}
+
+// RemoveCalls gets all the calls that were made to Remove.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.RemoveCalls())
+func (mock *X509StoreAPI) RemoveCalls() []struct {
+	VaultID int
+	OwnerDN string
+} {
+	var calls []struct {
+		VaultID int
+		OwnerDN string
+	}
+	mock.lockRemove.RLock()
+	calls = mock.calls.Remove
+	mock.lockRemove.RUnlock()
+	return calls
+}
+
+// Retrieve calls RetrieveFunc.
+func (mock *X509StoreAPI) Retrieve(vaultID int) (*x509.Certificate, error) {
+	if mock.RetrieveFunc == nil {
+		panic("X509StoreAPI.RetrieveFunc: method is nil but X509StoreAPI.Retrieve was just called")
+	}
+	callInfo := struct {
+		VaultID int
+	}{
+		VaultID: vaultID,
+	}
+	mock.lockRetrieve.Lock()
+	mock.calls.Retrieve = append(mock.calls.Retrieve, callInfo)
+	mock.lockRetrieve.Unlock()
+	return mock.RetrieveFunc(vaultID)
+}
+
+// RetrieveCalls gets all the calls that were made to Retrieve.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.RetrieveCalls())
+func (mock *X509StoreAPI) RetrieveCalls() []struct {
+	VaultID int
+} {
+	var calls []struct {
+		VaultID int
+	}
+	mock.lockRetrieve.RLock()
+	calls = mock.calls.Retrieve
+	mock.lockRetrieve.RUnlock()
+	return calls
+}
+
+// RetrieveItem calls RetrieveItemFunc.
+func (mock *X509StoreAPI) RetrieveItem(vaultID int) (*venafi.VaultItem, error) {
+	if mock.RetrieveItemFunc == nil {
+		panic("X509StoreAPI.RetrieveItemFunc: method is nil but X509StoreAPI.RetrieveItem was just called")
+	}
+	callInfo := struct {
+		VaultID int
+	}{
+		VaultID: vaultID,
+	}
+	mock.lockRetrieveItem.Lock()
+	mock.calls.RetrieveItem = append(mock.calls.RetrieveItem, callInfo)
+	mock.lockRetrieveItem.Unlock()
+	return mock.RetrieveItemFunc(vaultID)
+}
+
+// RetrieveItemCalls gets all the calls that were made to RetrieveItem.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.RetrieveItemCalls())
+func (mock *X509StoreAPI) RetrieveItemCalls() []struct {
+	VaultID int
+} {
+	var calls []struct {
+		VaultID int
+	}
+	mock.lockRetrieveItem.RLock()
+	calls = mock.calls.RetrieveItem
+	mock.lockRetrieveItem.RUnlock()
+	return calls
+}
+
+// RetrievePrivateKey calls RetrievePrivateKeyFunc.
+func (mock *X509StoreAPI) RetrievePrivateKey(vaultID int, password string) (crypto.Signer, error) {
+	if mock.RetrievePrivateKeyFunc == nil {
+		panic("X509StoreAPI.RetrievePrivateKeyFunc: method is nil but X509StoreAPI.RetrievePrivateKey was just called")
+	}
+	callInfo := struct {
+		VaultID  int
+		Password string
+	}{
+		VaultID:  vaultID,
+		Password: password,
+	}
+	mock.lockRetrievePrivateKey.Lock()
+	mock.calls.RetrievePrivateKey = append(mock.calls.RetrievePrivateKey, callInfo)
+	mock.lockRetrievePrivateKey.Unlock()
+	return mock.RetrievePrivateKeyFunc(vaultID, password)
+}
+
+// RetrievePrivateKeyCalls gets all the calls that were made to RetrievePrivateKey.
+// Check the length with:
+//
+//	len(mockedX509StoreAPI.RetrievePrivateKeyCalls())
+func (mock *X509StoreAPI) RetrievePrivateKeyCalls() []struct {
+	VaultID  int
+	Password string
+} {
+	var calls []struct {
+		VaultID  int
+		Password string
+	}
+	mock.lockRetrievePrivateKey.RLock()
+	calls = mock.calls.RetrievePrivateKey
+	mock.lockRetrievePrivateKey.RUnlock()
+	return calls
+}
@@ -0,0 +1,304 @@
+package venafi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PermissionAudit is the rights every principal has on every object in a
+// subtree, as returned by AuditPermissions.
+type PermissionAudit struct {
+	RootDN    string
+	AuditedAt time.Time
+	Entries   []PermissionAuditEntry
+}
+
+// PermissionAuditEntry is one principal's rights on one object. Via lists the
+// groups the principal belongs to that have an entry on the object or one of
+// its ancestors; it is empty when all of the principal's rights there are its
+// own. PrivateKeyRead is set when Effective allows reading private keys.
+type PermissionAuditEntry struct {
+	ObjectDN       string
+	Class          string
+	Principal      Identity
+	Via            []string `json:",omitempty"`
+	Explicit       Permissions
+	Effective      Permissions
+	PrivateKeyRead bool
+}
+
+// AuditPermissions walks the subtree at rootDN and reports the effective
+// rights of every principal with explicit permissions on the subtree or on the
+// folders above it. Groups are expanded so that each member user is reported
+// too. Only principals with some right on an object get an entry for it.
+// Entries are sorted by object DN and principal name.
+//
+// Effective is called once for each object and each principal that has an
+// explicit entry on the object or one of its ancestors, directly or through a
+// group, so the number of calls grows with the size of the subtree times the
+// number of such principals.
+func AuditPermissions(permissions PermissionsAPI, config ConfigAPI, identity IdentityAPI, rootDN string) (*PermissionAudit, error) {
+	rootDN = strings.TrimSuffix(rootDN, `\`)
+
+	root, err := config.IsValid(rootDN, "")
+	if err != nil {
+		return nil, err
+	}
+	children, err := config.Enumerate(rootDN, true, "")
+	if err != nil {
+		return nil, err
+	}
+	objects := append([]ConfigObject{*root}, children...)
+	sort.Slice(objects, func(i, j int) bool {
+		return strings.ToLower(objects[i].DN) < strings.ToLower(objects[j].DN)
+	})
+
+	// explicit maps an object DN and principal to the rights set on that
+	// object directly.
+	explicit := make(map[string]map[string]Permissions)
+	principals := make(map[string]*Identity)
+
+	scan := func(dn string) error {
+		ids, err := permissions.List(dn)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			perms, err := permissions.Get(dn, &id)
+			if err != nil {
+				return err
+			}
+			key := strings.ToLower(id.PrefixedUniversal)
+			if explicit[dn] == nil {
+				explicit[dn] = make(map[string]Permissions)
+			}
+			explicit[dn][key] = perms.Explicit
+			principals[key] = &Identity{PrefixedUniversal: id.PrefixedUniversal}
+		}
+		return nil
+	}
+
+	for dn := parentDN(rootDN); dn != ""; dn = parentDN(dn) {
+		if err := scan(dn); err != nil {
+			return nil, err
+		}
+	}
+	for _, obj := range objects {
+		if err := scan(obj.DN); err != nil {
+			return nil, err
+		}
+	}
+
+	members, err := expandPrincipals(identity, principals)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(principals))
+	for key := range principals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.ToLower(principalName(principals[keys[i]])) < strings.ToLower(principalName(principals[keys[j]]))
+	})
+
+	audit := &PermissionAudit{RootDN: rootDN, AuditedAt: time.Now().UTC(), Entries: make([]PermissionAuditEntry, 0)}
+	for _, obj := range objects {
+		reachable := reachablePrincipals(obj.DN, explicit, members)
+		for _, key := range keys {
+			groups, ok := reachable[key]
+			if !ok {
+				continue
+			}
+			p := principals[key]
+			effective, err := permissions.Effective(obj.DN, p)
+			if err != nil {
+				return nil, err
+			}
+			if *effective == (Permissions{}) {
+				continue
+			}
+			audit.Entries = append(audit.Entries, PermissionAuditEntry{
+				ObjectDN:       obj.DN,
+				Class:          obj.Class,
+				Principal:      *p,
+				Via:            principalNames(principals, groups),
+				Explicit:       explicit[obj.DN][key],
+				Effective:      *effective,
+				PrivateKeyRead: effective.PrivateKeyRead,
+			})
+		}
+	}
+
+	return audit, nil
+}
+
+// reachablePrincipals returns the keys of the principals with an explicit
+// entry on dn or one of its ancestors, together with the members of any such
+// group. Only these can have rights on dn. Each key maps to the keys of the
+// groups with an entry there that the principal belongs to.
+func reachablePrincipals(dn string, explicit map[string]map[string]Permissions, members map[string][]string) map[string][]string {
+	rv := make(map[string][]string)
+	for ; dn != ""; dn = parentDN(dn) {
+		for key := range explicit[dn] {
+			if _, ok := rv[key]; !ok {
+				rv[key] = nil
+			}
+			for _, member := range members[key] {
+				if !containsFold(rv[member], key) {
+					rv[member] = append(rv[member], key)
+				}
+			}
+		}
+	}
+	return rv
+}
+
+// principalNames returns the sorted names of the principals with the given
+// keys.
+func principalNames(principals map[string]*Identity, keys []string) []string {
+	if len(keys) == 0 {
+		return nil
+	}
+	rv := make([]string, len(keys))
+	for i, key := range keys {
+		rv[i] = principalName(principals[key])
+	}
+	sort.Strings(rv)
+	return rv
+}
+
+// expandPrincipals fills in the details of each principal and adds the
+// members of every group, nested groups included. It returns the keys of each
+// group's members, by group key.
+func expandPrincipals(identity IdentityAPI, principals map[string]*Identity) (map[string][]string, error) {
+	groups := make([]*Identity, 0)
+	for _, p := range principals {
+		id, err := identity.Validate(&Identity{PrefixedUniversal: p.PrefixedUniversal})
+		if err != nil {
+			// An entry for a deleted identity is still worth reporting.
+			if isMissingIdentity(err) {
+				continue
+			}
+			return nil, err
+		}
+		*p = *id
+		if id.IsGroup {
+			groups = append(groups, p)
+		}
+	}
+
+	rv := make(map[string][]string, len(groups))
+	for _, group := range groups {
+		members, err := identity.GetMembers(group, true)
+		if err != nil {
+			return nil, err
+		}
+		groupKey := strings.ToLower(group.PrefixedUniversal)
+		for _, member := range members {
+			key := strings.ToLower(member.PrefixedUniversal)
+			rv[groupKey] = append(rv[groupKey], key)
+			if _, ok := principals[key]; !ok {
+				member := member
+				principals[key] = &member
+			}
+		}
+	}
+	return rv, nil
+}
+
+// PrivateKeyReaders returns the entries whose principal can read private
+// keys.
+func (a *PermissionAudit) PrivateKeyReaders() []PermissionAuditEntry {
+	rv := make([]PermissionAuditEntry, 0)
+	for _, e := range a.Entries {
+		if e.PrivateKeyRead {
+			rv = append(rv, e)
+		}
+	}
+	return rv
+}
+
+// WriteJSON writes the audit as indented JSON.
+func (a *PermissionAudit) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// WriteCSV writes the audit as CSV with one row per entry and one column per
+// right. A right column holds "explicit" if the right is set on the object
+// itself, "inherited" if it comes from elsewhere, and is empty otherwise.
+func (a *PermissionAudit) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"Object DN", "Class", "Principal", "Principal ID", "Group", "Via"}
+	for _, f := range (Permissions{}).flags() {
+		header = append(header, f.name)
+	}
+	header = append(header, "Flag")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, e := range a.Entries {
+		row := []string{
+			e.ObjectDN, e.Class, principalName(&e.Principal), e.Principal.PrefixedUniversal,
+			boolString(e.Principal.IsGroup), strings.Join(e.Via, ";"),
+		}
+		explicit := e.Explicit.flags()
+		for i, f := range e.Effective.flags() {
+			switch {
+			case explicit[i].set:
+				row = append(row, "explicit")
+			case f.set:
+				row = append(row, "inherited")
+			default:
+				row = append(row, "")
+			}
+		}
+		flag := ""
+		if e.PrivateKeyRead {
+			flag = "private key read"
+		}
+		row = append(row, flag)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// principalName returns the most readable name known for id.
+func principalName(id *Identity) string {
+	if id.PrefixedName != "" {
+		return id.PrefixedName
+	}
+	return id.PrefixedUniversal
+}
+
+// parentDN returns the DN of the folder containing dn, or "" for a root.
+func parentDN(dn string) string {
+	i := strings.LastIndex(dn, `\`)
+	if i <= 0 {
+		return ""
+	}
+	return dn[:i]
+}
+
+func boolString(b bool) string {
+	if b {
+		return "yes"
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package venafi_test
+
+import (
+	"strings"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+)
+
+// countingPermissions counts the Effective calls made through it.
+type countingPermissions struct {
+	venafi.PermissionsAPI
+	effective map[string]int
+}
+
+func (p *countingPermissions) Effective(objectDN string, principal *venafi.Identity) (*venafi.Permissions, error) {
+	p.effective[objectDN+"|"+principal.PrefixedName]++
+	return p.PermissionsAPI.Effective(objectDN, principal)
+}
+
+func TestAuditPermissions(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Prod`, "Policy", nil)
+	srv.AddObject(`\VED\Policy\Prod\Web`, "Policy", nil)
+	srv.AddObject(`\VED\Policy\Prod\Web\web01`, "X509 Certificate", nil)
+	srv.AddObject(`\VED\Policy\Prod\Db`, "Policy", nil)
+
+	alice := srv.AddUser("alice", nil)
+	bob := srv.AddUser("bob", nil)
+	carol := srv.AddUser("carol", nil)
+	custodians := srv.AddGroup("Key Custodians", bob)
+	srv.SetPermissions(`\VED\Policy\Prod`, alice, venafi.RoleViewer)
+	srv.SetPermissions(`\VED\Policy\Prod\Web`, custodians, venafi.RoleKeyCustodian)
+	srv.SetPermissions(`\VED\Policy\Prod\Db`, carol, venafi.RoleOperator)
+
+	perms := &countingPermissions{PermissionsAPI: v.Permissions, effective: make(map[string]int)}
+	audit, err := venafi.AuditPermissions(perms, v.Config, v.Identity, `\VED\Policy\Prod`)
+	if err != nil {
+		t.Fatalf("AuditPermissions: %v", err)
+	}
+
+	got := make(map[string]venafi.PermissionAuditEntry)
+	for _, e := range audit.Entries {
+		got[e.ObjectDN+"|"+e.Principal.PrefixedName] = e
+	}
+	for _, key := range []string{
+		`\VED\Policy\Prod|local:alice`,
+		`\VED\Policy\Prod\Web\web01|local:alice`,
+		`\VED\Policy\Prod\Web\web01|local:bob`,
+		`\VED\Policy\Prod\Db|local:carol`,
+	} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("no audit entry for %s", key)
+		}
+	}
+	if e := got[`\VED\Policy\Prod\Web\web01|local:bob`]; !e.PrivateKeyRead || len(e.Via) != 1 {
+		t.Errorf("bob on web01 = %+v, want private key read via Key Custodians", e)
+	}
+	if readers := audit.PrivateKeyReaders(); len(readers) == 0 {
+		t.Error("PrivateKeyReaders is empty")
+	}
+
+	// Principals without an entry on an object or its ancestors cannot have
+	// rights there, so they are not asked about.
+	for key := range perms.effective {
+		if (strings.HasPrefix(key, `\VED\Policy\Prod\Web`) && strings.HasSuffix(key, "|local:carol")) ||
+			(strings.HasPrefix(key, `\VED\Policy\Prod\Db|`) && strings.HasSuffix(key, "|local:bob")) {
+			t.Errorf("Effective called for %s", key)
+		}
+	}
+}
+
+func TestAuditViaIsPerObject(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Prod`, "Policy", nil)
+	srv.AddObject(`\VED\Policy\Prod\Web`, "Policy", nil)
+	srv.AddObject(`\VED\Policy\Prod\Db`, "Policy", nil)
+
+	// alice has rights of her own on Prod and is also in a group that only
+	// has rights on Web.
+	alice := srv.AddUser("alice", nil)
+	custodians := srv.AddGroup("Key Custodians", alice)
+	srv.SetPermissions(`\VED\Policy\Prod`, alice, venafi.RoleViewer)
+	srv.SetPermissions(`\VED\Policy\Prod\Web`, custodians, venafi.RoleKeyCustodian)
+
+	audit, err := venafi.AuditPermissions(v.Permissions, v.Config, v.Identity, `\VED\Policy\Prod`)
+	if err != nil {
+		t.Fatalf("AuditPermissions: %v", err)
+	}
+
+	via := make(map[string]string)
+	for _, e := range audit.Entries {
+		if e.Principal.PrefixedName == alice.PrefixedName {
+			via[e.ObjectDN] = strings.Join(e.Via, ",")
+		}
+	}
+	want := map[string]string{
+		`\VED\Policy\Prod`:     "",
+		`\VED\Policy\Prod\Db`:  "",
+		`\VED\Policy\Prod\Web`: "local:Key Custodians",
+	}
+	for dn, w := range want {
+		if got, ok := via[dn]; !ok || got != w {
+			t.Errorf("alice on %s: via %q (reported %v), want %q", dn, got, ok, w)
+		}
+	}
+}
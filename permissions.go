@@ -0,0 +1,253 @@
+package venafi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Permissions is the set of rights a principal has on an object. PolicyWrite
+// is the right to change policy values on a folder, and Admin the right to
+// manage the object's permissions.
+type Permissions struct {
+	View            bool `json:"IsViewAllowed"`
+	Read            bool `json:"IsReadAllowed"`
+	Write           bool `json:"IsWriteAllowed"`
+	Create          bool `json:"IsCreateAllowed"`
+	Delete          bool `json:"IsDeleteAllowed"`
+	Rename          bool `json:"IsRenameAllowed"`
+	Revoke          bool `json:"IsRevokeAllowed"`
+	PrivateKeyRead  bool `json:"IsPrivateKeyReadAllowed"`
+	PrivateKeyWrite bool `json:"IsPrivateKeyWriteAllowed"`
+	Associate       bool `json:"IsAssociateAllowed"`
+	PolicyWrite     bool `json:"IsPolicyWriteAllowed"`
+	Admin           bool `json:"IsManagePermissionsAllowed"`
+}
+
+// Role templates for Grant.
+var (
+	RoleViewer       = Permissions{View: true, Read: true}
+	RoleOperator     = Permissions{View: true, Read: true, Write: true, Create: true, Delete: true, Rename: true, Revoke: true, Associate: true}
+	RoleKeyCustodian = Permissions{View: true, Read: true, PrivateKeyRead: true, PrivateKeyWrite: true}
+	RoleAdmin        = Permissions{View: true, Read: true, Write: true, Create: true, Delete: true, Rename: true, Revoke: true,
+		PrivateKeyRead: true, PrivateKeyWrite: true, Associate: true, PolicyWrite: true, Admin: true}
+)
+
+// Union returns the rights in either p or other.
+func (p Permissions) Union(other Permissions) Permissions {
+	return Permissions{
+		View:            p.View || other.View,
+		Read:            p.Read || other.Read,
+		Write:           p.Write || other.Write,
+		Create:          p.Create || other.Create,
+		Delete:          p.Delete || other.Delete,
+		Rename:          p.Rename || other.Rename,
+		Revoke:          p.Revoke || other.Revoke,
+		PrivateKeyRead:  p.PrivateKeyRead || other.PrivateKeyRead,
+		PrivateKeyWrite: p.PrivateKeyWrite || other.PrivateKeyWrite,
+		Associate:       p.Associate || other.Associate,
+		PolicyWrite:     p.PolicyWrite || other.PolicyWrite,
+		Admin:           p.Admin || other.Admin,
+	}
+}
+
+// Contains reports whether p includes every right in other.
+func (p Permissions) Contains(other Permissions) bool {
+	return p.Union(other) == p
+}
+
+// Names returns the names of the rights in p, in field order.
+func (p Permissions) Names() []string {
+	flags := p.flags()
+	rv := make([]string, 0, len(flags))
+	for _, f := range flags {
+		if f.set {
+			rv = append(rv, f.name)
+		}
+	}
+	return rv
+}
+
+type permissionFlag struct {
+	name string
+	set  bool
+}
+
+func (p Permissions) flags() []permissionFlag {
+	return []permissionFlag{
+		{"View", p.View}, {"Read", p.Read}, {"Write", p.Write}, {"Create", p.Create}, {"Delete", p.Delete},
+		{"Rename", p.Rename}, {"Revoke", p.Revoke}, {"PrivateKeyRead", p.PrivateKeyRead},
+		{"PrivateKeyWrite", p.PrivateKeyWrite}, {"Associate", p.Associate}, {"PolicyWrite", p.PolicyWrite},
+		{"Admin", p.Admin},
+	}
+}
+
+func (p Permissions) String() string {
+	return strings.Join(p.Names(), ",")
+}
+
+// ObjectPermissions holds a principal's rights on an object. Explicit rights
+// are set on the object itself; implicit rights come from parent folders and
+// group memberships.
+type ObjectPermissions struct {
+	Explicit Permissions
+	Implicit Permissions
+}
+
+type PermissionsService struct {
+	client *Client
+}
+
+// List returns the principals with explicit permissions on an object. Only
+// PrefixedUniversal is set on each.
+func (s *PermissionsService) List(objectDN string) ([]Identity, error) {
+	path, err := s.objectPath(objectDN)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []string
+
+	_, err = s.client.doJsonRequestWithBody("GET", path, nil, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := make([]Identity, len(output))
+	for i, principal := range output {
+		rv[i] = Identity{PrefixedUniversal: principal}
+	}
+	return rv, nil
+}
+
+// Get returns the explicit and implicit permissions principal has on an
+// object.
+func (s *PermissionsService) Get(objectDN string, principal *Identity) (*ObjectPermissions, error) {
+	type Output struct {
+		ExplicitPermissions *Permissions
+		ImplicitPermissions *Permissions
+	}
+
+	path, err := s.principalPath(objectDN, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	var output Output
+
+	_, err = s.client.doJsonRequestWithBody("GET", path, nil, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := &ObjectPermissions{}
+	if output.ExplicitPermissions != nil {
+		rv.Explicit = *output.ExplicitPermissions
+	}
+	if output.ImplicitPermissions != nil {
+		rv.Implicit = *output.ImplicitPermissions
+	}
+	return rv, nil
+}
+
+// Effective returns the rights principal actually has on an object, combining
+// explicit and implicit permissions.
+func (s *PermissionsService) Effective(objectDN string, principal *Identity) (*Permissions, error) {
+	type Output struct {
+		EffectivePermissions *Permissions
+	}
+
+	path, err := s.principalPath(objectDN, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	var output Output
+
+	_, err = s.client.doJsonRequestWithBody("GET", path+"/Effective", nil, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	if output.EffectivePermissions == nil {
+		return &Permissions{}, nil
+	}
+	return output.EffectivePermissions, nil
+}
+
+// Set replaces the explicit permissions principal has on an object.
+func (s *PermissionsService) Set(objectDN string, principal *Identity, perms Permissions) error {
+	path, err := s.principalPath(objectDN, principal)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.doJsonRequestWithBody("POST", path, perms, nil)
+	if errors.Is(err, ErrAlreadyExists) {
+		_, err = s.client.doJsonRequestWithBody("PUT", path, perms, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Remove deletes the explicit permissions principal has on an object.
+func (s *PermissionsService) Remove(objectDN string, principal *Identity) error {
+	path, err := s.principalPath(objectDN, principal)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.doJsonRequestWithBody("DELETE", path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Grant adds the rights in role, such as RoleViewer, to the explicit
+// permissions principal has on an object. Rights it already has are kept.
+func (s *PermissionsService) Grant(objectDN string, principal *Identity, role Permissions) error {
+	current, err := s.Get(objectDN, principal)
+	if err != nil {
+		return err
+	}
+
+	if current.Explicit.Contains(role) {
+		return nil
+	}
+
+	return s.Set(objectDN, principal, current.Explicit.Union(role))
+}
+
+func (s *PermissionsService) objectPath(objectDN string) (string, error) {
+	guid, err := s.client.Config.DnToGuid(objectDN)
+	if err != nil {
+		return "", err
+	}
+	return "/vedsdk/Permissions/Object/" + url.PathEscape(guid), nil
+}
+
+// principalPath returns the endpoint for principal's permissions on an
+// object. "local:{guid}" becomes ".../local/{guid}" and
+// "AD+corp:{guid}" becomes ".../AD/corp/{guid}".
+func (s *PermissionsService) principalPath(objectDN string, principal *Identity) (string, error) {
+	prefix, universal := splitPrefixed(principal.PrefixedUniversal)
+	if prefix == "" {
+		return "", fmt.Errorf("principal %q has no prefixed universal ID", principal.PrefixedUniversal)
+	}
+
+	path, err := s.objectPath(objectDN)
+	if err != nil {
+		return "", err
+	}
+
+	for _, part := range strings.SplitN(prefix, "+", 2) {
+		path += "/" + url.PathEscape(part)
+	}
+	return path + "/" + url.PathEscape(universal), nil
+}
@@ -0,0 +1,103 @@
+package venafi_test
+
+import (
+	"errors"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+func TestPermissions(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Web\web01`, config.ClassX509Certificate, nil)
+	alice := srv.AddUser("alice", nil)
+	bob := srv.AddUser("bob", nil)
+	ops := srv.AddGroup("Ops", bob)
+
+	if err := v.Permissions.Set(`\VED\Policy\Web`, &alice, venafi.RoleViewer); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := v.Permissions.Set(`\VED\Policy\Web`, &ops, venafi.Permissions{View: true, Write: true}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	principals, err := v.Permissions.List(`\VED\Policy\Web`)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(principals) != 2 {
+		t.Errorf("List = %+v, want alice and Ops", principals)
+	}
+
+	// Rights set on the folder are implicit on the certificate below it.
+	perms, err := v.Permissions.Get(`\VED\Policy\Web\web01`, &alice)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if perms.Explicit != (venafi.Permissions{}) || perms.Implicit != venafi.RoleViewer {
+		t.Errorf("alice on web01 = %+v, want viewer rights inherited", perms)
+	}
+
+	// Set replaces existing explicit rights.
+	if err := v.Permissions.Set(`\VED\Policy\Web`, &alice, venafi.Permissions{View: true}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if perms, err := v.Permissions.Get(`\VED\Policy\Web`, &alice); err != nil || perms.Explicit != (venafi.Permissions{View: true}) {
+		t.Errorf("alice on Web after Set = %+v, %v, want view only", perms, err)
+	}
+
+	// Effective rights include those granted to the principal's groups.
+	effective, err := v.Permissions.Effective(`\VED\Policy\Web\web01`, &bob)
+	if err != nil {
+		t.Fatalf("Effective: %v", err)
+	}
+	if !effective.View || !effective.Write || effective.Read {
+		t.Errorf("bob's effective rights on web01 = %s, want View,Write from Ops", effective)
+	}
+
+	if err := v.Permissions.Remove(`\VED\Policy\Web`, &alice); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if perms, err := v.Permissions.Get(`\VED\Policy\Web`, &alice); err != nil || perms.Explicit != (venafi.Permissions{}) {
+		t.Errorf("alice on Web after Remove = %+v, %v, want no explicit rights", perms, err)
+	}
+	if principals, _ := v.Permissions.List(`\VED\Policy\Web`); len(principals) != 1 {
+		t.Errorf("List after Remove = %+v, want only Ops", principals)
+	}
+
+	if _, err := v.Permissions.List(`\VED\Policy\Missing`); !errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("List of a missing object: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestGrantKeepsExistingRights(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	alice := srv.AddUser("alice", nil)
+	bob := srv.AddUser("bob", nil)
+
+	// Neither role template includes PolicyWrite or PrivateKeyRead.
+	existing := venafi.Permissions{View: true, PolicyWrite: true, PrivateKeyRead: true}
+	srv.SetPermissions(`\VED\Policy\Web`, alice, existing)
+
+	if err := v.Permissions.Grant(`\VED\Policy\Web`, &alice, venafi.RoleOperator); err != nil {
+		t.Fatalf("Grant: %v", err)
+	}
+	perms, err := v.Permissions.Get(`\VED\Policy\Web`, &alice)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if want := venafi.RoleOperator.Union(existing); perms.Explicit != want {
+		t.Errorf("alice after Grant = %s, want %s", perms.Explicit, want)
+	}
+
+	// Granting to a principal without explicit rights creates them.
+	if err := v.Permissions.Grant(`\VED\Policy\Web`, &bob, venafi.RoleViewer); err != nil {
+		t.Fatalf("Grant: %v", err)
+	}
+	if perms, err := v.Permissions.Get(`\VED\Policy\Web`, &bob); err != nil || perms.Explicit != venafi.RoleViewer {
+		t.Errorf("bob after Grant = %+v, %v, want viewer rights", perms, err)
+	}
+}
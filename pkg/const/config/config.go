@@ -6,6 +6,22 @@ const (
 	ClassPolicy          = "Policy"
 	ClassOpenSSLCA       = "OpenSSL CA"
 	ClassSelfSignedCA    = "Self Signed CA"
+	ClassDevice          = "Device"
+)
+
+// AttributeSyntax is the syntax of a schema attribute, which determines how
+// its values are encoded as strings.
+type AttributeSyntax int
+
+//noinspection GoUnusedConst
+const (
+	SyntaxString    AttributeSyntax = 1
+	SyntaxDN        AttributeSyntax = 2
+	SyntaxBoolean   AttributeSyntax = 3
+	SyntaxInteger   AttributeSyntax = 4
+	SyntaxTime      AttributeSyntax = 5
+	SyntaxPassword  AttributeSyntax = 6
+	SyntaxMultiLine AttributeSyntax = 7
 )
 
 //noinspection GoUnusedConst
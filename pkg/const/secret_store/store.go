@@ -1,11 +1,53 @@
 package secret_store
 
+import (
+	"strconv"
+	"strings"
+)
+
+// ProtectionKey names a key that encrypts Secret Store entries, in
+// "Provider:Name" form. Software keys are held by TPP itself; other providers,
+// such as "HSM", are hardware security modules.
+type ProtectionKey string
+
+//noinspection GoUnusedConst
+const (
+	ProtectionKeyDefault ProtectionKey = "Software:Default"
+	ProtectionKeyNull    ProtectionKey = "Null:Null"
+)
+
 //noinspection GoUnusedConst
 const (
-	ProtectionKeyDefault = "Software:Default"
-	ProtectionKeyNull    = "Null:Null"
+	ProviderSoftware = "Software"
+	ProviderHSM      = "HSM"
+	ProviderNull     = "Null"
 )
 
+// NewProtectionKey returns the key called name from provider.
+func NewProtectionKey(provider string, name string) ProtectionKey {
+	return ProtectionKey(provider + ":" + name)
+}
+
+// Provider returns the part of k before the colon.
+func (k ProtectionKey) Provider() string {
+	if i := strings.Index(string(k), ":"); i >= 0 {
+		return string(k)[:i]
+	}
+	return ""
+}
+
+// Name returns the part of k after the colon.
+func (k ProtectionKey) Name() string {
+	return string(k)[strings.Index(string(k), ":")+1:]
+}
+
+// IsHSM reports whether k is held outside TPP, in a hardware security
+// module.
+func (k ProtectionKey) IsHSM() bool {
+	provider := k.Provider()
+	return provider != "" && !strings.EqualFold(provider, ProviderSoftware) && !strings.EqualFold(provider, ProviderNull)
+}
+
 type SecretStoreResult int
 
 //go:generate stringer -type=SecretStoreResult
@@ -38,3 +80,55 @@ const (
 )
 
 
+
+// NamespaceConfig is the Secret Store namespace for vault entries owned by
+// Config objects.
+const NamespaceConfig = "config"
+
+// VaultType identifies the kind of data held in a Secret Store vault entry.
+// Archived entries have the type of the live entry with the low bit set.
+type VaultType int
+
+//noinspection GoUnusedConst
+const (
+	VaultTypeCSR         VaultType = 2
+	VaultTypeCertificate VaultType = 4
+	VaultTypePKCS12      VaultType = 8
+	VaultTypePassword    VaultType = 32
+	VaultTypeBlob        VaultType = 64
+	VaultTypePrivateKey  VaultType = 256
+)
+
+// Archived reports whether t is the type of an archived entry.
+func (t VaultType) Archived() bool {
+	return t&1 != 0
+}
+
+// Live returns t without the archived bit.
+func (t VaultType) Live() VaultType {
+	return t &^ 1
+}
+
+func (t VaultType) String() string {
+	var name string
+	switch t.Live() {
+	case VaultTypeCSR:
+		name = "CSR"
+	case VaultTypeCertificate:
+		name = "Certificate"
+	case VaultTypePKCS12:
+		name = "PKCS12"
+	case VaultTypePassword:
+		name = "Password"
+	case VaultTypeBlob:
+		name = "Blob"
+	case VaultTypePrivateKey:
+		name = "PrivateKey"
+	default:
+		return "VaultType(" + strconv.Itoa(int(t)) + ")"
+	}
+	if t.Archived() {
+		return "Archived" + name
+	}
+	return name
+}
@@ -63,6 +63,26 @@ func internalDecodeBlock(pemBytes []byte) (*pemlib.Block, []byte, error) {
 	return block, remainder, nil
 }
 
+// internalDecryptBlock decrypts block in place if it is encrypted. Unencrypted
+// blocks are left alone whether or not a password is given.
+func internalDecryptBlock(block *pemlib.Block, password string) error {
+	if !x509.IsEncryptedPEMBlock(block) {
+		return nil
+	}
+	if password == "" {
+		return fmt.Errorf("PEM block is encrypted but no password was given")
+	}
+
+	der, err := x509.DecryptPEMBlock(block, []byte(password))
+	if err != nil {
+		return fmt.Errorf("error decrypting PEM block")
+	}
+	block.Bytes = der
+	delete(block.Headers, "Proc-Type")
+	delete(block.Headers, "DEK-Info")
+	return nil
+}
+
 func internalParseCert(block *pemlib.Block) (*x509.Certificate, error) {
 	if block.Type != CertificateBlockType {
 		return nil, fmt.Errorf("expecting a block of type %s, got %s", CertificateBlockType, block.Type)
@@ -129,11 +149,8 @@ func DecodeCertAndPrivateKey(certBytes []byte, password string) (*x509.Certifica
 		return nil, nil, err
 	}
 
-	if password != "" {
-		block.Bytes, err = x509.DecryptPEMBlock(block, []byte(password))
-		if err != nil {
-			return nil, nil, fmt.Errorf("error decrypting PEM block")
-		}
+	if err := internalDecryptBlock(block, password); err != nil {
+		return nil, nil, err
 	}
 
 	pk, err := internalParseKey(block)
@@ -143,3 +160,40 @@ func DecodeCertAndPrivateKey(certBytes []byte, password string) (*x509.Certifica
 
 	return cert, pk, nil
 }
+
+// EncodePKCS8Key encodes pk as an unencrypted PKCS#8 block.
+func EncodePKCS8Key(pk crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(pk)
+	if err != nil {
+		return "", err
+	}
+
+	var pemBuf bytes.Buffer
+	err = pemlib.Encode(&pemBuf, &pemlib.Block{Type: PKCS8KeyBlockType, Bytes: der})
+	return pemBuf.String(), err
+}
+
+// DecodePrivateKeyWithPassword is like DecodePrivateKey but first decrypts the
+// block with password if it is encrypted.
+func DecodePrivateKeyWithPassword(keyText string, password string) (crypto.Signer, error) {
+	block, _, err := internalDecodeBlock([]byte(keyText))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := internalDecryptBlock(block, password); err != nil {
+		return nil, err
+	}
+
+	return internalParseKey(block)
+}
+
+// ParsePrivateKeyDER parses a DER-encoded PKCS#8, PKCS#1 or SEC 1 private key.
+func ParsePrivateKeyDER(der []byte) (crypto.Signer, error) {
+	for _, blockType := range []string{PKCS8KeyBlockType, RSAKeyBlockType, ECDSAKeyBlockType} {
+		if key, err := internalParseKey(&pemlib.Block{Type: blockType, Bytes: der}); err == nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("private key is not a valid format")
+}
@@ -0,0 +1,64 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Mask is the text substituted for any redacted value.
+const Mask = "********"
+
+// Fields lists the JSON body fields whose values are masked.
+var Fields = []string{
+	"Password",
+	"APIKey",
+	"PrivateKeyData",
+	"CertificateData",
+	"Base64Data",
+}
+
+// Headers lists the HTTP headers whose values are masked.
+var Headers = []string{
+	"X-Venafi-Api-Key",
+	"Authorization",
+}
+
+var (
+	fieldPattern  = regexp.MustCompile(`("(` + alternation(Fields) + `)"\s*:\s*)"((?:[^"\\]|\\.)*)"`)
+	headerPattern = regexp.MustCompile(`(?mi)^((?:` + alternation(Headers) + `):[ \t]*)[^\r\n]*`)
+)
+
+// String returns a copy of text with the values of any sensitive JSON fields
+// and HTTP headers replaced by Mask. It is intended for request and response
+// dumps, so the surrounding structure of the text is left intact.
+func String(text string) string {
+	text = ReplaceFields(text, func(name string, value string) string {
+		return Mask
+	})
+	text = headerPattern.ReplaceAllString(text, `${1}`+Mask)
+	return text
+}
+
+// ReplaceFields returns a copy of text with the value of each sensitive JSON
+// field replaced by the result of repl, which is given the field name and its
+// value as it appears in text, still JSON-escaped. The result must be escaped
+// the same way. Headers are left alone.
+func ReplaceFields(text string, repl func(name string, value string) string) string {
+	return fieldPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := fieldPattern.FindStringSubmatch(match)
+		return m[1] + `"` + repl(m[2], m[3]) + `"`
+	})
+}
+
+// Bytes is the []byte equivalent of String.
+func Bytes(b []byte) []byte {
+	return []byte(String(string(b)))
+}
+
+func alternation(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return strings.Join(quoted, "|")
+}
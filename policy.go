@@ -7,14 +7,12 @@ type PolicyService struct {
 }
 
 func (s *PolicyService) Create(objectDN string) (*ConfigObject, error) {
-	me, err := s.client.Identity.Self()
+	attrs, err := policyFolderAttributes(s.client.Identity)
 	if err != nil {
 		return nil, err
 	}
 
-	obj, err := s.client.Config.Create(objectDN, config.ClassPolicy, map[string]string{
-		"Contact": me.PrefixedUniversal,
-	})
+	obj, err := s.client.Config.Create(objectDN, config.ClassPolicy, attrs)
 	if err != nil {
 		return nil, err
 	}
@@ -29,3 +27,16 @@ func (s *PolicyService) Delete(objectDN string, recursive bool) error {
 func (s *PolicyService) Exists(objectDN string) bool {
 	return s.client.Config.Exists(objectDN)
 }
+
+// policyFolderAttributes returns the attributes a new policy folder is created
+// with.
+func policyFolderAttributes(identity IdentityAPI) (map[string]string, error) {
+	me, err := identity.Self()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"Contact": me.PrefixedUniversal,
+	}, nil
+}
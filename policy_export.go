@@ -0,0 +1,270 @@
+package venafi
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	tppconfig "github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+// PolicyExportVersion is the format version written by ExportPolicy.
+const PolicyExportVersion = 1
+
+// PolicyExport is a portable snapshot of a policy subtree. DNs are stored
+// relative to RootDN so the tree can be restored under a different root.
+type PolicyExport struct {
+	Version    int
+	RootDN     string
+	ExportedAt time.Time
+	Objects    []ExportedObject
+}
+
+// ExportedObject is one object in a PolicyExport. RelativeDN is empty for the
+// root itself and otherwise starts with a backslash.
+type ExportedObject struct {
+	RelativeDN string
+	Class      string
+	Attributes map[string][]string `json:",omitempty"`
+	Policies   []ExportedPolicy    `json:",omitempty"`
+}
+
+// ExportedPolicy is a policy value set on an exported folder.
+type ExportedPolicy struct {
+	Class     string
+	Attribute string
+	Values    []string
+	Locked    bool `json:",omitempty"`
+}
+
+// ExportOptions controls what ExportPolicy captures. TPP has no call
+// to list every policy value on a folder, so PolicyAttributes names the
+// policy attributes to read, by class. Objects of a class in SkipClasses are
+// left out together with everything below them.
+type ExportOptions struct {
+	PolicyAttributes map[string][]string
+	SkipClasses      []string
+	SkipAttributes   []string
+}
+
+// DefaultExportOptions captures the common certificate policy attributes and
+// skips attributes that only make sense on the source instance.
+var DefaultExportOptions = ExportOptions{
+	PolicyAttributes: map[string][]string{
+		tppconfig.ClassX509Certificate: {
+			policyAttrContact, policyAttrApprover, policyAttrCATemplate,
+			"Key Algorithm", "Key Bit Strength", "Elliptic Curve", "Management Type",
+			"Organization", "Organizational Unit", "City", "State", "Country",
+			"Domain Suffix Whitelist", "Manual Csr", "Prohibit Wildcard",
+		},
+	},
+	SkipAttributes: []string{"Certificate Vault Id", "Revision"},
+}
+
+// WriteJSON writes the export as indented JSON.
+func (e *PolicyExport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// ReadPolicyExport reads an export written by WriteJSON.
+func ReadPolicyExport(r io.Reader) (*PolicyExport, error) {
+	var e PolicyExport
+	if err := json.NewDecoder(r).Decode(&e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ExportPolicy walks the subtree at rootDN through config and captures every
+// object's class and attributes, plus the policy values named in opts for each
+// policy folder. A nil opts uses DefaultExportOptions.
+func ExportPolicy(config ConfigAPI, rootDN string, opts *ExportOptions) (*PolicyExport, error) {
+	if opts == nil {
+		opts = &DefaultExportOptions
+	}
+	rootDN = strings.TrimSuffix(rootDN, `\`)
+
+	root, err := config.IsValid(rootDN, "")
+	if err != nil {
+		return nil, err
+	}
+	children, err := config.Enumerate(rootDN, true, "")
+	if err != nil {
+		return nil, err
+	}
+	objects := append([]ConfigObject{*root}, children...)
+	sortByDepth(objects)
+
+	export := &PolicyExport{
+		Version:    PolicyExportVersion,
+		RootDN:     rootDN,
+		ExportedAt: time.Now().UTC(),
+		Objects:    make([]ExportedObject, 0, len(objects)),
+	}
+
+	skipped := make([]string, 0)
+	for _, obj := range objects {
+		if isAtOrBelowAny(obj.DN, skipped) {
+			continue
+		}
+		if containsFold(opts.SkipClasses, obj.Class) {
+			skipped = append(skipped, obj.DN)
+			continue
+		}
+
+		attrs, err := config.ReadAll(obj.DN)
+		if err != nil {
+			return nil, err
+		}
+		for name := range attrs {
+			if containsFold(opts.SkipAttributes, name) {
+				delete(attrs, name)
+			}
+		}
+
+		exported := ExportedObject{
+			RelativeDN: obj.DN[len(rootDN):],
+			Class:      obj.Class,
+			Attributes: attrs,
+		}
+
+		if obj.Class == tppconfig.ClassPolicy {
+			for _, class := range sortedKeys(opts.PolicyAttributes) {
+				for _, name := range opts.PolicyAttributes[class] {
+					value, err := config.ReadPolicy(obj.DN, class, name)
+					if err != nil {
+						return nil, err
+					}
+					if len(value.Values) == 0 {
+						continue
+					}
+					exported.Policies = append(exported.Policies, ExportedPolicy{
+						Class: class, Attribute: name, Values: value.Values, Locked: value.Locked,
+					})
+				}
+			}
+		}
+
+		export.Objects = append(export.Objects, exported)
+	}
+
+	return export, nil
+}
+
+// Restore recreates the exported tree under rootDN through config. rootDN may
+// differ from the export's RootDN and may be on another TPP instance. Existing
+// objects are updated in place. Attribute and policy values that refer to DNs
+// under the original root are rewritten to point under the new one. If any
+// step fails, the changes already made are rolled back.
+func (e *PolicyExport) Restore(config ConfigAPI, rootDN string) error {
+	if rootDN == "" {
+		rootDN = e.RootDN
+	}
+	rootDN = strings.TrimSuffix(rootDN, `\`)
+
+	return RunConfigBatch(config, func(b *ConfigBatch) error {
+		return e.restore(config, b, rootDN)
+	})
+}
+
+func (e *PolicyExport) restore(config ConfigAPI, b *ConfigBatch, rootDN string) error {
+	remap := func(values []string) []string {
+		return remapDNs(values, e.RootDN, rootDN)
+	}
+
+	for _, obj := range e.Objects {
+		dn := rootDN + obj.RelativeDN
+
+		exists, err := objectExists(config, dn)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			initial := make(map[string]string)
+			for name, values := range obj.Attributes {
+				if len(values) > 0 {
+					initial[name] = remap(values)[0]
+				}
+			}
+			if _, err := b.Create(dn, obj.Class, initial); err != nil {
+				return err
+			}
+		}
+
+		if len(obj.Attributes) > 0 {
+			attrs := make(map[string][]string, len(obj.Attributes))
+			for name, values := range obj.Attributes {
+				attrs[name] = remap(values)
+			}
+			if err := b.Write(dn, attrs); err != nil {
+				return err
+			}
+		}
+
+		for _, p := range obj.Policies {
+			if err := b.WritePolicy(dn, p.Class, p.Attribute, remap(p.Values), p.Locked); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// sortByDepth orders objects parent-first, then by DN.
+func sortByDepth(objects []ConfigObject) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		di, dj := strings.Count(objects[i].DN, `\`), strings.Count(objects[j].DN, `\`)
+		if di != dj {
+			return di < dj
+		}
+		return strings.ToLower(objects[i].DN) < strings.ToLower(objects[j].DN)
+	})
+}
+
+// remapDNs replaces the oldRoot prefix of any DN values with newRoot.
+func remapDNs(values []string, oldRoot string, newRoot string) []string {
+	if strings.EqualFold(oldRoot, newRoot) {
+		return values
+	}
+	rv := make([]string, len(values))
+	for i, v := range values {
+		if isAtOrBelow(v, oldRoot) {
+			v = newRoot + v[len(oldRoot):]
+		}
+		rv[i] = v
+	}
+	return rv
+}
+
+// isAtOrBelow reports whether dn is root or a descendant of it.
+func isAtOrBelow(dn string, root string) bool {
+	return strings.EqualFold(dn, root) || strings.HasPrefix(strings.ToLower(dn), strings.ToLower(root)+`\`)
+}
+
+// isAtOrBelowAny reports whether dn is at or below any of roots.
+func isAtOrBelowAny(dn string, roots []string) bool {
+	for _, root := range roots {
+		if isAtOrBelow(dn, root) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
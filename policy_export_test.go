@@ -0,0 +1,123 @@
+package venafi_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+func TestExportRestoreRoundTrip(t *testing.T) {
+	source, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, map[string][]string{"Description": {"All teams"}})
+	srv.AddObject(`\VED\Policy\Teams\CA`, config.ClassSelfSignedCA, nil)
+	srv.AddObject(`\VED\Policy\Teams\Web`, config.ClassPolicy, map[string][]string{"Description": {"Web team"}})
+	srv.AddObject(`\VED\Policy\Teams\Web\web01`, config.ClassX509Certificate,
+		map[string][]string{"X509 Subject": {"CN=web01"}, "Revision": {"7"}})
+	policies := map[string][]string{
+		"Certificate Authority": {`\VED\Policy\Teams\CA`},
+		"Key Bit Strength":      {"2048"},
+	}
+	for name, values := range policies {
+		if err := source.Config.WritePolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, name, values, true); err != nil {
+			t.Fatalf("WritePolicy: %v", err)
+		}
+	}
+
+	export, err := venafi.ExportPolicy(source.Config, `\VED\Policy\Teams`, nil)
+	if err != nil {
+		t.Fatalf("ExportPolicy: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := export.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	export, err = venafi.ReadPolicyExport(&buf)
+	if err != nil {
+		t.Fatalf("ReadPolicyExport: %v", err)
+	}
+
+	// Restore on another instance under a different root.
+	target, targetSrv := newTestClient(t, nil)
+	if err := export.Restore(target.Config, `\VED\Policy\Restored`); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	attrs, ok := targetSrv.Object(`\VED\Policy\Restored\Web\web01`)
+	if !ok {
+		t.Fatal("web01 was not restored")
+	}
+	if got := attrs["X509 Subject"]; !reflect.DeepEqual(got, []string{"CN=web01"}) {
+		t.Errorf("X509 Subject = %q, want [CN=web01]", got)
+	}
+	if _, ok := attrs["Revision"]; ok {
+		t.Error("Revision was exported although it is skipped by default")
+	}
+	value, err := target.Config.ReadPolicy(`\VED\Policy\Restored\Web`, config.ClassX509Certificate, "Certificate Authority")
+	if err != nil || !reflect.DeepEqual(value.Values, []string{`\VED\Policy\Restored\CA`}) || !value.Locked {
+		t.Errorf("Certificate Authority = %+v, %v, want a locked DN under the new root", value, err)
+	}
+
+	report, err := venafi.CompareTrees(source.Config, `\VED\Policy\Teams`, target.Config, `\VED\Policy\Restored`, nil)
+	if err != nil {
+		t.Fatalf("CompareTrees: %v", err)
+	}
+	if !report.Empty() {
+		t.Errorf("restored tree differs from the source:\n%s", report)
+	}
+
+	// Restoring again updates the existing objects in place.
+	if err := export.Restore(target.Config, `\VED\Policy\Restored`); err != nil {
+		t.Fatalf("second Restore: %v", err)
+	}
+}
+
+func TestExportSkipsSubtreesOfSkippedClasses(t *testing.T) {
+	source, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Teams\Devices`, "Device", nil)
+	srv.AddObject(`\VED\Policy\Teams\Devices\app01`, "Basic", nil)
+	srv.AddObject(`\VED\Policy\Teams\Web`, config.ClassPolicy, nil)
+
+	opts := venafi.DefaultExportOptions
+	opts.SkipClasses = []string{"Device"}
+	export, err := venafi.ExportPolicy(source.Config, `\VED\Policy\Teams`, &opts)
+	if err != nil {
+		t.Fatalf("ExportPolicy: %v", err)
+	}
+	var got []string
+	for _, obj := range export.Objects {
+		got = append(got, obj.RelativeDN)
+	}
+	if want := []string{"", `\Web`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("exported %q, want %q", got, want)
+	}
+
+	target, _ := newTestClient(t, nil)
+	if err := export.Restore(target.Config, `\VED\Policy\Teams`); err != nil {
+		t.Errorf("Restore: %v", err)
+	}
+}
+
+func TestRestoreReturnsLookupErrors(t *testing.T) {
+	source, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, nil)
+	export, err := venafi.ExportPolicy(source.Config, `\VED\Policy\Teams`, nil)
+	if err != nil {
+		t.Fatalf("ExportPolicy: %v", err)
+	}
+
+	// An object that cannot be checked is not created again.
+	srv.Inject("/vedsdk/Config/IsValid", venafitest.ConfigFault(config.InsufficientPrivileges, "denied"))
+	if err := export.Restore(source.Config, ""); !errors.Is(err, venafi.ErrInsufficientPrivileges) {
+		t.Errorf("Restore: got %v, want ErrInsufficientPrivileges", err)
+	}
+	srv.ClearFaults()
+	if _, ok := srv.Object(`\VED\Policy\Teams`); !ok {
+		t.Error("the existing root was removed")
+	}
+}
@@ -0,0 +1,365 @@
+package venafi
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	tppconfig "github.com/tradel/venafi-tpp/pkg/const/config"
+	"gopkg.in/yaml.v2"
+)
+
+// PolicySpec describes a tree of policy folders and the policy values that
+// should be set on them. It is normally loaded from YAML or JSON with
+// LoadPolicySpec and turned into changes with PlanPolicy.
+type PolicySpec struct {
+	Root    string       `json:"root" yaml:"root"`
+	Folders []FolderSpec `json:"folders" yaml:"folders"`
+}
+
+// FolderSpec describes one policy folder. Contacts, Approvers, CATemplate and
+// CustomFields are shorthands for policy values on the X509 Certificate class.
+// CustomFields are keyed by the field's name, as defined under
+// \VED\Config\Custom Fields, or by its GUID; PlanPolicy resolves names to the
+// GUIDs TPP stores custom field values under.
+type FolderSpec struct {
+	Name         string                                    `json:"name" yaml:"name"`
+	Contacts     []string                                  `json:"contacts,omitempty" yaml:"contacts,omitempty"`
+	Approvers    []string                                  `json:"approvers,omitempty" yaml:"approvers,omitempty"`
+	CATemplate   string                                    `json:"caTemplate,omitempty" yaml:"caTemplate,omitempty"`
+	CustomFields map[string]PolicyAttributeSpec            `json:"customFields,omitempty" yaml:"customFields,omitempty"`
+	Policies     map[string]map[string]PolicyAttributeSpec `json:"policies,omitempty" yaml:"policies,omitempty"`
+	Folders      []FolderSpec                              `json:"folders,omitempty" yaml:"folders,omitempty"`
+}
+
+// PolicyAttributeSpec is the desired value of one policy attribute.
+type PolicyAttributeSpec struct {
+	Values []string `json:"values" yaml:"values"`
+	Locked bool     `json:"locked,omitempty" yaml:"locked,omitempty"`
+}
+
+// Policy attributes that the FolderSpec shorthands are written to.
+const (
+	policyAttrContact    = "Contact"
+	policyAttrApprover   = "Approver"
+	policyAttrCATemplate = "Certificate Authority"
+)
+
+// customFieldsDN is where TPP keeps custom field definitions.
+const customFieldsDN = `\VED\Config\Custom Fields`
+
+// LoadPolicySpec reads a policy spec from a YAML or JSON file.
+func LoadPolicySpec(path string) (*PolicySpec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePolicySpec(b)
+}
+
+// ParsePolicySpec parses a policy spec from YAML or JSON.
+func ParsePolicySpec(data []byte) (*PolicySpec, error) {
+	var spec PolicySpec
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return nil, fmt.Errorf("error parsing policy spec: %s", err)
+	}
+	if spec.Root == "" {
+		return nil, fmt.Errorf("error parsing policy spec: root is required")
+	}
+	return &spec, nil
+}
+
+// PolicyActionType identifies the kind of change a PolicyAction makes.
+type PolicyActionType string
+
+//noinspection GoUnusedConst
+const (
+	PolicyActionCreateFolder PolicyActionType = "create"
+	PolicyActionWritePolicy  PolicyActionType = "write"
+	PolicyActionClearPolicy  PolicyActionType = "clear"
+)
+
+// PolicyAction is a single change in a PolicyPlan. OldValues and OldLocked
+// record what was in TPP when the plan was made.
+type PolicyAction struct {
+	Type      PolicyActionType
+	DN        string
+	Class     string   `json:",omitempty"`
+	Attribute string   `json:",omitempty"`
+	Values    []string `json:",omitempty"`
+	Locked    bool     `json:",omitempty"`
+	OldValues []string `json:",omitempty"`
+	OldLocked bool     `json:",omitempty"`
+}
+
+func (a PolicyAction) String() string {
+	switch a.Type {
+	case PolicyActionCreateFolder:
+		return fmt.Sprintf("+ create folder %s", a.DN)
+	case PolicyActionWritePolicy:
+		return fmt.Sprintf("~ %s [%s] %s: %s -> %s", a.DN, a.Class, a.Attribute,
+			formatPolicyValues(a.OldValues, a.OldLocked), formatPolicyValues(a.Values, a.Locked))
+	case PolicyActionClearPolicy:
+		return fmt.Sprintf("- %s [%s] %s: %s", a.DN, a.Class, a.Attribute, formatPolicyValues(a.OldValues, a.OldLocked))
+	}
+	return fmt.Sprintf("? %s %s", a.Type, a.DN)
+}
+
+// PolicyPlan is the ordered list of changes needed to make TPP match a
+// PolicySpec. Printing it gives a dry-run summary.
+type PolicyPlan struct {
+	Actions []PolicyAction
+}
+
+// Empty reports whether TPP already matches the spec.
+func (p *PolicyPlan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+func (p *PolicyPlan) String() string {
+	if p.Empty() {
+		return "No changes."
+	}
+	lines := make([]string, len(p.Actions))
+	for i, action := range p.Actions {
+		lines[i] = action.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PlanPolicy compares spec with the folders and policy values read through
+// config and returns the changes needed to make them match. Nothing is
+// modified. Policy attributes named anywhere in the spec are cleared from
+// folders that do not set them; the root folder itself is created if missing
+// but otherwise left alone.
+func PlanPolicy(config ConfigAPI, spec *PolicySpec) (*PolicyPlan, error) {
+	desired := make(map[string]map[policyKey]PolicyAttributeSpec)
+	order := make([]string, 0)
+	spec.flatten(desired, &order)
+	if err := resolveCustomFields(config, desired); err != nil {
+		return nil, err
+	}
+
+	managed := make(map[policyKey]bool)
+	for _, attrs := range desired {
+		for key := range attrs {
+			managed[key] = true
+		}
+	}
+	managedKeys := sortedPolicyKeys(managed)
+
+	plan := &PolicyPlan{Actions: make([]PolicyAction, 0)}
+	for _, dn := range order {
+		exists, err := objectExists(config, dn)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			plan.Actions = append(plan.Actions, PolicyAction{Type: PolicyActionCreateFolder, DN: dn})
+		}
+		if _, isFolder := desired[dn]; !isFolder {
+			continue
+		}
+
+		for _, key := range managedKeys {
+			want, wanted := desired[dn][key]
+
+			current := &PolicyValue{}
+			if exists {
+				var err error
+				current, err = config.ReadPolicy(dn, key.class, key.attribute)
+				if err != nil {
+					return nil, err
+				}
+			}
+			has := len(current.Values) > 0
+
+			switch {
+			case wanted && (!sameValues(want.Values, current.Values) || want.Locked != current.Locked):
+				plan.Actions = append(plan.Actions, PolicyAction{
+					Type: PolicyActionWritePolicy, DN: dn, Class: key.class, Attribute: key.attribute,
+					Values: want.Values, Locked: want.Locked, OldValues: current.Values, OldLocked: current.Locked,
+				})
+			case !wanted && has:
+				plan.Actions = append(plan.Actions, PolicyAction{
+					Type: PolicyActionClearPolicy, DN: dn, Class: key.class, Attribute: key.attribute,
+					OldValues: current.Values, OldLocked: current.Locked,
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply carries out the actions in the plan, in order, through config. New
+// folders get the identity's Self as their contact. If an action fails, the
+// actions already carried out are rolled back.
+func (p *PolicyPlan) Apply(config ConfigAPI, identity IdentityAPI) error {
+	attrs, err := policyFolderAttributes(identity)
+	if err != nil {
+		return err
+	}
+
+	return RunConfigBatch(config, func(b *ConfigBatch) error {
+		for _, action := range p.Actions {
+			var err error
+			switch action.Type {
+			case PolicyActionCreateFolder:
+				_, err = b.Create(action.DN, tppconfig.ClassPolicy, attrs)
+			case PolicyActionWritePolicy:
+				err = b.WritePolicy(action.DN, action.Class, action.Attribute, action.Values, action.Locked)
+			case PolicyActionClearPolicy:
+				err = b.ClearPolicyAttribute(action.DN, action.Class, action.Attribute)
+			default:
+				err = fmt.Errorf("unknown policy action: %s", action.Type)
+			}
+			if err != nil {
+				return fmt.Errorf("error applying %q: %w", action.String(), err)
+			}
+		}
+		return nil
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// policyKey names a policy attribute. customField marks an attribute that is
+// still a custom field name, to be replaced with the field's GUID.
+type policyKey struct {
+	class       string
+	attribute   string
+	customField bool
+}
+
+// flatten records the desired policy values of every folder in the spec by
+// DN, and all DNs including the root in parent-first order.
+func (spec *PolicySpec) flatten(desired map[string]map[policyKey]PolicyAttributeSpec, order *[]string) {
+	root := strings.TrimSuffix(spec.Root, `\`)
+	*order = append(*order, root)
+	for _, folder := range spec.Folders {
+		folder.flatten(root, desired, order)
+	}
+}
+
+func (f *FolderSpec) flatten(parentDN string, desired map[string]map[policyKey]PolicyAttributeSpec, order *[]string) {
+	dn := parentDN + `\` + f.Name
+	attrs := make(map[policyKey]PolicyAttributeSpec)
+
+	for class, classAttrs := range f.Policies {
+		for name, value := range classAttrs {
+			attrs[policyKey{class: class, attribute: name}] = value
+		}
+	}
+	if len(f.Contacts) > 0 {
+		attrs[policyKey{class: tppconfig.ClassX509Certificate, attribute: policyAttrContact}] = PolicyAttributeSpec{Values: f.Contacts}
+	}
+	if len(f.Approvers) > 0 {
+		attrs[policyKey{class: tppconfig.ClassX509Certificate, attribute: policyAttrApprover}] = PolicyAttributeSpec{Values: f.Approvers}
+	}
+	if f.CATemplate != "" {
+		attrs[policyKey{class: tppconfig.ClassX509Certificate, attribute: policyAttrCATemplate}] = PolicyAttributeSpec{Values: []string{f.CATemplate}}
+	}
+	for name, value := range f.CustomFields {
+		attrs[policyKey{class: tppconfig.ClassX509Certificate, attribute: name, customField: true}] = value
+	}
+
+	desired[dn] = attrs
+	*order = append(*order, dn)
+	for _, child := range f.Folders {
+		child.flatten(dn, desired, order)
+	}
+}
+
+// resolveCustomFields replaces the custom field names in desired with the GUIDs
+// of their definitions, which TPP stores custom field values under. Names that
+// are already GUIDs are kept.
+func resolveCustomFields(config ConfigAPI, desired map[string]map[policyKey]PolicyAttributeSpec) error {
+	guids := make(map[string]string)
+	for _, attrs := range desired {
+		for key, value := range attrs {
+			if !key.customField {
+				continue
+			}
+			guid, ok := guids[key.attribute]
+			if !ok {
+				guid = key.attribute
+				if !isGUID(guid) {
+					var err error
+					guid, err = config.DnToGuid(customFieldsDN + `\` + key.attribute)
+					if errors.Is(err, ErrNotFound) {
+						return fmt.Errorf("custom field %q is not defined under %s", key.attribute, customFieldsDN)
+					}
+					if err != nil {
+						return err
+					}
+				}
+				guids[key.attribute] = guid
+			}
+			delete(attrs, key)
+			attrs[policyKey{class: key.class, attribute: guid}] = value
+		}
+	}
+	return nil
+}
+
+// objectExists reports whether dn exists. Only ErrNotFound counts as missing;
+// other errors, such as a failed login, are returned.
+func objectExists(config ConfigAPI, dn string) (bool, error) {
+	_, err := config.IsValid(dn, "")
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isGUID reports whether s is a GUID in braces, as TPP writes them.
+func isGUID(s string) bool {
+	return len(s) == 38 && s[0] == '{' && s[37] == '}'
+}
+
+func sortedPolicyKeys(keys map[policyKey]bool) []policyKey {
+	rv := make([]policyKey, 0, len(keys))
+	for key := range keys {
+		rv = append(rv, key)
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		if rv[i].class != rv[j].class {
+			return rv[i].class < rv[j].class
+		}
+		return rv[i].attribute < rv[j].attribute
+	})
+	return rv
+}
+
+// sameValues compares two value lists, ignoring order.
+func sameValues(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func formatPolicyValues(values []string, locked bool) string {
+	text := "[" + strings.Join(values, ", ") + "]"
+	if locked {
+		text += " (locked)"
+	}
+	return text
+}
@@ -0,0 +1,152 @@
+package venafi_test
+
+import (
+	"errors"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+const testPolicySpec = `
+root: \VED\Policy\Teams
+folders:
+  - name: Web
+    caTemplate: \VED\Policy\CA Templates\Issuing CA
+    policies:
+      X509 Certificate:
+        Key Bit Strength: {values: ["2048"], locked: true}
+    folders:
+      - name: Internal
+        policies:
+          X509 Certificate:
+            Key Bit Strength: {values: ["4096"]}
+  - name: Db
+`
+
+func TestPolicyPlanApplyIsIdempotent(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\CA Templates`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\CA Templates\Issuing CA`, config.ClassSelfSignedCA, nil)
+
+	spec, err := venafi.ParsePolicySpec([]byte(testPolicySpec))
+	if err != nil {
+		t.Fatalf("ParsePolicySpec: %v", err)
+	}
+
+	plan, err := venafi.PlanPolicy(v.Config, spec)
+	if err != nil {
+		t.Fatalf("PlanPolicy: %v", err)
+	}
+	if plan.Empty() {
+		t.Fatal("first plan is empty")
+	}
+	if err := plan.Apply(v.Config, v.Identity); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	for _, dn := range []string{`\VED\Policy\Teams`, `\VED\Policy\Teams\Web`, `\VED\Policy\Teams\Web\Internal`, `\VED\Policy\Teams\Db`} {
+		if !v.Config.Exists(dn) {
+			t.Errorf("%s was not created", dn)
+		}
+	}
+	value, err := v.Config.ReadPolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, "Key Bit Strength")
+	if err != nil || len(value.Values) != 1 || value.Values[0] != "2048" || !value.Locked {
+		t.Errorf("Key Bit Strength on Web = %+v, %v, want locked [2048]", value, err)
+	}
+
+	again, err := venafi.PlanPolicy(v.Config, spec)
+	if err != nil {
+		t.Fatalf("second PlanPolicy: %v", err)
+	}
+	if !again.Empty() {
+		t.Errorf("second plan is not empty:\n%s", again)
+	}
+}
+
+func TestPolicyApplyRollsBackOnFailure(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\CA Templates`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\CA Templates\Issuing CA`, config.ClassSelfSignedCA, nil)
+
+	spec, err := venafi.ParsePolicySpec([]byte(testPolicySpec))
+	if err != nil {
+		t.Fatalf("ParsePolicySpec: %v", err)
+	}
+	plan, err := venafi.PlanPolicy(v.Config, spec)
+	if err != nil {
+		t.Fatalf("PlanPolicy: %v", err)
+	}
+
+	// The root folder is created before the first policy write fails, so it
+	// has to be rolled back.
+	srv.Inject("/vedsdk/Config/WritePolicy", venafitest.ConfigFault(config.InsufficientPrivileges, "denied"))
+
+	err = plan.Apply(v.Config, v.Identity)
+	if !errors.Is(err, venafi.ErrInsufficientPrivileges) {
+		t.Fatalf("Apply: got %v, want ErrInsufficientPrivileges", err)
+	}
+	if v.Config.Exists(`\VED\Policy\Teams`) {
+		t.Error("folders created by the failed Apply were not removed")
+	}
+}
+
+func TestPolicyPlanReturnsLookupErrors(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	spec, err := venafi.ParsePolicySpec([]byte(testPolicySpec))
+	if err != nil {
+		t.Fatalf("ParsePolicySpec: %v", err)
+	}
+
+	// A folder that cannot be checked is not planned as missing.
+	srv.Inject("/vedsdk/Config/IsValid", venafitest.ConfigFault(config.InsufficientPrivileges, "denied"))
+	plan, err := venafi.PlanPolicy(v.Config, spec)
+	if !errors.Is(err, venafi.ErrInsufficientPrivileges) {
+		t.Errorf("PlanPolicy: got %v, %v, want ErrInsufficientPrivileges", plan, err)
+	}
+}
+
+func TestPolicyPlanResolvesCustomFields(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Config`, "Folder", nil)
+	srv.AddObject(`\VED\Config\Custom Fields`, "Folder", nil)
+	costCenter := srv.AddObject(`\VED\Config\Custom Fields\Cost Center`, "Metadata Text", nil)
+	owner := srv.AddObject(`\VED\Config\Custom Fields\Owner`, "Metadata Text", nil)
+
+	spec, err := venafi.ParsePolicySpec([]byte(`
+root: \VED\Policy\Teams
+folders:
+  - name: Web
+    customFields:
+      Cost Center: {values: ["4711"], locked: true}
+      "` + owner.GUID + `": {values: ["web-team"]}
+`))
+	if err != nil {
+		t.Fatalf("ParsePolicySpec: %v", err)
+	}
+	plan, err := venafi.PlanPolicy(v.Config, spec)
+	if err != nil {
+		t.Fatalf("PlanPolicy: %v", err)
+	}
+	if err := plan.Apply(v.Config, v.Identity); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	// Values are stored under the field GUIDs, whether the spec names the
+	// field or gives its GUID.
+	for guid, want := range map[string]string{costCenter.GUID: "4711", owner.GUID: "web-team"} {
+		value, err := v.Config.ReadPolicy(`\VED\Policy\Teams\Web`, config.ClassX509Certificate, guid)
+		if err != nil || len(value.Values) != 1 || value.Values[0] != want {
+			t.Errorf("policy %s = %+v, %v, want [%s]", guid, value, err, want)
+		}
+	}
+	if again, err := venafi.PlanPolicy(v.Config, spec); err != nil || !again.Empty() {
+		t.Errorf("second plan = %v, %v, want no changes", again, err)
+	}
+
+	spec.Folders[0].CustomFields["Missing"] = venafi.PolicyAttributeSpec{Values: []string{"x"}}
+	if _, err := venafi.PlanPolicy(v.Config, spec); err == nil {
+		t.Error("PlanPolicy with an undefined custom field succeeded")
+	}
+}
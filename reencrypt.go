@@ -0,0 +1,122 @@
+package venafi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+// ReEncryptProgress reports one vault entry handled by ForceReEncryptTree. Done
+// counts the entries handled so far, this one included, out of Total. Err is
+// set if this entry could not be re-encrypted.
+type ReEncryptProgress struct {
+	VaultID int
+	OwnerDN string
+	Done    int
+	Total   int
+	Err     error
+}
+
+// ReEncryptResult summarizes a ForceReEncryptTree run.
+type ReEncryptResult struct {
+	RootDN      string
+	To          secret_store.ProtectionKey
+	Total       int
+	ReEncrypted int
+	Failures    []error
+}
+
+// ForceReEncryptTree re-encrypts every vault entry owned by an object at or
+// below rootDN with the protection key to, calling progress, if not nil, after
+// each one. It carries on past failures, which are listed in the result and
+// summarized in the returned error.
+//
+// TPP does not report which key encrypts an individual entry, so this is a
+// forced re-key: every entry in the subtree is moved to to, including entries
+// deliberately kept under a different key. Entries already under to are
+// re-encrypted harmlessly. Use it only on subtrees that should all share one
+// key.
+func ForceReEncryptTree(secretStore SecretStoreAPI, config ConfigAPI, crypto CryptoAPI, rootDN string,
+	to secret_store.ProtectionKey, progress func(ReEncryptProgress)) (*ReEncryptResult, error) {
+	rootDN = strings.TrimSuffix(rootDN, `\`)
+	result := &ReEncryptResult{RootDN: rootDN, To: to}
+
+	available, err := crypto.AvailableKeys()
+	if err != nil {
+		return nil, err
+	}
+	if !containsKey(available, to) {
+		return nil, fmt.Errorf("protection key %s is not available", to)
+	}
+
+	owners, err := vaultOwners(secretStore, config, rootDN)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(owners))
+	for id := range owners {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	result.Total = len(ids)
+
+	for i, id := range ids {
+		err := secretStore.ReEncrypt(id, to)
+		if err != nil {
+			result.Failures = append(result.Failures, fmt.Errorf("error re-encrypting vault entry %d owned by %s: %w", id, owners[id], err))
+		} else {
+			result.ReEncrypted++
+		}
+		if progress != nil {
+			progress(ReEncryptProgress{VaultID: id, OwnerDN: owners[id], Done: i + 1, Total: result.Total, Err: err})
+		}
+	}
+
+	if len(result.Failures) > 0 {
+		return result, fmt.Errorf("%d of %d vault entries were not re-encrypted: %w",
+			len(result.Failures), result.Total, result.Failures[0])
+	}
+	return result, nil
+}
+
+// vaultOwners maps the vault ID of every entry owned under rootDN to the
+// first owner it was found through.
+func vaultOwners(secretStore SecretStoreAPI, config ConfigAPI, rootDN string) (map[int]string, error) {
+	root, err := config.IsValid(rootDN, "")
+	if err != nil {
+		return nil, err
+	}
+	children, err := config.Enumerate(rootDN, true, "")
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[int]string)
+	for _, obj := range append([]ConfigObject{*root}, children...) {
+		ids, err := secretStore.LookupByOwner(obj.DN, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if _, ok := owners[id]; !ok {
+				owners[id] = obj.DN
+			}
+		}
+	}
+	return owners, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+func containsKey(keys []secret_store.ProtectionKey, key secret_store.ProtectionKey) bool {
+	for _, k := range keys {
+		if strings.EqualFold(string(k), string(key)) {
+			return true
+		}
+	}
+	return false
+}
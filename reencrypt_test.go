@@ -0,0 +1,91 @@
+package venafi_test
+
+import (
+	"errors"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+func TestForceReEncryptTree(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	hsm := secret_store.NewProtectionKey(secret_store.ProviderHSM, "Prod Key")
+	srv.AddProtectionKey(hsm)
+	srv.AddObject(`\VED\Policy\Prod`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Prod\web01`, config.ClassX509Certificate, nil)
+	srv.AddObject(`\VED\Policy\Prod\web02`, config.ClassX509Certificate, nil)
+	srv.AddObject(`\VED\Policy\Test`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Test\web03`, config.ClassX509Certificate, nil)
+
+	var prod []int
+	for _, owner := range []string{`\VED\Policy\Prod\web01`, `\VED\Policy\Prod\web02`} {
+		id, err := v.SecretStore.Add([]byte("s3cret"), secret_store.VaultTypePassword, owner, "")
+		if err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		prod = append(prod, id)
+	}
+	if _, err := v.SecretStore.Add([]byte("s3cret"), secret_store.VaultTypePassword, `\VED\Policy\Test\web03`, ""); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	var progress []venafi.ReEncryptProgress
+	result, err := venafi.ForceReEncryptTree(v.SecretStore, v.Config, v.Crypto, `\VED\Policy\Prod`, hsm,
+		func(p venafi.ReEncryptProgress) { progress = append(progress, p) })
+	if err != nil {
+		t.Fatalf("ForceReEncryptTree: %v", err)
+	}
+	if result.Total != 2 || result.ReEncrypted != 2 || len(result.Failures) != 0 {
+		t.Errorf("result = %+v, want 2 of 2 re-encrypted", result)
+	}
+	if len(progress) != 2 || progress[1].Done != 2 || progress[1].Total != 2 {
+		t.Errorf("progress = %+v, want two calls ending at 2/2", progress)
+	}
+	for i, p := range progress {
+		if p.VaultID != prod[i] || p.Err != nil {
+			t.Errorf("progress[%d] = %+v, want vault ID %d without error", i, p, prod[i])
+		}
+	}
+
+	// The entry outside the subtree keeps the default key.
+	keys, err := v.SecretStore.EncryptionKeysInUse()
+	if err != nil {
+		t.Fatalf("EncryptionKeysInUse: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("keys in use = %v, want the default and %s", keys, hsm)
+	}
+}
+
+func TestForceReEncryptTreeFailures(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	hsm := secret_store.NewProtectionKey(secret_store.ProviderHSM, "Prod Key")
+	srv.AddObject(`\VED\Policy\Prod`, config.ClassPolicy, nil)
+	for _, owner := range []string{`\VED\Policy\Prod\web01`, `\VED\Policy\Prod\web02`} {
+		srv.AddObject(owner, config.ClassX509Certificate, nil)
+		if _, err := v.SecretStore.Add([]byte("s3cret"), secret_store.VaultTypePassword, owner, ""); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	if _, err := venafi.ForceReEncryptTree(v.SecretStore, v.Config, v.Crypto, `\VED\Policy\Prod`, hsm, nil); err == nil {
+		t.Error("ForceReEncryptTree to an unavailable key succeeded")
+	}
+
+	// A failed entry is reported and the rest are still re-encrypted.
+	srv.AddProtectionKey(hsm)
+	fault := venafitest.StoreFault(secret_store.InsufficientPermissions)
+	fault.Times = 1
+	srv.Inject("/vedsdk/SecretStore/ReEncrypt", fault)
+
+	result, err := venafi.ForceReEncryptTree(v.SecretStore, v.Config, v.Crypto, `\VED\Policy\Prod`, hsm, nil)
+	if !errors.Is(err, venafi.ErrInsufficientPrivileges) {
+		t.Fatalf("ForceReEncryptTree: got %v, want ErrInsufficientPrivileges", err)
+	}
+	if result.Total != 2 || result.ReEncrypted != 1 || len(result.Failures) != 1 {
+		t.Errorf("result = %+v, want 1 of 2 re-encrypted", result)
+	}
+}
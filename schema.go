@@ -0,0 +1,237 @@
+package venafi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+// ClassDefinition describes a class in the TPP schema. MandatoryNames and
+// OptionalNames include attributes inherited from SuperClassNames.
+type ClassDefinition struct {
+	Name             string
+	SuperClassNames  []string
+	ContainmentNames []string
+	MandatoryNames   []string
+	OptionalNames    []string
+	NamingNames      []string
+	Revision         int64
+}
+
+// AttributeDefinition describes an attribute in the TPP schema.
+type AttributeDefinition struct {
+	Name     string
+	Syntax   config.AttributeSyntax
+	Property int
+	Revision int64
+}
+
+type SchemaService struct {
+	client *Client
+}
+
+// HighestRevision returns the highest revision of any object at or below
+// objectDN, optionally restricted to the given classes. It changes whenever
+// anything in the subtree does, so it is a cheap way to detect changes.
+func (s *SchemaService) HighestRevision(objectDN string, classNames ...string) (int64, error) {
+	type Input struct {
+		ObjectDN string
+		Classes  string `json:",omitempty"`
+	}
+	type Output struct {
+		Revision int64
+	}
+
+	var input Input = Input{objectDN, strings.Join(classNames, ",")}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/Config/GetHighestRevision", input, &output)
+	if err != nil {
+		return 0, err
+	}
+
+	return output.Revision, nil
+}
+
+// Class returns the definition of className.
+func (s *SchemaService) Class(className string) (*ClassDefinition, error) {
+	type Input struct {
+		Class string
+	}
+	type Output struct {
+		ClassDefinition ClassDefinition
+	}
+
+	var input Input = Input{className}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/ConfigSchema/Class", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &output.ClassDefinition, nil
+}
+
+// Attributes returns the definition of every attribute in the schema.
+func (s *SchemaService) Attributes() ([]AttributeDefinition, error) {
+	type Output struct {
+		AttributeDefinitions []AttributeDefinition
+	}
+
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/ConfigSchema/Attributes", struct{}{}, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.AttributeDefinitions, nil
+}
+
+// Containment returns the classes that objects of className may contain.
+func (s *SchemaService) Containment(className string) ([]string, error) {
+	type Input struct {
+		Class string
+	}
+	type Output struct {
+		ClassNames []string
+	}
+
+	var input Input = Input{className}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/ConfigSchema/Containment", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.ClassNames, nil
+}
+
+// ValidateCreate checks a ConfigService.Create call against the schema
+// without making it. It returns an error if className does not exist, may not
+// be contained by the parent of objectDN, or does not allow one of the given
+// attributes.
+func (s *SchemaService) ValidateCreate(objectDN string, className string, attributes map[string]string) error {
+	class, err := s.Class(className)
+	if err != nil {
+		return err
+	}
+
+	i := strings.LastIndex(objectDN, `\`)
+	if i <= 0 {
+		return newConfigError(nil, config.InvalidArgument, fmt.Sprintf("%q has no parent", objectDN))
+	}
+	parent, err := s.client.Config.IsValid(objectDN[:i], "")
+	if err != nil {
+		return err
+	}
+	if !containsFold(class.ContainmentNames, parent.Class) {
+		return newConfigError(nil, config.ObjectInvalidContainment,
+			fmt.Sprintf("a %s object cannot be contained in a %s object", className, parent.Class))
+	}
+
+	for name := range attributes {
+		if !containsFold(class.MandatoryNames, name) && !containsFold(class.OptionalNames, name) {
+			return newConfigError(nil, config.IllegalAttributeForClass,
+				fmt.Sprintf("attribute %q is not allowed on class %s", name, className))
+		}
+	}
+
+	return nil
+}
+
+// GenerateConstants writes a Go source file declaring a Class constant for
+// each of classNames and an Attr constant for every attribute in the schema
+// read through schema, in the style of pkg/const/config. Names that map to the
+// same identifier are told apart with a numeric suffix.
+func GenerateConstants(schema SchemaAPI, w io.Writer, packageName string, classNames []string) error {
+	attrs, err := schema.Attributes()
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(attrs))
+	for i, attr := range attrs {
+		names[i] = attr.Name
+	}
+	classNames = append([]string(nil), classNames...)
+	sort.Strings(classNames)
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated from the TPP schema. DO NOT EDIT.\n\npackage %s\n", packageName)
+	writeConstBlock(&buf, "Class", classNames)
+	writeConstBlock(&buf, "Attr", names)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// doRequestWithBody sends a request to a Config or ConfigSchema endpoint,
+// which share the same result codes.
+func (s *SchemaService) doRequestWithBody(method string, path string, body interface{}, output interface{}) (*http.Response, error) {
+	return (&ConfigService{s.client}).doRequestWithBody(method, path, body, output)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// writeConstBlock declares a constant named prefix plus the Go form of each
+// of names. Repeated names are declared once. Distinct names that turn into
+// the same identifier, such as "Key Size" and "Key-Size", get a numeric
+// suffix in sorted order, so none is dropped.
+func writeConstBlock(w io.Writer, prefix string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	declared := make(map[string]bool)
+	used := make(map[string]bool)
+	fmt.Fprint(w, "\nconst (\n")
+	for _, name := range names {
+		if declared[name] {
+			continue
+		}
+		declared[name] = true
+
+		base := prefix + goIdentifier(name)
+		ident := base
+		for n := 2; used[ident]; n++ {
+			ident = fmt.Sprintf("%s%d", base, n)
+		}
+		used[ident] = true
+		fmt.Fprintf(w, "\t%s = %q\n", ident, name)
+	}
+	fmt.Fprint(w, ")\n")
+}
+
+// goIdentifier turns a schema name such as "X509 Certificate" or "Key Bit
+// Strength" into an exported Go identifier like "X509Certificate".
+func goIdentifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package venafi_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/mocks"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+func TestSchemaService(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+
+	class, err := v.Schema.Class(config.ClassX509Certificate)
+	if err != nil {
+		t.Fatalf("Class: %v", err)
+	}
+	if class.Name != config.ClassX509Certificate ||
+		!reflect.DeepEqual(class.ContainmentNames, []string{config.ClassPolicy, config.ClassDevice}) {
+		t.Errorf("Class = %+v", class)
+	}
+	if _, err := v.Schema.Class("No Such Class"); !errors.Is(err, venafi.ErrNotFound) {
+		t.Errorf("Class of an unknown class: got %v, want ErrNotFound", err)
+	}
+
+	names, err := v.Schema.Containment(config.ClassDevice)
+	if err != nil || !reflect.DeepEqual(names, []string{config.ClassX509Certificate}) {
+		t.Errorf("Containment(Device) = %q, %v", names, err)
+	}
+
+	attrs, err := v.Schema.Attributes()
+	if err != nil {
+		t.Fatalf("Attributes: %v", err)
+	}
+	syntax := make(map[string]config.AttributeSyntax)
+	for _, attr := range attrs {
+		syntax[attr.Name] = attr.Syntax
+	}
+	if syntax["Key Bit Strength"] != config.SyntaxInteger || syntax["Contact"] != config.SyntaxDN {
+		t.Errorf("Attributes returned syntaxes %v", syntax)
+	}
+
+	// The highest revision moves when anything in the subtree changes.
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Web\web01`, config.ClassX509Certificate, nil)
+	before, err := v.Schema.HighestRevision(`\VED\Policy\Web`)
+	if err != nil {
+		t.Fatalf("HighestRevision: %v", err)
+	}
+	if err := v.Config.Write(`\VED\Policy\Web\web01`, map[string][]string{"Description": {"changed"}}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if after, err := v.Schema.HighestRevision(`\VED\Policy\Web`); err != nil || after <= before {
+		t.Errorf("HighestRevision after a write = %d, %v, want more than %d", after, err, before)
+	}
+	web, err := v.Config.IsValid(`\VED\Policy\Web`, "")
+	if err != nil {
+		t.Fatalf("IsValid: %v", err)
+	}
+	if after, err := v.Schema.HighestRevision(`\VED\Policy\Web`, config.ClassPolicy); err != nil || after != web.Revision {
+		t.Errorf("HighestRevision of policies only = %d, %v, want the revision of Web, %d", after, err, web.Revision)
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+	srv.AddObject(`\VED\Policy\Web\app01`, config.ClassDevice, nil)
+
+	if err := v.Schema.ValidateCreate(`\VED\Policy\Web\web01`, config.ClassX509Certificate,
+		map[string]string{"Description": "web01"}); err != nil {
+		t.Errorf("ValidateCreate of a valid object: %v", err)
+	}
+	if _, ok := srv.Object(`\VED\Policy\Web\web01`); ok {
+		t.Error("ValidateCreate created the object")
+	}
+
+	tests := []struct {
+		name   string
+		dn     string
+		class  string
+		attrs  map[string]string
+		result config.ConfigResult
+	}{
+		{"unknown class", `\VED\Policy\Web\x`, "No Such Class", nil, config.ClassDoesNotExist},
+		{"no parent", `VED`, config.ClassPolicy, nil, config.InvalidArgument},
+		{"missing parent", `\VED\Policy\Missing\x`, config.ClassPolicy, nil, config.ObjectDoesNotExist},
+		{"invalid containment", `\VED\Policy\Web\app01\Sub`, config.ClassPolicy, nil, config.ObjectInvalidContainment},
+		{"illegal attribute", `\VED\Policy\Web\Sub`, config.ClassPolicy, map[string]string{"Bogus": "x"}, config.IllegalAttributeForClass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Schema.ValidateCreate(tt.dn, tt.class, tt.attrs)
+			var configErr *venafi.ConfigServiceError
+			if !errors.As(err, &configErr) || configErr.Result != tt.result {
+				t.Errorf("got %v, want result %d", err, tt.result)
+			}
+		})
+	}
+}
+
+func TestGenerateConstants(t *testing.T) {
+	schema := &mocks.SchemaAPI{
+		AttributesFunc: func() ([]venafi.AttributeDefinition, error) {
+			return []venafi.AttributeDefinition{
+				{Name: "Key Size"}, {Name: "Key-Size"}, {Name: "Contact"}, {Name: "Key Size"}, {Name: "key size"},
+			}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := venafi.GenerateConstants(schema, &buf, "tppschema",
+		[]string{config.ClassX509Certificate, config.ClassPolicy}); err != nil {
+		t.Fatalf("GenerateConstants: %v", err)
+	}
+	// Names that map to the same identifier are all kept, with a suffix.
+	want := `// Code generated from the TPP schema. DO NOT EDIT.
+
+package tppschema
+
+const (
+	ClassPolicy          = "Policy"
+	ClassX509Certificate = "X509 Certificate"
+)
+
+const (
+	AttrContact  = "Contact"
+	AttrKeySize  = "Key Size"
+	AttrKeySize2 = "Key-Size"
+	AttrKeySize3 = "key size"
+)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("generated:\n%s\nwant:\n%s", got, want)
+	}
+
+	schema.AttributesFunc = func() ([]venafi.AttributeDefinition, error) { return nil, venafi.ErrUnauthorized }
+	if err := venafi.GenerateConstants(schema, &buf, "tppschema", nil); !errors.Is(err, venafi.ErrUnauthorized) {
+		t.Errorf("GenerateConstants with a failing schema: got %v, want ErrUnauthorized", err)
+	}
+	if strings.Count(buf.String(), "package") != 1 {
+		t.Error("GenerateConstants wrote output although reading the schema failed")
+	}
+}
@@ -0,0 +1,293 @@
+package venafi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+type SecretStoreService struct {
+	client *Client
+}
+
+// associationInput names an associated value and carries it in whichever of
+// TPP's typed value fields matches its Go type.
+type associationInput struct {
+	VaultID     int    `json:",omitempty"`
+	Name        string
+	StringValue string `json:",omitempty"`
+	IntValue    *int   `json:",omitempty"`
+	DateValue   string `json:",omitempty"`
+}
+
+func (s *SecretStoreService) doRequestWithBody(method string, path string, body interface{}, output interface{}) (*http.Response, error) {
+	return (&X509StoreService{s.client}).doRequestWithBody(method, path, body, output)
+}
+
+// Add stores data in a new vault entry of the given type, encrypted with
+// protectionKey and owned by ownerDN, and returns its vault ID. An empty
+// protectionKey uses secret_store.ProtectionKeyDefault.
+func (s *SecretStoreService) Add(data []byte, vaultType secret_store.VaultType, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error) {
+	if protectionKey == "" {
+		protectionKey = secret_store.ProtectionKeyDefault
+	}
+
+	type Input struct {
+		Base64Data string
+		Keyname    secret_store.ProtectionKey
+		Namespace  string
+		Owner      string
+		VaultType  secret_store.VaultType
+	}
+	type Output struct {
+		Result  secret_store.SecretStoreResult
+		VaultID int
+	}
+
+	var input Input = Input{base64.StdEncoding.EncodeToString(data), protectionKey, secret_store.NamespaceConfig,
+		ownerDN, vaultType}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/SecretStore/Add", input, &output)
+	if err != nil {
+		return -1, err
+	}
+
+	return output.VaultID, nil
+}
+
+// Retrieve returns the decrypted contents of a vault entry and its type.
+func (s *SecretStoreService) Retrieve(vaultID int) ([]byte, secret_store.VaultType, error) {
+	type Input struct {
+		VaultID int
+	}
+	type Output struct {
+		Base64Data string
+		Result     secret_store.SecretStoreResult
+		VaultType  secret_store.VaultType
+	}
+
+	var input Input = Input{vaultID}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/SecretStore/Retrieve", input, &output)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(output.Base64Data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return data, output.VaultType, nil
+}
+
+// Associate attaches a named value to a vault entry. value must be a string,
+// an int or a time.Time.
+func (s *SecretStoreService) Associate(vaultID int, name string, value interface{}) error {
+	input, err := newAssociationInput(vaultID, name, value)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequestWithBody("POST", "/vedsdk/SecretStore/Associate", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Dissociate removes a named value from a vault entry. A nil value removes
+// every value with that name.
+func (s *SecretStoreService) Dissociate(vaultID int, name string, value interface{}) error {
+	input, err := newAssociationInput(vaultID, name, value)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.doRequestWithBody("POST", "/vedsdk/SecretStore/Dissociate", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LookupByAssociation returns the vault IDs of entries that have the named
+// value attached. value must be a string, an int or a time.Time.
+func (s *SecretStoreService) LookupByAssociation(name string, value interface{}) ([]int, error) {
+	type Output struct {
+		Result   secret_store.SecretStoreResult
+		VaultIDs []int
+	}
+
+	input, err := newAssociationInput(0, name, value)
+	if err != nil {
+		return nil, err
+	}
+	var output Output
+
+	_, err = s.doRequestWithBody("POST", "/vedsdk/SecretStore/LookupByAssociation", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.VaultIDs, nil
+}
+
+// LookupByOwner returns the vault IDs of entries owned by ownerDN. A zero
+// vaultType matches entries of any type.
+func (s *SecretStoreService) LookupByOwner(ownerDN string, vaultType secret_store.VaultType) ([]int, error) {
+	type Input struct {
+		Namespace string
+		Owner     string
+		VaultType secret_store.VaultType `json:",omitempty"`
+	}
+	type Output struct {
+		Result   secret_store.SecretStoreResult
+		VaultIDs []int
+	}
+
+	var input Input = Input{secret_store.NamespaceConfig, ownerDN, vaultType}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/SecretStore/LookupByOwner", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.VaultIDs, nil
+}
+
+// Mutate changes the type of a vault entry, for example to archive it.
+func (s *SecretStoreService) Mutate(vaultID int, vaultType secret_store.VaultType) error {
+	type Input struct {
+		VaultID   int
+		VaultType secret_store.VaultType
+	}
+
+	var input Input = Input{vaultID, vaultType}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/SecretStore/Mutate", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// OwnerAdd makes ownerDN an owner of a vault entry.
+func (s *SecretStoreService) OwnerAdd(vaultID int, ownerDN string) error {
+	return s.owner("/vedsdk/SecretStore/OwnerAdd", vaultID, ownerDN)
+}
+
+// OwnerDelete removes ownerDN from the owners of a vault entry. TPP deletes
+// the entry when its last owner is removed.
+func (s *SecretStoreService) OwnerDelete(vaultID int, ownerDN string) error {
+	return s.owner("/vedsdk/SecretStore/OwnerDelete", vaultID, ownerDN)
+}
+
+// OwnerLookup returns the DNs of the owners of a vault entry.
+func (s *SecretStoreService) OwnerLookup(vaultID int) ([]string, error) {
+	type Input struct {
+		Namespace string
+		VaultID   int
+	}
+	type Output struct {
+		Owners []string
+		Result secret_store.SecretStoreResult
+	}
+
+	var input Input = Input{secret_store.NamespaceConfig, vaultID}
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/SecretStore/OwnerLookup", input, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Owners, nil
+}
+
+// EncryptionKeysInUse returns the protection keys that encrypt at least one
+// vault entry.
+func (s *SecretStoreService) EncryptionKeysInUse() ([]secret_store.ProtectionKey, error) {
+	type Output struct {
+		EncryptionKeys []secret_store.ProtectionKey
+		Result         secret_store.SecretStoreResult
+	}
+
+	var output Output
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/SecretStore/EncryptionKeysInUse", struct{}{}, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	return output.EncryptionKeys, nil
+}
+
+// ReEncrypt encrypts a vault entry again with protectionKey.
+func (s *SecretStoreService) ReEncrypt(vaultID int, protectionKey secret_store.ProtectionKey) error {
+	type Input struct {
+		Keyname secret_store.ProtectionKey
+		VaultID int
+	}
+
+	var input Input = Input{protectionKey, vaultID}
+
+	_, err := s.doRequestWithBody("POST", "/vedsdk/SecretStore/ReEncrypt", input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SecretStoreService) owner(path string, vaultID int, ownerDN string) error {
+	type Input struct {
+		Namespace string
+		Owner     string
+		VaultID   int
+	}
+
+	var input Input = Input{secret_store.NamespaceConfig, ownerDN, vaultID}
+
+	_, err := s.doRequestWithBody("POST", path, input, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+func newAssociationInput(vaultID int, name string, value interface{}) (*associationInput, error) {
+	input := &associationInput{VaultID: vaultID, Name: name}
+	switch v := value.(type) {
+	case nil:
+	case string:
+		input.StringValue = v
+	case int:
+		input.IntValue = &v
+	case time.Time:
+		input.DateValue = formatDate(v)
+	default:
+		return nil, fmt.Errorf("unsupported type %T for associated value %s", value, name)
+	}
+	return input, nil
+}
+
+// formatDate formats t in the "/Date(milliseconds)/" form TPP uses for dates.
+func formatDate(t time.Time) string {
+	return "/Date(" + strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10) + ")/"
+}
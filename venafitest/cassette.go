@@ -0,0 +1,223 @@
+package venafitest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tradel/venafi-tpp/pkg/redact"
+)
+
+// Cassette is a fixture file of recorded TPP interactions.
+type Cassette struct {
+	Interactions []Interaction
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest
+	Response RecordedResponse
+}
+
+// RecordedRequest is the sanitized form of an http.Request.
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Header http.Header `json:",omitempty"`
+	Body   string      `json:",omitempty"`
+}
+
+// RecordedResponse is the sanitized form of an http.Response.
+type RecordedResponse struct {
+	StatusCode int
+	Header     http.Header `json:",omitempty"`
+	Body       string      `json:",omitempty"`
+}
+
+// LoadCassette reads a cassette previously written by a Recorder.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path as indented JSON.
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// sanitizeHeader copies h, masking the values of any sensitive headers.
+// Content-Length is dropped because masking can change the body length.
+func sanitizeHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		out[k] = append([]string(nil), v...)
+	}
+	out.Del("Content-Length")
+	for _, name := range redact.Headers {
+		if _, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			out.Set(name, redact.Mask)
+		}
+	}
+	return out
+}
+
+// sanitizeBody masks any sensitive JSON fields in b.
+func sanitizeBody(b []byte) string {
+	return redact.String(string(b))
+}
+
+// sanitizeResponseBody is like sanitizeBody but keeps certificate and vault
+// data decodable, so that replayed responses can still be parsed. See
+// placeholderData.
+func sanitizeResponseBody(b []byte) string {
+	return redact.ReplaceFields(string(b), func(name string, value string) string {
+		switch name {
+		case "CertificateData", "Base64Data":
+			return placeholderData(value)
+		}
+		return redact.Mask
+	})
+}
+
+// placeholderData sanitizes value, base64 data returned by TPP. Certificates
+// are public and kept as they are. Private keys, alone or in a PEM bundle
+// with their certificate, are replaced by placeholderKey. Anything else
+// becomes the base64 encoding of redact.Mask.
+func placeholderData(value string) string {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return redact.Mask
+	}
+	masked := base64.StdEncoding.EncodeToString([]byte(redact.Mask))
+
+	if _, err := x509.ParseCertificate(data); err == nil {
+		return value
+	}
+	if _, err := x509.ParsePKCS8PrivateKey(data); err == nil {
+		return base64.StdEncoding.EncodeToString(placeholderKey())
+	}
+
+	var out bytes.Buffer
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		switch {
+		case block.Type == "CERTIFICATE":
+			pem.Encode(&out, &pem.Block{Type: block.Type, Bytes: block.Bytes})
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			pem.Encode(&out, &pem.Block{Type: "PRIVATE KEY", Bytes: placeholderKey()})
+		}
+	}
+	if out.Len() == 0 {
+		return masked
+	}
+	return base64.StdEncoding.EncodeToString(out.Bytes())
+}
+
+var (
+	placeholderOnce sync.Once
+	placeholderDER  []byte
+)
+
+// placeholderKey returns a PKCS#8 key, generated once per process, that
+// stands in for recorded private keys.
+func placeholderKey() []byte {
+	placeholderOnce.Do(func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		placeholderDER, err = x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return placeholderDER
+}
+
+// readBody reads and replaces *body so that it can be read again.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	b, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, err
+}
+
+// canonicalJSON re-encodes s with sorted keys so that bodies can be compared
+// regardless of field order. Non-JSON input is returned unchanged.
+func canonicalJSON(s string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
+
+// canonicalURL joins path and query with the query parameters sorted, so that
+// recorded URLs do not depend on the server address or parameter order.
+func canonicalURL(path string, query map[string][]string) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString(path)
+	for i, k := range keys {
+		if i == 0 {
+			buf.WriteByte('?')
+		} else {
+			buf.WriteByte('&')
+		}
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for j, v := range values {
+			if j > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(k + "=" + v)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,320 @@
+package venafitest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/pkg/pem"
+)
+
+type certEntry struct {
+	cert       *x509.Certificate
+	key        crypto.Signer
+	vaultID    int
+	keyVaultID int
+	createdOn  time.Time
+}
+
+func (s *Server) certificate(obj *object, entry *certEntry) venafi.Certificate {
+	c := venafi.Certificate{
+		CreatedOn:  entry.createdOn,
+		ObjectDN:   obj.DN,
+		ObjectGUID: obj.GUID,
+		Name:       obj.Name,
+		ParentDN:   obj.Parent,
+		Class:      obj.Class,
+	}
+	if entry.cert != nil {
+		sum := sha1.Sum(entry.cert.Raw)
+		c.X509 = venafi.X509Data{
+			CommonName: entry.cert.Subject.CommonName,
+			AltNames:   map[string][]string{"DNS": entry.cert.DNSNames},
+			Serial:     strings.ToUpper(entry.cert.SerialNumber.Text(16)),
+			Thumbprint: strings.ToUpper(hex.EncodeToString(sum[:])),
+			ValidFrom:  entry.cert.NotBefore,
+			ValidTo:    entry.cert.NotAfter,
+		}
+	}
+	return c
+}
+
+func (s *Server) handleCertificatesList(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all := make([]venafi.Certificate, 0)
+	for _, obj := range s.children(`\VED`, true) {
+		if entry, ok := s.certs[dnKey(obj.DN)]; ok {
+			all = append(all, s.certificate(obj, entry))
+		}
+	}
+
+	page := make([]venafi.Certificate, 0)
+	if offset < len(all) {
+		end := offset + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		page = all[offset:end]
+	}
+
+	output := map[string]interface{}{
+		"Certificates": page,
+		"DataRange":    "Certificates " + strconv.Itoa(offset+1) + " - " + strconv.Itoa(offset+len(page)),
+		"TotalCount":   len(all),
+	}
+	if offset+limit < len(all) {
+		next := "certificates/?limit=" + strconv.Itoa(limit) + "&offset=" + strconv.Itoa(offset+limit)
+		output["_links"] = []map[string]string{{"Next": next}}
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
+
+func (s *Server) handleCertificatesRequest(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		PolicyDN        string
+		ObjectName      string
+		Subject         string
+		SubjectAltNames []struct {
+			Type int
+			Name string
+		}
+		ValidityPeriod int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	name := input.ObjectName
+	if name == "" {
+		name = input.Subject
+	}
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "ObjectName or Subject is required")
+		return
+	}
+	cn := input.Subject
+	if cn == "" {
+		cn = name
+	}
+	var dnsNames []string
+	for _, san := range input.SubjectAltNames {
+		if san.Type == 2 {
+			dnsNames = append(dnsNames, san.Name)
+		}
+	}
+	days := input.ValidityPeriod
+	if days <= 0 {
+		days = 365
+	}
+
+	cert, key, err := newCertificate(cn, dnsNames, days)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.storeCertificate(w, input.PolicyDN, name, cert, key)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"CertificateDN": obj.DN,
+		"Guid":          obj.GUID,
+	})
+}
+
+func (s *Server) handleCertificatesRetrieve(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		CertificateDN     string
+		Format            string
+		IncludeChain      bool
+		IncludePrivateKey bool
+		Password          string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	entry, ok := s.certs[dnKey(input.CertificateDN)]
+	s.mu.Unlock()
+	if !ok || entry.cert == nil {
+		writeError(w, http.StatusBadRequest, "Failed to lookup certificate: "+input.CertificateDN)
+		return
+	}
+
+	data, err := pem.EncodeCert(entry.cert)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if input.IncludePrivateKey && entry.key != nil {
+		keyPEM, err := pem.EncodeKey(entry.key, input.Password, x509.PEMCipher3DES)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		data += keyPEM
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"CertificateData": base64.StdEncoding.EncodeToString([]byte(data)),
+		"Filename":        dnName(input.CertificateDN) + ".pem",
+		"Format":          input.Format,
+	})
+}
+
+func (s *Server) handleCertificatesImport(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		PolicyDN        string
+		ObjectName      string
+		CertificateData string
+		Password        string
+		PrivateKeyData  string
+		Reconcile       bool
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	var cert *x509.Certificate
+	var key crypto.Signer
+	var err error
+	if input.PrivateKeyData != "" {
+		cert, key, err = pem.DecodeCertAndPrivateKey([]byte(input.CertificateData+input.PrivateKeyData), input.Password)
+	} else {
+		cert, err = pem.DecodeCertString(input.CertificateData)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to parse certificate: "+err.Error())
+		return
+	}
+
+	name := input.ObjectName
+	if name == "" {
+		name = cert.Subject.CommonName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, exists := s.objects[dnKey(input.PolicyDN+`\`+name)]
+	if exists {
+		entry, isCert := s.certs[dnKey(obj.DN)]
+		if !isCert || !input.Reconcile {
+			writeError(w, http.StatusBadRequest, "Certificate already exists: "+obj.DN)
+			return
+		}
+		entry.cert, entry.key = cert, key
+		if vault, ok := s.vault[entry.vaultID]; ok {
+			vault.data = cert.Raw
+		}
+		s.touch(obj)
+	} else {
+		var ok bool
+		if obj, ok = s.storeCertificate(w, input.PolicyDN, name, cert, key); !ok {
+			return
+		}
+	}
+	entry := s.certs[dnKey(obj.DN)]
+
+	output := map[string]interface{}{
+		"CertificateDN":      obj.DN,
+		"CertificateVaultId": entry.vaultID,
+		"Guid":               obj.GUID,
+	}
+	if entry.keyVaultID != 0 {
+		output["PrivateKeyVaultId"] = entry.keyVaultID
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
+
+// storeCertificate creates a certificate object under policyDN and adds the
+// certificate to the vault. The caller must hold s.mu.
+func (s *Server) storeCertificate(w http.ResponseWriter, policyDN string, name string,
+	cert *x509.Certificate, key crypto.Signer) (*object, bool) {
+	if _, ok := s.objects[dnKey(policyDN)]; !ok {
+		writeError(w, http.StatusBadRequest, "Policy folder does not exist: "+policyDN)
+		return nil, false
+	}
+
+	dn := policyDN + `\` + name
+	if _, exists := s.objects[dnKey(dn)]; exists {
+		writeError(w, http.StatusBadRequest, "Certificate already exists: "+dn)
+		return nil, false
+	}
+
+	obj := s.addObject(dn, config.ClassX509Certificate, nil)
+	entry := &certEntry{cert: cert, key: key, createdOn: time.Now().UTC()}
+	entry.vaultID = s.addVaultEntry(cert.Raw, dn)
+	if key != nil {
+		entry.keyVaultID = s.nextVID
+		s.nextVID++
+	}
+	obj.attrs["Certificate Vault Id"] = []string{strconv.Itoa(entry.vaultID)}
+	s.certs[dnKey(dn)] = entry
+
+	return obj, true
+}
+
+// newCertificate generates a self-signed certificate and ECDSA key.
+func newCertificate(commonName string, dnsNames []string, validityDays int) (*x509.Certificate, crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    now,
+		NotAfter:     now.AddDate(0, 0, validityDays),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
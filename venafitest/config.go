@@ -0,0 +1,716 @@
+package venafitest
+
+import (
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+type object struct {
+	venafi.ConfigObject
+	attrs    map[string][]string
+	policies map[string]map[string]*policyAttr
+	perms    map[string]grant
+}
+
+func (o *object) info() venafi.ConfigObject {
+	info := o.ConfigObject
+	info.AttributeList = nil
+	return info
+}
+
+// AddObject creates an object directly in the fake's tree, bypassing the API.
+// Missing parents are not created.
+func (s *Server) AddObject(dn string, class string, attributes map[string][]string) venafi.ConfigObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addObject(dn, class, attributes).info()
+}
+
+// Object returns the attributes of the object at dn and whether it exists.
+func (s *Server) Object(dn string) (map[string][]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[dnKey(dn)]
+	if !ok {
+		return nil, false
+	}
+	attrs := make(map[string][]string, len(obj.attrs))
+	for k, v := range obj.attrs {
+		attrs[k] = append([]string(nil), v...)
+	}
+	return attrs, true
+}
+
+// addObject inserts an object into the tree. The caller must hold s.mu.
+func (s *Server) addObject(dn string, class string, attributes map[string][]string) *object {
+	s.revision++
+	obj := &object{
+		ConfigObject: venafi.ConfigObject{
+			DN:       dn,
+			GUID:     newGUID(),
+			Id:       s.nextID,
+			Name:     dnName(dn),
+			Parent:   dnParent(dn),
+			Revision: s.revision,
+			Class:    class,
+		},
+		attrs: make(map[string][]string),
+	}
+	for k, v := range attributes {
+		obj.attrs[k] = append([]string(nil), v...)
+	}
+	s.nextID++
+	s.objects[dnKey(dn)] = obj
+	return obj
+}
+
+// touch bumps the revision of obj after a change. The caller must hold s.mu.
+func (s *Server) touch(obj *object) {
+	s.revision++
+	obj.Revision = s.revision
+}
+
+// children returns the objects directly or indirectly below dn, sorted by DN.
+// The caller must hold s.mu.
+func (s *Server) children(dn string, recursive bool) []*object {
+	prefix := dnKey(dn) + `\`
+	rv := make([]*object, 0)
+	for key, obj := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if !recursive && strings.Contains(key[len(prefix):], `\`) {
+			continue
+		}
+		rv = append(rv, obj)
+	}
+	sort.Slice(rv, func(i, j int) bool { return dnKey(rv[i].DN) < dnKey(rv[j].DN) })
+	return rv
+}
+
+func (s *Server) lookup(w http.ResponseWriter, dn string, guid string) (*object, bool) {
+	if dn == "" && guid != "" {
+		for _, obj := range s.objects {
+			if strings.EqualFold(obj.GUID, guid) {
+				return obj, true
+			}
+		}
+	} else if obj, ok := s.objects[dnKey(dn)]; ok {
+		return obj, true
+	}
+	writeConfigResult(w, config.ObjectDoesNotExist, "Object does not exist", nil)
+	return nil, false
+}
+
+func (s *Server) handleConfigCreate(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Class             string
+		ObjectDN          string
+		NameAttributeList []struct {
+			Name  string
+			Value string
+		}
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.objects[dnKey(input.ObjectDN)]; exists {
+		writeConfigResult(w, config.ObjectAlreadyExists, "Object already exists", nil)
+		return
+	}
+	if _, ok := s.objects[dnKey(dnParent(input.ObjectDN))]; !ok {
+		writeConfigResult(w, config.ObjectDoesNotExist, "Parent object does not exist", nil)
+		return
+	}
+
+	attrs := make(map[string][]string)
+	for _, pair := range input.NameAttributeList {
+		attrs[pair.Name] = append(attrs[pair.Name], pair.Value)
+	}
+	obj := s.addObject(input.ObjectDN, input.Class, attrs)
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"Object": obj.info()})
+}
+
+func (s *Server) handleConfigIsValid(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN   string
+		ObjectGUID string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, input.ObjectGUID)
+	if !ok {
+		return
+	}
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"Object": obj.info()})
+}
+
+func (s *Server) handleConfigDefaultDN(w http.ResponseWriter, r *http.Request) {
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"DefaultDN": `\VED\Policy`})
+}
+
+func (s *Server) handleConfigDelete(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN  string
+		Recursive int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	children := s.children(obj.DN, true)
+	if len(children) > 0 && input.Recursive == 0 {
+		writeConfigResult(w, config.ObjectHasChildren, "Object has children", nil)
+		return
+	}
+	for _, child := range children {
+		delete(s.objects, dnKey(child.DN))
+	}
+	// Like TPP, deleting an object does not move the revision of its parent.
+	delete(s.objects, dnKey(obj.DN))
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+func (s *Server) handleConfigEnumerate(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN  string
+		Recursive string
+		Pattern   string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	recursive, _ := strconv.ParseBool(input.Recursive)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookup(w, input.ObjectDN, ""); !ok {
+		return
+	}
+
+	objects := make([]venafi.ConfigObject, 0)
+	for _, obj := range s.children(input.ObjectDN, recursive) {
+		if matchPattern(input.Pattern, obj.Name) {
+			objects = append(objects, obj.info())
+		}
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"Objects": objects})
+}
+
+func (s *Server) handleConfigAddValue(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		AttributeName string
+		Value         string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	for _, v := range obj.attrs[input.AttributeName] {
+		if v == input.Value {
+			writeConfigResult(w, config.AttributeValueExists, "Attribute value already exists", nil)
+			return
+		}
+	}
+	obj.attrs[input.AttributeName] = append(obj.attrs[input.AttributeName], input.Value)
+	s.touch(obj)
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+func (s *Server) handleConfigClearAttribute(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		AttributeName string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	delete(obj.attrs, input.AttributeName)
+	s.touch(obj)
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+func (s *Server) handleConfigRemoveAttributeValues(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		AttributeName string
+		Values        []string
+		Value         string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	if input.Value != "" {
+		input.Values = append(input.Values, input.Value)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	current := obj.attrs[input.AttributeName]
+	for _, v := range input.Values {
+		found := false
+		for i := range current {
+			if strings.EqualFold(current[i], v) {
+				current = append(current[:i:i], current[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			writeConfigResult(w, config.AttributeValueDoesNotExist, "Attribute value does not exist", nil)
+			return
+		}
+	}
+	obj.attrs[input.AttributeName] = current
+	s.touch(obj)
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+func (s *Server) handleConfigWriteDn(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		AttributeName string
+		Values        []string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	for _, v := range input.Values {
+		if _, ok := s.objects[dnKey(v)]; !ok {
+			writeConfigResult(w, config.InvalidAttributeDN, "Invalid attribute DN", nil)
+			return
+		}
+	}
+	obj.attrs[input.AttributeName] = append([]string(nil), input.Values...)
+	s.touch(obj)
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+func (s *Server) handleConfigGetRevision(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"Revision": obj.Revision})
+}
+
+func (s *Server) handleConfigRead(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		AttributeName string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	values, ok := obj.attrs[input.AttributeName]
+	if !ok {
+		values = []string{}
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{
+		"ObjectDN":      obj.DN,
+		"AttributeName": input.AttributeName,
+		"Values":        values,
+	})
+}
+
+func (s *Server) handleConfigReadAll(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+
+	type nameValues struct {
+		Name   string
+		Values []string
+	}
+	pairs := make([]nameValues, 0, len(obj.attrs))
+	for k, v := range obj.attrs {
+		pairs = append(pairs, nameValues{k, v})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"NameValues": pairs})
+}
+
+func (s *Server) handleConfigWrite(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		AttributeData []struct {
+			Name  string
+			Value []string
+		}
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	for _, pair := range input.AttributeData {
+		obj.attrs[pair.Name] = append([]string(nil), pair.Value...)
+	}
+	s.touch(obj)
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+func (s *Server) handleConfigFind(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Pattern        string
+		AttributeNames []string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objects := make([]venafi.ConfigObject, 0)
+	for _, obj := range s.children(`\VED`, true) {
+		if len(input.AttributeNames) == 0 {
+			if matchPattern(input.Pattern, obj.Name) {
+				objects = append(objects, obj.info())
+			}
+			continue
+		}
+	attributes:
+		for _, name := range input.AttributeNames {
+			for _, v := range obj.attrs[name] {
+				if matchPattern(input.Pattern, v) {
+					objects = append(objects, obj.info())
+					break attributes
+				}
+			}
+		}
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"Objects": objects})
+}
+
+func (s *Server) handleConfigFindObjectsOfClass(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Class     string
+		Classes   string
+		ObjectDN  string
+		Recursive int
+		Pattern   string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	classes := make(map[string]bool)
+	for _, class := range strings.Split(input.Classes+","+input.Class, ",") {
+		if class = strings.TrimSpace(class); class != "" {
+			classes[strings.ToLower(class)] = true
+		}
+	}
+	root := input.ObjectDN
+	recursive := input.Recursive != 0
+	if root == "" {
+		root, recursive = `\VED`, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookup(w, root, ""); !ok {
+		return
+	}
+
+	objects := make([]venafi.ConfigObject, 0)
+	for _, obj := range s.children(root, recursive) {
+		if classes[strings.ToLower(obj.Class)] && (input.Pattern == "" || matchPattern(input.Pattern, obj.Name)) {
+			objects = append(objects, obj.info())
+		}
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"Objects": objects})
+}
+
+func (s *Server) handleConfigFindContainers(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN  string
+		Recursive int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookup(w, input.ObjectDN, ""); !ok {
+		return
+	}
+
+	objects := make([]venafi.ConfigObject, 0)
+	for _, obj := range s.children(input.ObjectDN, input.Recursive != 0) {
+		if containerClasses[obj.Class] {
+			objects = append(objects, obj.info())
+		}
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"Objects": objects})
+}
+
+func (s *Server) handleConfigDnToGuid(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	writeConfigResult(w, config.Success, "", map[string]interface{}{
+		"ClassName": obj.Class,
+		"GUID":      obj.GUID,
+		"Revision":  obj.Revision,
+	})
+}
+
+func (s *Server) handleConfigGuidToDn(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectGUID string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, "", input.ObjectGUID)
+	if !ok {
+		return
+	}
+	writeConfigResult(w, config.Success, "", map[string]interface{}{
+		"ClassName": obj.Class,
+		"ObjectDN":  obj.DN,
+		"Revision":  obj.Revision,
+	})
+}
+
+func (s *Server) handleConfigIdInfo(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectID string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *object
+	for _, obj := range s.objects {
+		if strings.EqualFold(obj.GUID, input.ObjectID) || strconv.Itoa(obj.Id) == input.ObjectID {
+			found = obj
+			break
+		}
+	}
+	if found == nil {
+		writeConfigResult(w, config.ObjectDoesNotExist, "Object does not exist", nil)
+		return
+	}
+	writeConfigResult(w, config.Success, "", map[string]interface{}{
+		"ClassName": found.Class,
+		"GUID":      found.GUID,
+		"Revision":  found.Revision,
+	})
+}
+
+func (s *Server) handleConfigRenameObject(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN    string
+		NewObjectDN string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	if _, exists := s.objects[dnKey(input.NewObjectDN)]; exists {
+		writeConfigResult(w, config.ObjectAlreadyExists, "Object already exists", nil)
+		return
+	}
+	if _, ok := s.objects[dnKey(dnParent(input.NewObjectDN))]; !ok {
+		writeConfigResult(w, config.ObjectDoesNotExist, "Parent object does not exist", nil)
+		return
+	}
+
+	oldDN := obj.DN
+	for _, moved := range append(s.children(oldDN, true), obj) {
+		oldKey := dnKey(moved.DN)
+		moved.DN = input.NewObjectDN + moved.DN[len(oldDN):]
+		moved.Name = dnName(moved.DN)
+		moved.Parent = dnParent(moved.DN)
+		s.touch(moved)
+
+		delete(s.objects, oldKey)
+		s.objects[dnKey(moved.DN)] = moved
+		if entry, ok := s.certs[oldKey]; ok {
+			delete(s.certs, oldKey)
+			s.certs[dnKey(moved.DN)] = entry
+		}
+	}
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+func writeConfigResult(w http.ResponseWriter, result config.ConfigResult, message string, fields map[string]interface{}) {
+	body := map[string]interface{}{"Result": result}
+	if message != "" {
+		body["Error"] = message
+	}
+	for k, v := range fields {
+		body[k] = v
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// containerClasses are the classes the fake treats as containers.
+var containerClasses = map[string]bool{
+	"Top":              true,
+	config.ClassPolicy: true,
+	config.ClassDevice: true,
+}
+
+// matchPattern reports whether value matches a TPP wildcard pattern, ignoring
+// case. An empty pattern matches everything.
+func matchPattern(pattern string, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return matched
+}
+
+func dnKey(dn string) string {
+	return strings.ToLower(strings.TrimSuffix(dn, `\`))
+}
+
+func dnParent(dn string) string {
+	i := strings.LastIndex(dn, `\`)
+	if i <= 0 {
+		return ""
+	}
+	return dn[:i]
+}
+
+func dnName(dn string) string {
+	return dn[strings.LastIndex(dn, `\`)+1:]
+}
@@ -0,0 +1,48 @@
+package venafitest
+
+import (
+	"net/http"
+
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+// AddProtectionKey makes key available for encrypting vault entries, as if
+// an HSM key had been configured. Software:Default is always available.
+func (s *Server) AddProtectionKey(key secret_store.ProtectionKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.hasProtectionKey(key) {
+		s.keys = append(s.keys, key)
+	}
+}
+
+// hasProtectionKey reports whether key can encrypt vault entries. The Null
+// key, which leaves them unencrypted, is always accepted. The caller must
+// hold s.mu.
+func (s *Server) hasProtectionKey(key secret_store.ProtectionKey) bool {
+	if key == secret_store.ProtectionKeyNull {
+		return true
+	}
+	for _, k := range s.keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleCryptoAvailableKeys(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"Keynames": append([]secret_store.ProtectionKey{}, s.keys...),
+	})
+}
+
+func (s *Server) handleCryptoGetDefaultKey(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"DefaultKey": secret_store.ProtectionKeyDefault,
+	})
+}
@@ -0,0 +1,81 @@
+package venafitest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+// Fault describes a failure to inject into calls to one endpoint. A Fault with
+// only Latency set delays the call and then lets it through.
+type Fault struct {
+	// StatusCode and Body are written in place of the real response when
+	// StatusCode is non-zero.
+	StatusCode int
+	Body       interface{}
+
+	// Latency delays the response, overriding the server-wide latency.
+	Latency time.Duration
+
+	// Times is the number of calls the fault applies to. Zero means every
+	// call until the fault is cleared.
+	Times int
+}
+
+// HTTPFault returns a fault that fails with the given HTTP status and a TPP
+// style {"Error": message} body.
+func HTTPFault(status int, message string) Fault {
+	return Fault{StatusCode: status, Body: map[string]string{"Error": message}}
+}
+
+// ConfigFault returns a fault that reports a Config result code, the way TPP
+// does for failed Config calls.
+func ConfigFault(result config.ConfigResult, message string) Fault {
+	return Fault{StatusCode: http.StatusOK, Body: map[string]interface{}{"Result": result, "Error": message}}
+}
+
+// StoreFault returns a fault that reports a secret store result code, the way
+// TPP does for failed X509CertificateStore calls.
+func StoreFault(result secret_store.SecretStoreResult) Fault {
+	return Fault{StatusCode: http.StatusOK, Body: map[string]interface{}{"Result": result}}
+}
+
+// Inject arranges for calls to path (for example "/vedsdk/Config/Create") to
+// fail as described by f. Paths are matched case-insensitively.
+func (s *Server) Inject(path string, f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[normalizePath(path)] = &f
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = make(map[string]*Fault)
+}
+
+// SetLatency delays every response by d. Use zero to turn latency off.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// takeFault returns the fault registered for path, if any, and counts down its
+// remaining uses. The caller must hold s.mu.
+func (s *Server) takeFault(path string) *Fault {
+	f, ok := s.faults[path]
+	if !ok {
+		return nil
+	}
+	if f.Times > 0 {
+		f.Times--
+		if f.Times == 0 {
+			delete(s.faults, path)
+		}
+	}
+	return f
+}
@@ -0,0 +1,249 @@
+package venafitest
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	venafi "github.com/tradel/venafi-tpp"
+)
+
+// localGroup returns the directory entry for a local group, writing an error
+// if there is none. The caller must hold s.mu.
+func (s *Server) localGroup(w http.ResponseWriter, id venafi.Identity) *directoryEntry {
+	entry := s.findIdentity(id)
+	if entry == nil || !entry.IsGroup || entry.Prefix != "local" {
+		writeError(w, http.StatusBadRequest, "Group does not exist or is not a local group")
+		return nil
+	}
+	return entry
+}
+
+// members returns the identities in a group, expanding nested groups if
+// resolveNested is set. The caller must hold s.mu.
+func (s *Server) members(group *directoryEntry, resolveNested bool) []venafi.Identity {
+	seen := map[string]bool{strings.ToLower(group.PrefixedUniversal): true}
+	rv := make([]venafi.Identity, 0)
+	var walk func(g *directoryEntry)
+	walk = func(g *directoryEntry) {
+		for _, key := range g.members {
+			member := s.findIdentity(venafi.Identity{PrefixedUniversal: key})
+			if member == nil || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if resolveNested && member.IsGroup {
+				walk(member)
+				continue
+			}
+			rv = append(rv, member.Identity)
+		}
+	}
+	walk(group)
+	return rv
+}
+
+func (s *Server) handleIdentityGetMembers(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ID            venafi.Identity
+		ResolveNested int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.findIdentity(input.ID)
+	if entry == nil || !entry.IsGroup {
+		writeError(w, http.StatusBadRequest, "Group does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Identities": s.members(entry, input.ResolveNested != 0)})
+}
+
+func (s *Server) handleIdentityGetMemberships(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ID venafi.Identity
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.findIdentity(input.ID)
+	if entry == nil {
+		writeError(w, http.StatusBadRequest, "Failed to validate identity")
+		return
+	}
+	identities := make([]venafi.Identity, 0)
+	for _, group := range s.directory() {
+		if containsFold(group.members, entry.PrefixedUniversal) {
+			identities = append(identities, group.Identity)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Identities": identities})
+}
+
+func (s *Server) handleIdentityAddGroup(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name    venafi.Identity
+		Members []venafi.Identity
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	name := strings.TrimPrefix(input.Name.PrefixedName, "local:")
+
+	s.mu.Lock()
+	if s.findIdentity(venafi.Identity{PrefixedName: "local:" + name}) != nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, "Group already exists")
+		return
+	}
+	// Like TPP, create the group with the members that exist and report the
+	// rest.
+	members := make([]venafi.Identity, 0, len(input.Members))
+	invalid := make([]venafi.Identity, 0)
+	for _, member := range input.Members {
+		if s.findIdentity(member) == nil {
+			invalid = append(invalid, member)
+		} else {
+			members = append(members, member)
+		}
+	}
+	s.mu.Unlock()
+
+	group := s.AddGroup(name, members...)
+	output := map[string]interface{}{"ID": group}
+	if len(invalid) > 0 {
+		output["InvalidMembers"] = invalid
+	}
+	writeJSON(w, http.StatusOK, output)
+}
+
+func (s *Server) handleIdentityAddGroupMembers(w http.ResponseWriter, r *http.Request) {
+	s.updateGroupMembers(w, r, true, func(group *directoryEntry, key string) {
+		if !containsFold(group.members, key) {
+			group.members = append(group.members, key)
+		}
+	})
+}
+
+func (s *Server) handleIdentityRemoveGroupMembers(w http.ResponseWriter, r *http.Request) {
+	s.updateGroupMembers(w, r, false, func(group *directoryEntry, key string) {
+		for i, member := range group.members {
+			if member == key {
+				group.members = append(group.members[:i:i], group.members[i+1:]...)
+				break
+			}
+		}
+	})
+}
+
+// updateGroupMembers applies update to each member in the request. Unknown
+// members fail the request, unless reportInvalid is set, in which case they
+// are skipped and listed in InvalidMembers as TPP does when adding members.
+func (s *Server) updateGroupMembers(w http.ResponseWriter, r *http.Request, reportInvalid bool,
+	update func(group *directoryEntry, key string)) {
+	var input struct {
+		Group   venafi.Identity
+		Members []venafi.Identity
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group := s.localGroup(w, input.Group)
+	if group == nil {
+		return
+	}
+	members := make([]venafi.Identity, 0, len(input.Members))
+	invalid := make([]venafi.Identity, 0)
+	for _, m := range input.Members {
+		if s.findIdentity(m) == nil {
+			if !reportInvalid {
+				writeError(w, http.StatusBadRequest, "Member does not exist")
+				return
+			}
+			invalid = append(invalid, m)
+		}
+	}
+	for _, m := range input.Members {
+		if member := s.findIdentity(m); member != nil {
+			update(group, strings.ToLower(member.PrefixedUniversal))
+			members = append(members, member.Identity)
+		}
+	}
+
+	output := map[string]interface{}{"Members": members}
+	if len(invalid) > 0 {
+		output["InvalidMembers"] = invalid
+	}
+	writeJSON(w, http.StatusOK, output)
+}
+
+func (s *Server) handleIdentityRenameGroup(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Group        venafi.Identity
+		NewGroupName string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group := s.localGroup(w, input.Group)
+	if group == nil {
+		return
+	}
+	if s.findIdentity(venafi.Identity{PrefixedName: "local:" + input.NewGroupName}) != nil {
+		writeError(w, http.StatusConflict, "Group already exists")
+		return
+	}
+	group.Name = input.NewGroupName
+	group.FullName = `\VED\Identity\` + input.NewGroupName
+	group.PrefixedName = "local:" + input.NewGroupName
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"ID": group.Identity})
+}
+
+func (s *Server) handleIdentityDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+	if len(parts) < 2 {
+		writeError(w, http.StatusBadRequest, "Missing group")
+		return
+	}
+	prefix, _ := url.PathUnescape(parts[len(parts)-2])
+	universal, _ := url.PathUnescape(parts[len(parts)-1])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group := s.localGroup(w, venafi.Identity{PrefixedUniversal: prefix + ":" + universal})
+	if group == nil {
+		return
+	}
+	key := strings.ToLower(group.PrefixedUniversal)
+	delete(s.users, key)
+	for _, entry := range s.users {
+		for i, member := range entry.members {
+			if member == key {
+				entry.members = append(entry.members[:i:i], entry.members[i+1:]...)
+				break
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Success": true})
+}
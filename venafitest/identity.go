@@ -0,0 +1,234 @@
+package venafitest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	venafi "github.com/tradel/venafi-tpp"
+)
+
+// directoryEntry is an identity in the fake's directory. Members holds the
+// lowercased prefixed universal IDs of a group's direct members.
+type directoryEntry struct {
+	venafi.Identity
+	attrs   map[string][]string
+	members []string
+}
+
+// AddIdentity adds id to the fake's identity directory with the given
+// provider attributes, replacing any identity with the same prefixed
+// universal ID.
+func (s *Server) AddIdentity(id venafi.Identity, attrs map[string][]string) venafi.Identity {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id.Type == 0 {
+		id.Type = venafi.IdentityUser
+		if id.IsGroup {
+			id.Type = venafi.IdentitySecurityGroup
+		}
+	}
+	s.users[strings.ToLower(id.PrefixedUniversal)] = &directoryEntry{Identity: id, attrs: attrs}
+	return id
+}
+
+// AddUser adds a local user called name to the fake's identity directory.
+func (s *Server) AddUser(name string, attrs map[string][]string) venafi.Identity {
+	return s.AddIdentity(localIdentity(name, false), attrs)
+}
+
+// AddGroup adds a local security group called name containing members, which
+// may be users or other groups.
+func (s *Server) AddGroup(name string, members ...venafi.Identity) venafi.Identity {
+	id := s.AddIdentity(localIdentity(name, true), nil)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.users[strings.ToLower(id.PrefixedUniversal)]
+	for _, member := range members {
+		entry.members = append(entry.members, strings.ToLower(member.PrefixedUniversal))
+	}
+	return id
+}
+
+func localIdentity(name string, group bool) venafi.Identity {
+	return venafi.Identity{
+		FullName:          `\VED\Identity\` + name,
+		IsGroup:           group,
+		Name:              name,
+		Prefix:            "local",
+		PrefixedName:      "local:" + name,
+		PrefixedUniversal: "local:{" + name + "}",
+		Universal:         "{" + name + "}",
+	}
+}
+
+// findIdentity returns the directory entry for id, matching on prefixed
+// universal ID, prefixed name or name. The session's own Identity is always
+// present. The caller must hold s.mu.
+func (s *Server) findIdentity(id venafi.Identity) *directoryEntry {
+	for _, entry := range s.directory() {
+		if (id.PrefixedUniversal != "" && strings.EqualFold(id.PrefixedUniversal, entry.PrefixedUniversal)) ||
+			(id.PrefixedName != "" && strings.EqualFold(id.PrefixedName, entry.PrefixedName)) ||
+			(id.Name != "" && strings.EqualFold(id.Name, entry.Name)) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// directory returns every identity known to the fake, sorted by prefixed
+// name. The caller must hold s.mu.
+func (s *Server) directory() []*directoryEntry {
+	rv := make([]*directoryEntry, 0, len(s.users)+1)
+	if _, ok := s.users[strings.ToLower(s.Identity.PrefixedUniversal)]; !ok {
+		rv = append(rv, &directoryEntry{Identity: s.Identity})
+	}
+	for _, entry := range s.users {
+		rv = append(rv, entry)
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		return strings.ToLower(rv[i].PrefixedName) < strings.ToLower(rv[j].PrefixedName)
+	})
+	return rv
+}
+
+// groupsOf returns the groups that contain key directly or through nested
+// groups. The caller must hold s.mu.
+func (s *Server) groupsOf(key string) []*directoryEntry {
+	seen := make(map[string]bool)
+	queue := []string{strings.ToLower(key)}
+	rv := make([]*directoryEntry, 0)
+	for len(queue) > 0 {
+		member := queue[0]
+		queue = queue[1:]
+		for _, entry := range s.directory() {
+			group := strings.ToLower(entry.PrefixedUniversal)
+			if seen[group] || !containsFold(entry.members, member) {
+				continue
+			}
+			seen[group] = true
+			rv = append(rv, entry)
+			queue = append(queue, group)
+		}
+	}
+	return rv
+}
+
+func (s *Server) handleIdentitySelf(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Like TPP, list the groups the session belongs to alongside the user.
+	identities := []venafi.Identity{s.Identity}
+	for _, group := range s.groupsOf(s.Identity.PrefixedUniversal) {
+		identities = append(identities, group.Identity)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Identities": identities})
+}
+
+func (s *Server) handleIdentityValidate(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ID venafi.Identity
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	entry := s.findIdentity(input.ID)
+	s.mu.Unlock()
+
+	if entry == nil {
+		writeError(w, http.StatusBadRequest, "Failed to validate identity")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"ID": entry.Identity})
+}
+
+func (s *Server) handleIdentityBrowse(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Filter       string
+		Limit        int
+		IdentityType venafi.IdentityType
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filter := strings.ToLower(input.Filter)
+	identities := make([]venafi.Identity, 0)
+	for _, entry := range s.directory() {
+		if input.IdentityType != 0 && input.IdentityType&entry.Type == 0 {
+			continue
+		}
+		if !strings.HasPrefix(strings.ToLower(entry.Name), filter) &&
+			!strings.HasPrefix(strings.ToLower(entry.FullName), filter) {
+			continue
+		}
+		if input.Limit > 0 && len(identities) >= input.Limit {
+			break
+		}
+		identities = append(identities, entry.Identity)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Identities": identities})
+}
+
+func (s *Server) handleIdentityGetAssociatedEntries(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ID venafi.Identity
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.findIdentity(input.ID)
+	if entry == nil {
+		writeError(w, http.StatusBadRequest, "Failed to validate identity")
+		return
+	}
+	identities := make([]venafi.Identity, 0)
+	for _, group := range s.groupsOf(entry.PrefixedUniversal) {
+		identities = append(identities, group.Identity)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Identities": identities})
+}
+
+func (s *Server) handleIdentityReadAttribute(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ID            venafi.Identity
+		AttributeName string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.findIdentity(input.ID)
+	if entry == nil {
+		writeError(w, http.StatusBadRequest, "Failed to validate identity")
+		return
+	}
+	values := make([]string, 0)
+	for name, v := range entry.attrs {
+		if strings.EqualFold(name, input.AttributeName) {
+			values = append(values, v...)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Attributes": values})
+}
@@ -0,0 +1,158 @@
+package venafitest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	venafi "github.com/tradel/venafi-tpp"
+)
+
+// SetPermissions sets the explicit permissions principal has on the object at
+// dn, bypassing the API.
+func (s *Server) SetPermissions(dn string, principal venafi.Identity, perms venafi.Permissions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if obj, ok := s.objects[dnKey(dn)]; ok {
+		obj.setPermissions(principal.PrefixedUniversal, perms)
+	}
+}
+
+// grant is an explicit permission entry on an object.
+type grant struct {
+	principal string
+	perms     venafi.Permissions
+}
+
+func (o *object) setPermissions(principal string, perms venafi.Permissions) {
+	if o.perms == nil {
+		o.perms = make(map[string]grant)
+	}
+	o.perms[strings.ToLower(principal)] = grant{principal, perms}
+}
+
+// permissionsRequest is a parsed /Permissions/Object path. principal is empty
+// when only the object is named.
+type permissionsRequest struct {
+	obj       *object
+	principal string
+	effective bool
+}
+
+// parsePermissionsPath reads the object GUID, principal and Effective suffix
+// from a path such as /vedsdk/Permissions/Object/{guid}/AD/corp/{id}/Effective,
+// writing an error if the object does not exist. The caller must hold s.mu.
+func (s *Server) parsePermissionsPath(w http.ResponseWriter, r *http.Request) (*permissionsRequest, bool) {
+	const marker = "/permissions/object/"
+	p := strings.TrimSuffix(r.URL.Path, "/")
+	i := strings.Index(strings.ToLower(p), marker)
+	parts := strings.Split(p[i+len(marker):], "/")
+
+	req := &permissionsRequest{}
+	if n := len(parts); n > 1 && strings.EqualFold(parts[n-1], "effective") {
+		req.effective = true
+		parts = parts[:n-1]
+	}
+
+	for _, obj := range s.objects {
+		if strings.EqualFold(obj.GUID, parts[0]) {
+			req.obj = obj
+		}
+	}
+	if req.obj == nil {
+		writeError(w, http.StatusNotFound, "Object does not exist")
+		return nil, false
+	}
+
+	switch len(parts) {
+	case 1:
+	case 3:
+		req.principal = parts[1] + ":" + parts[2]
+	case 4:
+		req.principal = parts[1] + "+" + parts[2] + ":" + parts[3]
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid principal")
+		return nil, false
+	}
+	return req, true
+}
+
+// inherited combines the explicit permissions of principal and the groups it
+// belongs to on obj and its parents. With self false, principal's own explicit
+// permissions on obj are left out. The caller must hold s.mu.
+func (s *Server) inherited(obj *object, principal string, self bool) venafi.Permissions {
+	principals := []string{strings.ToLower(principal)}
+	for _, group := range s.groupsOf(principal) {
+		principals = append(principals, strings.ToLower(group.PrefixedUniversal))
+	}
+
+	var rv venafi.Permissions
+	for dn := obj.DN; dn != ""; dn = dnParent(dn) {
+		o, ok := s.objects[dnKey(dn)]
+		if !ok {
+			continue
+		}
+		for _, p := range principals {
+			if o == obj && p == principals[0] && !self {
+				continue
+			}
+			rv = rv.Union(o.perms[p].perms)
+		}
+	}
+	return rv
+}
+
+func (s *Server) handlePermissions(w http.ResponseWriter, r *http.Request) {
+	var perms venafi.Permissions
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if !decodeJSON(w, r, &perms) {
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, ok := s.parsePermissionsPath(w, r)
+	if !ok {
+		return
+	}
+
+	if req.principal == "" {
+		principals := make([]string, 0, len(req.obj.perms))
+		for _, g := range req.obj.perms {
+			principals = append(principals, g.principal)
+		}
+		sort.Strings(principals)
+		writeJSON(w, http.StatusOK, principals)
+		return
+	}
+
+	explicit, exists := req.obj.perms[strings.ToLower(req.principal)]
+	switch {
+	case r.Method == http.MethodGet && req.effective:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"EffectivePermissions": s.inherited(req.obj, req.principal, true),
+		})
+	case r.Method == http.MethodGet:
+		out := map[string]interface{}{
+			"ExplicitPermissions": nil,
+			"ImplicitPermissions": s.inherited(req.obj, req.principal, false),
+		}
+		if exists {
+			out["ExplicitPermissions"] = explicit.perms
+		}
+		writeJSON(w, http.StatusOK, out)
+	case r.Method == http.MethodPost && exists:
+		writeError(w, http.StatusConflict, "Permissions already exist for this principal")
+	case r.Method == http.MethodPut && !exists:
+		writeError(w, http.StatusNotFound, "No permissions exist for this principal")
+	case r.Method == http.MethodDelete:
+		delete(req.obj.perms, strings.ToLower(req.principal))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"Success": true})
+	default:
+		req.obj.setPermissions(req.principal, perms)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"Success": true})
+	}
+}
@@ -0,0 +1,248 @@
+package venafitest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+// policyAttr is a policy value set on a folder for one class and attribute.
+type policyAttr struct {
+	values []string
+	locked bool
+}
+
+// policy returns the policy value set directly on obj, if any.
+func (o *object) policy(class string, attr string) (*policyAttr, bool) {
+	p, ok := o.policies[strings.ToLower(class)][attr]
+	return p, ok
+}
+
+func (o *object) setPolicy(class string, attr string, p *policyAttr) {
+	if o.policies == nil {
+		o.policies = make(map[string]map[string]*policyAttr)
+	}
+	key := strings.ToLower(class)
+	if o.policies[key] == nil {
+		o.policies[key] = make(map[string]*policyAttr)
+	}
+	o.policies[key][attr] = p
+}
+
+// findPolicy resolves the policy for class and attr as seen from the folder
+// at dn: the topmost locked value wins, otherwise the nearest value. The caller
+// must hold s.mu.
+func (s *Server) findPolicy(dn string, class string, attr string) (*policyAttr, string, bool) {
+	var chain []*object
+	for d := dn; d != ""; d = dnParent(d) {
+		if obj, ok := s.objects[dnKey(d)]; ok {
+			chain = append(chain, obj)
+		}
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if p, ok := chain[i].policy(class, attr); ok && p.locked {
+			return p, chain[i].DN, true
+		}
+	}
+	for _, obj := range chain {
+		if p, ok := obj.policy(class, attr); ok {
+			return p, obj.DN, true
+		}
+	}
+	return nil, "", false
+}
+
+func (s *Server) handleConfigFindPolicy(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookup(w, input.ObjectDN, ""); !ok {
+		return
+	}
+	p, policyDN, ok := s.findPolicy(input.ObjectDN, input.Class, input.AttributeName)
+	if !ok {
+		writeConfigResult(w, config.PolicyDoesNotExist, "Policy does not exist", nil)
+		return
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{
+		"PolicyDN": policyDN,
+		"Locked":   p.locked,
+		"Values":   p.values,
+	})
+}
+
+func (s *Server) handleConfigReadEffectivePolicy(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		AttributeName string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+
+	own, hasOwn := obj.attrs[input.AttributeName]
+	p, policyDN, hasPolicy := s.findPolicy(obj.Parent, obj.Class, input.AttributeName)
+
+	output := map[string]interface{}{"Locked": false, "Overridden": false, "Values": []string{}}
+	switch {
+	case hasPolicy && p.locked:
+		output["PolicyDN"], output["Locked"], output["Values"] = policyDN, true, p.values
+		output["Overridden"] = hasOwn
+	case hasOwn:
+		output["PolicyDN"], output["Values"] = obj.DN, own
+	case hasPolicy:
+		output["PolicyDN"], output["Values"] = policyDN, p.values
+	}
+
+	writeConfigResult(w, config.Success, "", output)
+}
+
+func (s *Server) handleConfigReadPolicy(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	p, ok := obj.policy(input.Class, input.AttributeName)
+	if !ok {
+		p = &policyAttr{values: []string{}}
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{
+		"Locked": p.locked,
+		"Values": p.values,
+	})
+}
+
+func (s *Server) handleConfigWritePolicy(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+		Locked        int
+		Values        []string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.policyFolder(w, input.ObjectDN)
+	if !ok {
+		return
+	}
+	obj.setPolicy(input.Class, input.AttributeName, &policyAttr{
+		values: append([]string{}, input.Values...),
+		locked: input.Locked != 0,
+	})
+	s.touch(obj)
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+func (s *Server) handleConfigAddPolicyValue(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+		Value         string
+		Locked        int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.policyFolder(w, input.ObjectDN)
+	if !ok {
+		return
+	}
+	p, ok := obj.policy(input.Class, input.AttributeName)
+	if !ok {
+		p = &policyAttr{values: []string{}}
+		obj.setPolicy(input.Class, input.AttributeName, p)
+	}
+	for _, v := range p.values {
+		if v == input.Value {
+			writeConfigResult(w, config.AttributeValueExists, "Attribute value already exists", nil)
+			return
+		}
+	}
+	p.values = append(p.values, input.Value)
+	p.locked = input.Locked != 0
+	s.touch(obj)
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+func (s *Server) handleConfigClearPolicyAttribute(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN      string
+		Class         string
+		AttributeName string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.policyFolder(w, input.ObjectDN)
+	if !ok {
+		return
+	}
+	delete(obj.policies[strings.ToLower(input.Class)], input.AttributeName)
+	s.touch(obj)
+
+	writeConfigResult(w, config.Success, "", nil)
+}
+
+// policyFolder looks up dn and checks that it can hold policy values. The
+// caller must hold s.mu.
+func (s *Server) policyFolder(w http.ResponseWriter, dn string) (*object, bool) {
+	obj, ok := s.lookup(w, dn, "")
+	if !ok {
+		return nil, false
+	}
+	if obj.Class != config.ClassPolicy {
+		writeConfigResult(w, config.PolicyDoesNotExist, "Object is not a policy folder", nil)
+		return nil, false
+	}
+	return obj, true
+}
@@ -0,0 +1,78 @@
+package venafitest
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper that passes requests through to a real
+// TPP server and records each interaction, with secrets masked, so that it
+// can be replayed later by a Replayer.
+type Recorder struct {
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder that sends requests using next. If next is
+// nil, http.DefaultTransport is used.
+func NewRecorder(next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next}
+}
+
+// Client returns an http.Client that records through r, suitable for passing
+// to venafi.NewClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := readBody(&res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    canonicalURL(req.URL.Path, req.URL.Query()),
+			Header: sanitizeHeader(req.Header),
+			Body:   sanitizeBody(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     sanitizeHeader(res.Header),
+			Body:       sanitizeResponseBody(resBody),
+		},
+	})
+
+	return res, nil
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the interactions recorded so far to a fixture file.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
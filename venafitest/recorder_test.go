@@ -0,0 +1,138 @@
+package venafitest_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := venafitest.NewServer()
+	defer srv.Close()
+
+	cert, key := selfSigned(t, "web01.example.com")
+	attrs := map[string]string{"Description": "web", "Contact": "local:{1}", "Approver": "local:{2}", "Driver Name": "x"}
+
+	calls := func(v *venafi.Client) {
+		t.Helper()
+		if _, err := v.Config.Create(`\VED\Policy\Team`, "Policy", attrs); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := v.Config.Write(`\VED\Policy\Team`, map[string][]string{"A": {"1"}, "B": {"2"}, "C": {"3"}}); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		if _, err := v.Certs.Import(`\VED\Policy\Team`, "web01", cert, key, false); err != nil {
+			t.Fatalf("Import: %v", err)
+		}
+		got, pk, err := v.Certs.Retrieve(`\VED\Policy\Team\web01`)
+		if err != nil {
+			t.Fatalf("Retrieve: %v", err)
+		}
+		if !got.Equal(cert) || pk == nil {
+			t.Fatalf("Retrieve returned %v, %v", got.Subject, pk)
+		}
+	}
+
+	rec := venafitest.NewRecorder(nil)
+	v, err := venafi.NewClient(srv.URL, srv.Username, srv.Password, rec.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls(v)
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	cassette, err := venafitest.LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette: %v", err)
+	}
+	for _, i := range cassette.Interactions {
+		for _, body := range []string{i.Request.Body, i.Response.Body} {
+			if strings.Contains(body, srv.Password) || strings.Contains(body, v.APIKey) {
+				t.Errorf("cassette leaks a secret: %s", body)
+			}
+		}
+	}
+
+	// Replay several times, since map iteration order changes between runs.
+	for n := 0; n < 5; n++ {
+		rep, err := venafitest.LoadReplayer(path, venafitest.MatchStrict)
+		if err != nil {
+			t.Fatalf("LoadReplayer: %v", err)
+		}
+		v, err := venafi.NewClient("http://replay.invalid", srv.Username, srv.Password, rep.Client())
+		if err != nil {
+			t.Fatal(err)
+		}
+		calls(v)
+		if rep.Remaining() != 0 {
+			t.Errorf("%d interactions were not replayed", rep.Remaining())
+		}
+	}
+}
+
+func TestReplayLooseIgnoresOrder(t *testing.T) {
+	srv := venafitest.NewServer()
+	defer srv.Close()
+
+	rec := venafitest.NewRecorder(nil)
+	v, _ := venafi.NewClient(srv.URL, srv.Username, srv.Password, rec.Client())
+	if _, err := v.Config.Retrieve(`\VED\Policy`); err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if _, err := v.Config.DefaultDN(); err != nil {
+		t.Fatalf("DefaultDN: %v", err)
+	}
+
+	rep := venafitest.NewReplayer(rec.Cassette(), venafitest.MatchLoose)
+	v, _ = venafi.NewClient("http://replay.invalid", srv.Username, srv.Password, rep.Client())
+	if _, err := v.Config.DefaultDN(); err != nil {
+		t.Errorf("DefaultDN: %v", err)
+	}
+	if _, err := v.Config.Retrieve(`\VED\Policy`); err != nil {
+		t.Errorf("Retrieve: %v", err)
+	}
+
+	strict := venafitest.NewReplayer(rec.Cassette(), venafitest.MatchStrict)
+	v, _ = venafi.NewClient("http://replay.invalid", srv.Username, srv.Password, strict.Client())
+	if _, err := v.Config.Retrieve(`\VED\Policy\Other`); err == nil {
+		t.Error("strict replay accepted a request that was not recorded")
+	}
+}
+
+func selfSigned(t *testing.T, commonName string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
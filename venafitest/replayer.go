@@ -0,0 +1,157 @@
+package venafitest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// MatchMode controls how a Replayer pairs incoming requests with recorded
+// interactions.
+type MatchMode int
+
+const (
+	// MatchStrict requires requests to arrive in the recorded order with the
+	// same method, URL and body.
+	MatchStrict MatchMode = iota
+
+	// MatchLoose serves the first unused interaction with the same method and
+	// URL path, ignoring order, query parameters and body.
+	MatchLoose
+)
+
+// Replayer is an http.RoundTripper that serves responses from a cassette
+// instead of contacting a server.
+type Replayer struct {
+	mode     MatchMode
+	cassette *Cassette
+
+	mu   sync.Mutex
+	used []bool
+	next int
+}
+
+// NewReplayer returns a Replayer serving the interactions in cassette.
+func NewReplayer(cassette *Cassette, mode MatchMode) *Replayer {
+	return &Replayer{
+		mode:     mode,
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// LoadReplayer reads a fixture file and returns a Replayer serving it.
+func LoadReplayer(path string, mode MatchMode) (*Replayer, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(cassette, mode), nil
+}
+
+// Client returns an http.Client that replays through r, suitable for passing
+// to venafi.NewClient.
+func (r *Replayer) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	incoming := RecordedRequest{
+		Method: req.Method,
+		URL:    canonicalURL(req.URL.Path, req.URL.Query()),
+		Body:   sanitizeBody(body),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var i int
+	if r.mode == MatchStrict {
+		i, err = r.matchStrict(incoming)
+	} else {
+		i, err = r.matchLoose(incoming, req.URL.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.used[i] = true
+
+	recorded := r.cassette.Interactions[i].Response
+	header := make(http.Header, len(recorded.Header))
+	for k, v := range recorded.Header {
+		header[k] = append([]string(nil), v...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(recorded.Body))),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Remaining returns the number of recorded interactions not yet served.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, used := range r.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+// matchStrict checks incoming against the next interaction in order. The
+// caller must hold r.mu.
+func (r *Replayer) matchStrict(incoming RecordedRequest) (int, error) {
+	if r.next >= len(r.cassette.Interactions) {
+		return 0, fmt.Errorf("replay: unexpected request %s %s: cassette exhausted", incoming.Method, incoming.URL)
+	}
+
+	want := r.cassette.Interactions[r.next].Request
+	if incoming.Method != want.Method || !strings.EqualFold(incoming.URL, want.URL) {
+		return 0, fmt.Errorf("replay: request %d is %s %s, want %s %s", r.next+1,
+			incoming.Method, incoming.URL, want.Method, want.URL)
+	}
+	if canonicalJSON(incoming.Body) != canonicalJSON(want.Body) {
+		return 0, fmt.Errorf("replay: request %d to %s %s has body %s, want %s", r.next+1,
+			incoming.Method, incoming.URL, incoming.Body, want.Body)
+	}
+
+	r.next++
+	return r.next - 1, nil
+}
+
+// matchLoose finds the first unused interaction with the same method and path.
+// The caller must hold r.mu.
+func (r *Replayer) matchLoose(incoming RecordedRequest, path string) (int, error) {
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request.Method != incoming.Method {
+			continue
+		}
+		recordedPath := interaction.Request.URL
+		if q := strings.IndexByte(recordedPath, '?'); q >= 0 {
+			recordedPath = recordedPath[:q]
+		}
+		if strings.EqualFold(recordedPath, path) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("replay: no recorded interaction for %s %s", incoming.Method, incoming.URL)
+}
@@ -0,0 +1,156 @@
+package venafitest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+// classDefinition is the fake's view of a schema class.
+type classDefinition struct {
+	Name             string
+	ContainmentNames []string
+}
+
+// schemaClasses is the subset of the TPP schema known to the fake.
+var schemaClasses = map[string]classDefinition{
+	"top":              {"Top", nil},
+	"policy":           {config.ClassPolicy, []string{"Top", config.ClassPolicy}},
+	"device":           {config.ClassDevice, []string{config.ClassPolicy}},
+	"x509 certificate": {config.ClassX509Certificate, []string{config.ClassPolicy, config.ClassDevice}},
+	"openssl ca":       {config.ClassOpenSSLCA, []string{config.ClassPolicy}},
+	"self signed ca":   {config.ClassSelfSignedCA, []string{config.ClassPolicy}},
+}
+
+func (s *Server) handleSchemaClass(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Class string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	class, ok := schemaClasses[strings.ToLower(input.Class)]
+	if !ok {
+		writeConfigResult(w, config.ClassDoesNotExist, "Class does not exist", nil)
+		return
+	}
+
+	containment := class.ContainmentNames
+	if containment == nil {
+		containment = []string{}
+	}
+	optional := make([]string, 0, len(schemaAttributes))
+	if class.Name != "Top" {
+		for name := range schemaAttributes {
+			optional = append(optional, name)
+		}
+		sort.Strings(optional)
+	}
+	writeConfigResult(w, config.Success, "", map[string]interface{}{
+		"ClassDefinition": map[string]interface{}{
+			"Name":             class.Name,
+			"SuperClassNames":  []string{"Top"},
+			"ContainmentNames": containment,
+			"MandatoryNames":   []string{},
+			"OptionalNames":    optional,
+		},
+	})
+}
+
+func (s *Server) handleSchemaContainment(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Class string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	if _, ok := schemaClasses[strings.ToLower(input.Class)]; !ok {
+		writeConfigResult(w, config.ClassDoesNotExist, "Class does not exist", nil)
+		return
+	}
+
+	names := make([]string, 0)
+	for _, class := range schemaClasses {
+		for _, container := range class.ContainmentNames {
+			if strings.EqualFold(container, input.Class) {
+				names = append(names, class.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"ClassNames": names})
+}
+
+func (s *Server) handleConfigGetHighestRevision(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ObjectDN string
+		Classes  string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	root, ok := s.lookup(w, input.ObjectDN, "")
+	if !ok {
+		return
+	}
+	var classes []string
+	if input.Classes != "" {
+		classes = strings.Split(input.Classes, ",")
+	}
+
+	var revision int64
+	for _, obj := range append(s.children(root.DN, true), root) {
+		if len(classes) > 0 && !containsFold(classes, obj.Class) {
+			continue
+		}
+		if obj.Revision > revision {
+			revision = obj.Revision
+		}
+	}
+
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"Revision": revision})
+}
+
+// schemaAttributes is the subset of TPP attribute definitions known to the
+// fake. Attributes not listed here are treated as strings.
+var schemaAttributes = map[string]config.AttributeSyntax{
+	"Contact":                 config.SyntaxDN,
+	"Approver":                config.SyntaxDN,
+	"Certificate Authority":   config.SyntaxDN,
+	"Description":             config.SyntaxString,
+	"Disabled":                config.SyntaxBoolean,
+	"Key Bit Strength":        config.SyntaxInteger,
+	"Manual Csr":              config.SyntaxBoolean,
+	"Prohibit Wildcard":       config.SyntaxBoolean,
+	"Revision":                config.SyntaxInteger,
+	"Created On":              config.SyntaxTime,
+	"Last Renewed On":         config.SyntaxTime,
+	"X509 Subject":            config.SyntaxString,
+	"X509 SubjectAltName DNS": config.SyntaxString,
+	"Certificate Vault Id":    config.SyntaxInteger,
+	"Consumers":               config.SyntaxDN,
+	"Driver Name":             config.SyntaxString,
+	"Management Type":         config.SyntaxString,
+	"Organization":            config.SyntaxString,
+	"Organizational Unit":     config.SyntaxString,
+	"Renewal Window":          config.SyntaxInteger,
+	"Validity Period":         config.SyntaxInteger,
+	"Private Key Vault Id":    config.SyntaxInteger,
+}
+
+func (s *Server) handleSchemaAttributes(w http.ResponseWriter, r *http.Request) {
+	defs := make([]map[string]interface{}, 0, len(schemaAttributes))
+	for name, syntax := range schemaAttributes {
+		defs = append(defs, map[string]interface{}{"Name": name, "Syntax": syntax})
+	}
+	writeConfigResult(w, config.Success, "", map[string]interface{}{"AttributeDefinitions": defs})
+}
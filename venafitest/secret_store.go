@@ -0,0 +1,335 @@
+package venafitest
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+// associationRequest is the body of the SecretStore association endpoints.
+type associationRequest struct {
+	VaultID     int
+	Name        string
+	StringValue string
+	IntValue    *int
+	DateValue   string
+}
+
+// value returns the string form of whichever value field is set, and false
+// if none is.
+func (a *associationRequest) value() (string, bool) {
+	switch {
+	case a.IntValue != nil:
+		return strconv.Itoa(*a.IntValue), true
+	case a.DateValue != "":
+		return a.DateValue, true
+	case a.StringValue != "":
+		return a.StringValue, true
+	}
+	return "", false
+}
+
+// secret returns the vault entry with the given ID, writing InvalidVaultID if
+// there is none. The caller must hold s.mu.
+func (s *Server) secret(w http.ResponseWriter, vaultID int) *vaultEntry {
+	entry, ok := s.vault[vaultID]
+	if !ok {
+		writeStoreResult(w, secret_store.InvalidVaultID, nil)
+		return nil
+	}
+	return entry
+}
+
+func (s *Server) handleSecretAdd(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Base64Data string
+		Keyname    secret_store.ProtectionKey
+		Namespace  string
+		Owner      string
+		VaultType  secret_store.VaultType
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	data, err := base64.StdEncoding.DecodeString(input.Base64Data)
+	if err != nil || input.VaultType == 0 {
+		writeStoreResult(w, secret_store.InvalidParams, nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.hasProtectionKey(input.Keyname) {
+		writeStoreResult(w, secret_store.InvalidKey, nil)
+		return
+	}
+
+	id := s.addSecret(data, input.VaultType, input.Owner, input.Keyname)
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{"VaultID": id})
+}
+
+func (s *Server) handleSecretRetrieve(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		VaultID int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.secret(w, input.VaultID)
+	if entry == nil {
+		return
+	}
+
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{
+		"Base64Data": base64.StdEncoding.EncodeToString(entry.data),
+		"VaultType":  entry.vaultType,
+	})
+}
+
+func (s *Server) handleSecretAssociate(w http.ResponseWriter, r *http.Request) {
+	var input associationRequest
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	value, ok := input.value()
+	if !ok || input.Name == "" {
+		writeStoreResult(w, secret_store.InvalidParams, nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.secret(w, input.VaultID)
+	if entry == nil {
+		return
+	}
+	if !containsFold(entry.associations[input.Name], value) {
+		entry.associations[input.Name] = append(entry.associations[input.Name], value)
+	}
+
+	writeStoreResult(w, secret_store.Success, nil)
+}
+
+func (s *Server) handleSecretDissociate(w http.ResponseWriter, r *http.Request) {
+	var input associationRequest
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.secret(w, input.VaultID)
+	if entry == nil {
+		return
+	}
+
+	value, ok := input.value()
+	if !ok {
+		delete(entry.associations, input.Name)
+		writeStoreResult(w, secret_store.Success, nil)
+		return
+	}
+	values := entry.associations[input.Name][:0]
+	for _, v := range entry.associations[input.Name] {
+		if !strings.EqualFold(v, value) {
+			values = append(values, v)
+		}
+	}
+	entry.associations[input.Name] = values
+
+	writeStoreResult(w, secret_store.Success, nil)
+}
+
+func (s *Server) handleSecretLookupByAssociation(w http.ResponseWriter, r *http.Request) {
+	var input associationRequest
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	value, ok := input.value()
+	if !ok {
+		writeStoreResult(w, secret_store.InvalidParams, nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := s.sortedVaultIDs(func(entry *vaultEntry) bool {
+		return containsFold(entry.associations[input.Name], value)
+	})
+
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{"VaultIDs": ids})
+}
+
+func (s *Server) handleSecretLookupByOwner(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Namespace string
+		Owner     string
+		VaultType secret_store.VaultType
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := s.sortedVaultIDs(func(entry *vaultEntry) bool {
+		return entry.ownedBy(input.Owner) && (input.VaultType == 0 || entry.vaultType == input.VaultType)
+	})
+
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{"VaultIDs": ids})
+}
+
+func (s *Server) handleSecretMutate(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		VaultID   int
+		VaultType secret_store.VaultType
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.secret(w, input.VaultID)
+	if entry == nil {
+		return
+	}
+	entry.vaultType = input.VaultType
+
+	writeStoreResult(w, secret_store.Success, nil)
+}
+
+func (s *Server) handleSecretOwnerAdd(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Namespace string
+		Owner     string
+		VaultID   int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.secret(w, input.VaultID)
+	if entry == nil {
+		return
+	}
+	if !entry.ownedBy(input.Owner) {
+		entry.owners = append(entry.owners, input.Owner)
+	}
+
+	writeStoreResult(w, secret_store.Success, nil)
+}
+
+func (s *Server) handleSecretOwnerDelete(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Namespace string
+		Owner     string
+		VaultID   int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.secret(w, input.VaultID)
+	if entry == nil {
+		return
+	}
+	owners := entry.owners[:0]
+	for _, owner := range entry.owners {
+		if !strings.EqualFold(owner, input.Owner) {
+			owners = append(owners, owner)
+		}
+	}
+	entry.owners = owners
+	if len(owners) == 0 {
+		delete(s.vault, input.VaultID)
+	}
+
+	writeStoreResult(w, secret_store.Success, nil)
+}
+
+func (s *Server) handleSecretOwnerLookup(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Namespace string
+		VaultID   int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.secret(w, input.VaultID)
+	if entry == nil {
+		return
+	}
+
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{
+		"Owners": append([]string{}, entry.owners...),
+	})
+}
+
+func (s *Server) handleSecretEncryptionKeysInUse(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[secret_store.ProtectionKey]bool)
+	keys := make([]secret_store.ProtectionKey, 0)
+	for _, entry := range s.vault {
+		if !seen[entry.protectionKey] {
+			seen[entry.protectionKey] = true
+			keys = append(keys, entry.protectionKey)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{"EncryptionKeys": keys})
+}
+
+func (s *Server) handleSecretReEncrypt(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Keyname secret_store.ProtectionKey
+		VaultID int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.hasProtectionKey(input.Keyname) {
+		writeStoreResult(w, secret_store.InvalidKey, nil)
+		return
+	}
+
+	entry := s.secret(w, input.VaultID)
+	if entry == nil {
+		return
+	}
+	entry.protectionKey = input.Keyname
+
+	writeStoreResult(w, secret_store.Success, nil)
+}
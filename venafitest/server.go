@@ -0,0 +1,300 @@
+// Package venafitest provides an in-memory fake of the Venafi TPP web SDK for
+// use in tests. It speaks enough of the API for every service in the venafi
+// package and lets tests inject failures and latency per endpoint.
+package venafitest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+)
+
+// Default credentials accepted by a new Server.
+const (
+	DefaultUsername = "tppadmin"
+	DefaultPassword = "Passw0rd"
+)
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request)
+
+// Server is a fake TPP server backed by an in-memory object tree. The zero
+// value is not usable; create one with NewServer.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password are the only credentials accepted by authorize.
+	Username string
+	Password string
+
+	// Identity is returned from Identity/Self for an authenticated session.
+	Identity venafi.Identity
+
+	mu       sync.Mutex
+	apiKeys  map[string]bool
+	objects  map[string]*object
+	certs    map[string]*certEntry
+	vault    map[int]*vaultEntry
+	users    map[string]*directoryEntry
+	keys     []secret_store.ProtectionKey
+	nextID   int
+	nextVID  int
+	revision int64
+	faults   map[string]*Fault
+	latency  time.Duration
+	routes   map[string]handlerFunc
+}
+
+// NewServer starts a fake TPP server containing the \VED and \VED\Policy
+// roots. Callers should Close it when finished.
+func NewServer() *Server {
+	s := &Server{
+		Username: DefaultUsername,
+		Password: DefaultPassword,
+		Identity: venafi.Identity{
+			FullName:          `\VED\Identity\` + DefaultUsername,
+			Name:              DefaultUsername,
+			Prefix:            "local",
+			PrefixedName:      "local:" + DefaultUsername,
+			PrefixedUniversal: "local:{" + DefaultUsername + "}",
+			Universal:         "{" + DefaultUsername + "}",
+			Type:              venafi.IdentityUser,
+		},
+		apiKeys: make(map[string]bool),
+		objects: make(map[string]*object),
+		certs:   make(map[string]*certEntry),
+		vault:   make(map[int]*vaultEntry),
+		users:   make(map[string]*directoryEntry),
+		keys:    []secret_store.ProtectionKey{secret_store.ProtectionKeyDefault},
+		faults:  make(map[string]*Fault),
+		nextID:  1,
+		nextVID: 1,
+	}
+
+	s.addObject(`\VED`, "Top", nil)
+	s.addObject(`\VED\Policy`, config.ClassPolicy, nil)
+
+	s.routes = map[string]handlerFunc{
+		"POST /vedsdk/authorize":                           s.handleAuthorize,
+		"POST /vedsdk/config/create":                       s.handleConfigCreate,
+		"POST /vedsdk/config/isvalid":                      s.handleConfigIsValid,
+		"GET /vedsdk/config/defaultdn":                     s.handleConfigDefaultDN,
+		"POST /vedsdk/config/delete":                       s.handleConfigDelete,
+		"POST /vedsdk/config/enumerate":                    s.handleConfigEnumerate,
+		"POST /vedsdk/config/addvalue":                     s.handleConfigAddValue,
+		"POST /vedsdk/config/clearattribute":               s.handleConfigClearAttribute,
+		"POST /vedsdk/config/read":                         s.handleConfigRead,
+		"POST /vedsdk/config/readall":                      s.handleConfigReadAll,
+		"POST /vedsdk/config/write":                        s.handleConfigWrite,
+		"POST /vedsdk/config/find":                         s.handleConfigFind,
+		"POST /vedsdk/config/findobjectsofclass":           s.handleConfigFindObjectsOfClass,
+		"POST /vedsdk/config/findcontainers":               s.handleConfigFindContainers,
+		"POST /vedsdk/config/dntoguid":                     s.handleConfigDnToGuid,
+		"POST /vedsdk/config/guidtodn":                     s.handleConfigGuidToDn,
+		"POST /vedsdk/config/idinfo":                       s.handleConfigIdInfo,
+		"POST /vedsdk/config/readdn":                       s.handleConfigRead,
+		"POST /vedsdk/config/removeattributevalues":        s.handleConfigRemoveAttributeValues,
+		"POST /vedsdk/config/removednvalue":                s.handleConfigRemoveAttributeValues,
+		"POST /vedsdk/config/writedn":                      s.handleConfigWriteDn,
+		"POST /vedsdk/config/renameobject":                 s.handleConfigRenameObject,
+		"POST /vedsdk/config/findpolicy":                   s.handleConfigFindPolicy,
+		"POST /vedsdk/config/readeffectivepolicy":          s.handleConfigReadEffectivePolicy,
+		"POST /vedsdk/config/readpolicy":                   s.handleConfigReadPolicy,
+		"POST /vedsdk/config/writepolicy":                  s.handleConfigWritePolicy,
+		"POST /vedsdk/config/addpolicyvalue":               s.handleConfigAddPolicyValue,
+		"POST /vedsdk/config/clearpolicyattribute":         s.handleConfigClearPolicyAttribute,
+		"POST /vedsdk/configschema/attributes":             s.handleSchemaAttributes,
+		"POST /vedsdk/configschema/containment":            s.handleSchemaContainment,
+		"POST /vedsdk/config/getrevision":                  s.handleConfigGetRevision,
+		"POST /vedsdk/config/gethighestrevision":           s.handleConfigGetHighestRevision,
+		"POST /vedsdk/configschema/class":                  s.handleSchemaClass,
+		"GET /vedsdk/identity/self":                        s.handleIdentitySelf,
+		"POST /vedsdk/identity/browse":                     s.handleIdentityBrowse,
+		"POST /vedsdk/identity/getassociatedentries":       s.handleIdentityGetAssociatedEntries,
+		"POST /vedsdk/identity/readattribute":              s.handleIdentityReadAttribute,
+		"POST /vedsdk/identity/getmembers":                 s.handleIdentityGetMembers,
+		"POST /vedsdk/identity/getmemberships":             s.handleIdentityGetMemberships,
+		"POST /vedsdk/identity/addgroup":                   s.handleIdentityAddGroup,
+		"PUT /vedsdk/identity/addgroupmembers":             s.handleIdentityAddGroupMembers,
+		"PUT /vedsdk/identity/removegroupmembers":          s.handleIdentityRemoveGroupMembers,
+		"PUT /vedsdk/identity/renamegroup":                 s.handleIdentityRenameGroup,
+		"DELETE /vedsdk/identity/group/*":                  s.handleIdentityDeleteGroup,
+		"POST /vedsdk/identity/validate":                   s.handleIdentityValidate,
+		"GET /vedsdk/permissions/object/*":                 s.handlePermissions,
+		"POST /vedsdk/permissions/object/*":                s.handlePermissions,
+		"PUT /vedsdk/permissions/object/*":                 s.handlePermissions,
+		"DELETE /vedsdk/permissions/object/*":              s.handlePermissions,
+		"GET /vedsdk/certificates":                         s.handleCertificatesList,
+		"POST /vedsdk/certificates/request":                s.handleCertificatesRequest,
+		"POST /vedsdk/certificates/retrieve":               s.handleCertificatesRetrieve,
+		"POST /vedsdk/certificates/import":                 s.handleCertificatesImport,
+		"POST /vedsdk/x509certificatestore/add":            s.handleStoreAdd,
+		"POST /vedsdk/x509certificatestore/lookup":         s.handleStoreLookup,
+		"POST /vedsdk/x509certificatestore/lookupexpiring": s.handleStoreLookupExpiring,
+		"POST /vedsdk/x509certificatestore/retrieve":       s.handleStoreRetrieve,
+		"POST /vedsdk/x509certificatestore/remove":         s.handleStoreRemove,
+		"GET /vedsdk/crypto/availablekeys":                 s.handleCryptoAvailableKeys,
+		"GET /vedsdk/crypto/getdefaultkey":                 s.handleCryptoGetDefaultKey,
+		"POST /vedsdk/secretstore/add":                     s.handleSecretAdd,
+		"POST /vedsdk/secretstore/retrieve":                s.handleSecretRetrieve,
+		"POST /vedsdk/secretstore/associate":               s.handleSecretAssociate,
+		"POST /vedsdk/secretstore/dissociate":              s.handleSecretDissociate,
+		"POST /vedsdk/secretstore/lookupbyassociation":     s.handleSecretLookupByAssociation,
+		"POST /vedsdk/secretstore/lookupbyowner":           s.handleSecretLookupByOwner,
+		"POST /vedsdk/secretstore/mutate":                  s.handleSecretMutate,
+		"POST /vedsdk/secretstore/owneradd":                s.handleSecretOwnerAdd,
+		"POST /vedsdk/secretstore/ownerdelete":             s.handleSecretOwnerDelete,
+		"POST /vedsdk/secretstore/ownerlookup":             s.handleSecretOwnerLookup,
+		"POST /vedsdk/secretstore/encryptionkeysinuse":     s.handleSecretEncryptionKeysInUse,
+		"POST /vedsdk/secretstore/reencrypt":               s.handleSecretReEncrypt,
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewClient returns a venafi.Client pointed at the fake server and logged in
+// with the server's credentials.
+func (s *Server) NewClient() (*venafi.Client, error) {
+	return venafi.NewClient(s.URL, s.Username, s.Password, s.Client())
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := normalizePath(r.URL.Path)
+
+	s.mu.Lock()
+	latency := s.latency
+	fault := s.takeFault(path)
+	s.mu.Unlock()
+
+	if fault != nil && fault.Latency > 0 {
+		latency = fault.Latency
+	}
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+	if fault != nil && fault.StatusCode != 0 {
+		writeJSON(w, fault.StatusCode, fault.Body)
+		return
+	}
+
+	handler, ok := s.routes[r.Method+" "+path]
+	if !ok {
+		handler, ok = s.prefixRoute(r.Method + " " + path)
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "no such endpoint: "+r.Method+" "+r.URL.Path)
+		return
+	}
+
+	if path != "/vedsdk/authorize" && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "Authorization failed")
+		return
+	}
+
+	handler(w, r)
+}
+
+// prefixRoute finds a route whose key ends in "/*" and matches the start of
+// key, for endpoints that take parameters in the path.
+func (s *Server) prefixRoute(key string) (handlerFunc, bool) {
+	for route, handler := range s.routes {
+		if strings.HasSuffix(route, "/*") && strings.HasPrefix(key, strings.TrimSuffix(route, "*")) {
+			return handler, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	key := r.Header.Get("X-Venafi-Api-Key")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.apiKeys[key]
+}
+
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Username string
+		Password string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	if input.Username != s.Username || input.Password != s.Password {
+		writeError(w, http.StatusUnauthorized, "Username/password combination not valid")
+		return
+	}
+
+	key := newGUID()[1:37]
+
+	s.mu.Lock()
+	s.apiKeys[key] = true
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"APIKey":     key,
+		"ValidUntil": "/Date(" + jsonTime(time.Now().Add(3*time.Minute)) + ")/",
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+func normalizePath(path string) string {
+	return strings.TrimSuffix(strings.ToLower(path), "/")
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"Error": message})
+}
+
+func newGUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	h := hex.EncodeToString(b)
+	return "{" + h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32] + "}"
+}
+
+// jsonTime formats t as milliseconds since the epoch, as used in TPP's
+// "/Date(...)/" timestamps.
+func jsonTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), s) {
+			return true
+		}
+	}
+	return false
+}
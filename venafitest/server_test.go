@@ -0,0 +1,170 @@
+package venafitest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+// newServer starts a fake server and returns it with a client logged in to
+// it. The server is closed when the test finishes.
+func newServer(t *testing.T) (*venafitest.Server, *venafi.Client) {
+	t.Helper()
+
+	srv := venafitest.NewServer()
+	t.Cleanup(srv.Close)
+
+	v, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return srv, v
+}
+
+// post sends body to path with the client's API key and decodes the JSON
+// response into output.
+func post(t *testing.T, srv *venafitest.Server, v *venafi.Client, path string, body interface{}, output interface{}) int {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest("POST", srv.URL+path, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Venafi-Api-Key", v.APIKey)
+
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", path, err)
+	}
+	defer res.Body.Close()
+	if err := json.NewDecoder(res.Body).Decode(output); err != nil {
+		t.Fatalf("decoding %s response: %v", path, err)
+	}
+	return res.StatusCode
+}
+
+func TestCertificateRequest(t *testing.T) {
+	srv, v := newServer(t)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+
+	// Log in so the client has an API key to reuse.
+	if _, err := v.Certs.List(); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	input := map[string]interface{}{
+		"PolicyDN":        `\VED\Policy\Web`,
+		"ObjectName":      "web01",
+		"Subject":         "web01.example.com",
+		"SubjectAltNames": []map[string]interface{}{{"Type": 2, "Name": "web01.example.com"}},
+		"ValidityPeriod":  30,
+	}
+	var output struct {
+		CertificateDN string
+		Guid          string
+	}
+	if status := post(t, srv, v, "/vedsdk/certificates/Request", input, &output); status != http.StatusOK {
+		t.Fatalf("Request returned status %d", status)
+	}
+	if output.CertificateDN != `\VED\Policy\Web\web01` || output.Guid == "" {
+		t.Errorf("Request = %+v", output)
+	}
+
+	cert, key, err := v.Certs.Retrieve(output.CertificateDN)
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	if cert.Subject.CommonName != "web01.example.com" || len(cert.DNSNames) != 1 || key == nil {
+		t.Errorf("issued certificate = %s %v, key %v", cert.Subject, cert.DNSNames, key)
+	}
+	if days := cert.NotAfter.Sub(cert.NotBefore).Hours() / 24; days != 30 {
+		t.Errorf("issued certificate is valid for %v days, want 30", days)
+	}
+
+	// Requesting into a missing folder fails.
+	var failure struct{ Error string }
+	input["PolicyDN"] = `\VED\Policy\Missing`
+	if status := post(t, srv, v, "/vedsdk/certificates/Request", input, &failure); status != http.StatusBadRequest || failure.Error == "" {
+		t.Errorf("Request into a missing folder: status %d, %+v", status, failure)
+	}
+}
+
+func TestInjectFault(t *testing.T) {
+	srv, v := newServer(t)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+
+	// A fault with Times set applies that many times and then clears itself.
+	fault := venafitest.ConfigFault(config.InsufficientPrivileges, "denied")
+	fault.Times = 2
+	srv.Inject("/vedsdk/config/isvalid", fault)
+	for i := 0; i < 2; i++ {
+		if _, err := v.Config.IsValid(`\VED\Policy\Web`, ""); !errors.Is(err, venafi.ErrInsufficientPrivileges) {
+			t.Errorf("call %d: got %v, want ErrInsufficientPrivileges", i+1, err)
+		}
+	}
+	if _, err := v.Config.IsValid(`\VED\Policy\Web`, ""); err != nil {
+		t.Errorf("call after the fault ran out: %v", err)
+	}
+
+	// Without Times, a fault stays until it is cleared. Other endpoints are
+	// not affected.
+	srv.Inject("/vedsdk/Config/Read", venafitest.HTTPFault(http.StatusInternalServerError, "boom"))
+	for i := 0; i < 3; i++ {
+		var apiErr *venafi.APIError
+		if _, err := v.Config.Read(`\VED\Policy\Web`, "Description"); !errors.As(err, &apiErr) ||
+			apiErr.StatusCode != http.StatusInternalServerError {
+			t.Errorf("call %d: got %v, want a 500 APIError", i+1, err)
+		}
+	}
+	if _, err := v.Config.IsValid(`\VED\Policy\Web`, ""); err != nil {
+		t.Errorf("IsValid with a fault on Read: %v", err)
+	}
+	srv.ClearFaults()
+	if _, err := v.Config.Read(`\VED\Policy\Web`, "Description"); err != nil {
+		t.Errorf("Read after ClearFaults: %v", err)
+	}
+
+	srv.Inject("/vedsdk/X509CertificateStore/Lookup", venafitest.StoreFault(secret_store.InsufficientPermissions))
+	if _, err := v.X509Store.Lookup(nil, `\VED\Policy\Web`, "", ""); !errors.Is(err, venafi.ErrInsufficientPrivileges) {
+		t.Errorf("Lookup with a store fault: got %v, want ErrInsufficientPrivileges", err)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	srv, v := newServer(t)
+	srv.AddObject(`\VED\Policy\Web`, config.ClassPolicy, nil)
+
+	elapsed := func() time.Duration {
+		t.Helper()
+		start := time.Now()
+		if _, err := v.Config.IsValid(`\VED\Policy\Web`, ""); err != nil {
+			t.Fatalf("IsValid: %v", err)
+		}
+		return time.Since(start)
+	}
+
+	srv.SetLatency(50 * time.Millisecond)
+	if d := elapsed(); d < 50*time.Millisecond {
+		t.Errorf("call with server latency took %s", d)
+	}
+
+	// A fault with only Latency set overrides the server latency and lets the
+	// call through.
+	srv.Inject("/vedsdk/Config/IsValid", venafitest.Fault{Latency: 150 * time.Millisecond, Times: 1})
+	if d := elapsed(); d < 150*time.Millisecond {
+		t.Errorf("call with fault latency took %s", d)
+	}
+}
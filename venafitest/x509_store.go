@@ -0,0 +1,261 @@
+package venafitest
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"github.com/tradel/venafi-tpp/pkg/pem"
+)
+
+// vaultEntry is a Secret Store entry. For certificates, data is the DER
+// encoding. associations holds the string form of each associated value.
+type vaultEntry struct {
+	data          []byte
+	vaultType     secret_store.VaultType
+	protectionKey secret_store.ProtectionKey
+	owners        []string
+	associations  map[string][]string
+}
+
+// addVaultEntry stores a certificate in the vault and returns its vault ID.
+// The caller must hold s.mu.
+func (s *Server) addVaultEntry(der []byte, ownerDN string) int {
+	return s.addSecret(der, secret_store.VaultTypeCertificate, ownerDN, secret_store.ProtectionKeyDefault)
+}
+
+// addSecret stores data of any type in the vault and returns its vault ID.
+// The caller must hold s.mu.
+func (s *Server) addSecret(data []byte, vaultType secret_store.VaultType, ownerDN string, protectionKey secret_store.ProtectionKey) int {
+	id := s.nextVID
+	s.nextVID++
+	entry := &vaultEntry{
+		data:          data,
+		vaultType:     vaultType,
+		protectionKey: protectionKey,
+		associations:  make(map[string][]string),
+	}
+	if ownerDN != "" {
+		entry.owners = append(entry.owners, ownerDN)
+	}
+	s.vault[id] = entry
+	return id
+}
+
+func (e *vaultEntry) isCertificate() bool {
+	return e.vaultType == secret_store.VaultTypeCertificate
+}
+
+func (e *vaultEntry) ownedBy(ownerDN string) bool {
+	for _, owner := range e.owners {
+		if strings.EqualFold(owner, ownerDN) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedVaultIDs returns the vault IDs for which match returns true, in order.
+// The caller must hold s.mu.
+func (s *Server) sortedVaultIDs(match func(*vaultEntry) bool) []int {
+	ids := make([]int, 0)
+	for id, entry := range s.vault {
+		if match(entry) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (s *Server) handleStoreAdd(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		CertificateString string
+		OwnerDN           string
+		ProtectionKey     secret_store.ProtectionKey
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	cert, ok := decodeStoreCertificate(input.CertificateString)
+	if !ok {
+		writeStoreResult(w, secret_store.InvalidParams, nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, entry := range s.vault {
+		if entry.isCertificate() && bytes.Equal(entry.data, cert.Raw) {
+			if input.OwnerDN != "" && !entry.ownedBy(input.OwnerDN) {
+				entry.owners = append(entry.owners, input.OwnerDN)
+			}
+			writeStoreResult(w, secret_store.Success, map[string]interface{}{"VaultId": id})
+			return
+		}
+	}
+
+	key := input.ProtectionKey
+	if key == "" {
+		key = secret_store.ProtectionKeyDefault
+	}
+	if !s.hasProtectionKey(key) {
+		writeStoreResult(w, secret_store.InvalidKey, nil)
+		return
+	}
+
+	id := s.addSecret(cert.Raw, secret_store.VaultTypeCertificate, input.OwnerDN, key)
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{"VaultId": id})
+}
+
+func (s *Server) handleStoreLookup(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		CertificateString string
+		OwnerDN           string
+		Name              string
+		Value             string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	var der []byte
+	if input.CertificateString != "" {
+		cert, ok := decodeStoreCertificate(input.CertificateString)
+		if !ok {
+			writeStoreResult(w, secret_store.InvalidParams, nil)
+			return
+		}
+		der = cert.Raw
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := s.sortedVaultIDs(func(entry *vaultEntry) bool {
+		switch {
+		case !entry.isCertificate():
+			return false
+		case der != nil:
+			return bytes.Equal(entry.data, der)
+		case input.OwnerDN != "":
+			return entry.ownedBy(input.OwnerDN)
+		case input.Name != "":
+			return containsFold(entry.associations[input.Name], input.Value)
+		}
+		return false
+	})
+
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{"VaultIds": ids})
+}
+
+func (s *Server) handleStoreLookupExpiring(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		DaysToExpiration int
+		OwnerDN          string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	cutoff := time.Now().AddDate(0, 0, input.DaysToExpiration)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := s.sortedVaultIDs(func(entry *vaultEntry) bool {
+		if !entry.isCertificate() || (input.OwnerDN != "" && !entry.ownedBy(input.OwnerDN)) {
+			return false
+		}
+		cert, err := x509.ParseCertificate(entry.data)
+		return err == nil && cert.NotAfter.Before(cutoff)
+	})
+
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{"VaultIds": ids})
+}
+
+func (s *Server) handleStoreRetrieve(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		VaultId int
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.vault[input.VaultId]
+	if !ok || !entry.isCertificate() {
+		writeStoreResult(w, secret_store.InvalidVaultID, nil)
+		return
+	}
+
+	writeStoreResult(w, secret_store.Success, map[string]interface{}{
+		"CertificateString": base64.StdEncoding.EncodeToString(entry.data),
+	})
+}
+
+func (s *Server) handleStoreRemove(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		VaultId int
+		OwnerDN string
+	}
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.vault[input.VaultId]
+	if !ok {
+		writeStoreResult(w, secret_store.InvalidVaultID, nil)
+		return
+	}
+
+	owners := entry.owners[:0]
+	for _, owner := range entry.owners {
+		if !strings.EqualFold(owner, input.OwnerDN) {
+			owners = append(owners, owner)
+		}
+	}
+	entry.owners = owners
+	if input.OwnerDN == "" || len(owners) == 0 {
+		delete(s.vault, input.VaultId)
+	}
+
+	writeStoreResult(w, secret_store.Success, nil)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// decodeStoreCertificate accepts the base64-encoded PEM that
+// venafi.X509StoreService sends.
+func decodeStoreCertificate(b64 string) (*x509.Certificate, bool) {
+	text, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return nil, false
+	}
+	cert, err := pem.DecodeCertString(string(text))
+	if err != nil {
+		return nil, false
+	}
+	return cert, true
+}
+
+func writeStoreResult(w http.ResponseWriter, result secret_store.SecretStoreResult, fields map[string]interface{}) {
+	body := map[string]interface{}{"Result": result}
+	for k, v := range fields {
+		body[k] = v
+	}
+	writeJSON(w, http.StatusOK, body)
+}
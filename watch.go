@@ -0,0 +1,209 @@
+package venafi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeType identifies what happened to an object seen by a Watcher.
+type ChangeType string
+
+//noinspection GoUnusedConst
+const (
+	ChangeCreated  ChangeType = "created"
+	ChangeModified ChangeType = "modified"
+	ChangeDeleted  ChangeType = "deleted"
+)
+
+// ChangeEvent reports a change to one object below a watched DN. For deleted
+// objects, Object is the last version seen.
+type ChangeEvent struct {
+	Type   ChangeType
+	Object ConfigObject
+}
+
+// WatchOptions controls how Watch polls.
+type WatchOptions struct {
+	// ResyncPolls, if positive, makes Watch enumerate the subtree every
+	// ResyncPolls polls even though its revision has not changed. Deleting an
+	// object does not move the revision of any object left in the subtree, so
+	// without resyncs a deletion is only noticed once something else changes.
+	// Each resync costs a full Enumerate of the subtree; zero turns them off.
+	ResyncPolls int
+}
+
+// DefaultWatchOptions resyncs every tenth poll, so deletions are reported
+// within ten intervals.
+var DefaultWatchOptions = WatchOptions{
+	ResyncPolls: 10,
+}
+
+// Watcher delivers the changes found by Watch. Errors from a poll are sent on
+// Errors and polling carries on. Callers must keep reading both channels until
+// Stop is called; both are closed once it returns.
+type Watcher struct {
+	Events <-chan ChangeEvent
+	Errors <-chan error
+
+	config   ConfigAPI
+	schema   SchemaAPI
+	rootDN   string
+	resync   int
+	events   chan ChangeEvent
+	errors   chan error
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Stop ends polling and waits for the watcher to shut down. It is safe to call
+// more than once.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+// Watch polls the subtree at rootDN every interval and reports objects below
+// it that were created, modified or deleted. Each poll only checks the
+// revisions of rootDN and its subtree, through config and schema; the subtree
+// is enumerated again only when one of them has moved, or when opts asks for a
+// periodic resync. A nil opts uses DefaultWatchOptions.
+func Watch(config ConfigAPI, schema SchemaAPI, rootDN string, interval time.Duration, opts *WatchOptions) (*Watcher, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("watch interval must be positive, got %s", interval)
+	}
+	if opts == nil {
+		opts = &DefaultWatchOptions
+	}
+
+	w := &Watcher{
+		config: config,
+		schema: schema,
+		rootDN: rootDN,
+		resync: opts.ResyncPolls,
+		events: make(chan ChangeEvent),
+		errors: make(chan error),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	w.Events = w.events
+	w.Errors = w.errors
+
+	revision, err := w.subtreeRevision()
+	if err != nil {
+		return nil, err
+	}
+	known, err := w.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	go w.run(interval, revision, known)
+	return w, nil
+}
+
+func (w *Watcher) run(interval time.Duration, revision int64, known map[string]ConfigObject) {
+	defer close(w.done)
+	defer close(w.errors)
+	defer close(w.events)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	polls := 0
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+
+		polls++
+		current, err := w.subtreeRevision()
+		if err == nil && current == revision && (w.resync <= 0 || polls%w.resync != 0) {
+			continue
+		}
+
+		var latest map[string]ConfigObject
+		if err == nil {
+			latest, err = w.snapshot()
+		}
+		if err != nil {
+			select {
+			case w.errors <- err:
+				continue
+			case <-w.stop:
+				return
+			}
+		}
+
+		for _, event := range diffSnapshots(known, latest) {
+			select {
+			case w.events <- event:
+			case <-w.stop:
+				return
+			}
+		}
+		revision, known = current, latest
+	}
+}
+
+// subtreeRevision combines the revision of the watched DN with the highest
+// revision below it, so that a change to either is noticed.
+func (w *Watcher) subtreeRevision() (int64, error) {
+	own, err := w.config.GetRevision(w.rootDN)
+	if err != nil {
+		return 0, err
+	}
+	highest, err := w.schema.HighestRevision(w.rootDN)
+	if err != nil {
+		return 0, err
+	}
+	if own > highest {
+		return own, nil
+	}
+	return highest, nil
+}
+
+// snapshot returns every object below the watched DN, keyed by GUID.
+func (w *Watcher) snapshot() (map[string]ConfigObject, error) {
+	objects, err := w.config.Enumerate(w.rootDN, true, "")
+	if err != nil {
+		return nil, err
+	}
+	rv := make(map[string]ConfigObject, len(objects))
+	for _, obj := range objects {
+		rv[strings.ToLower(obj.GUID)] = obj
+	}
+	return rv, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// diffSnapshots returns the changes between two snapshots, sorted by DN.
+func diffSnapshots(before map[string]ConfigObject, after map[string]ConfigObject) []ChangeEvent {
+	rv := make([]ChangeEvent, 0)
+	for guid, obj := range after {
+		old, ok := before[guid]
+		switch {
+		case !ok:
+			rv = append(rv, ChangeEvent{ChangeCreated, obj})
+		case obj.Revision != old.Revision || obj.DN != old.DN:
+			rv = append(rv, ChangeEvent{ChangeModified, obj})
+		}
+	}
+	for guid, obj := range before {
+		if _, ok := after[guid]; !ok {
+			rv = append(rv, ChangeEvent{ChangeDeleted, obj})
+		}
+	}
+	sort.SliceStable(rv, func(i, j int) bool {
+		return strings.ToLower(rv[i].Object.DN) < strings.ToLower(rv[j].Object.DN)
+	})
+	return rv
+}
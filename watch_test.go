@@ -0,0 +1,101 @@
+package venafi_test
+
+import (
+	"testing"
+	"time"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/config"
+)
+
+// nextEvent waits for the next event from w, failing the test on a poll error
+// or if nothing arrives in time.
+func nextEvent(t *testing.T, w *venafi.Watcher) venafi.ChangeEvent {
+	t.Helper()
+	select {
+	case event := <-w.Events:
+		return event
+	case err := <-w.Errors:
+		t.Fatalf("watch error: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a change event")
+	}
+	return venafi.ChangeEvent{}
+}
+
+func TestWatch(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, nil)
+
+	w, err := venafi.Watch(v.Config, v.Schema, `\VED\Policy\Teams`, 10*time.Millisecond, nil)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := v.Config.Create(`\VED\Policy\Teams\Web`, config.ClassPolicy, nil); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if event := nextEvent(t, w); event.Type != venafi.ChangeCreated || event.Object.DN != `\VED\Policy\Teams\Web` {
+		t.Errorf("got %s %s, want created Web", event.Type, event.Object.DN)
+	}
+
+	if err := v.Config.Write(`\VED\Policy\Teams\Web`, map[string][]string{"Description": {"Web team"}}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if event := nextEvent(t, w); event.Type != venafi.ChangeModified || event.Object.DN != `\VED\Policy\Teams\Web` {
+		t.Errorf("got %s %s, want modified Web", event.Type, event.Object.DN)
+	}
+
+	// The deletion leaves every remaining revision alone, so only the default
+	// resync picks it up.
+	if err := v.Config.Delete(`\VED\Policy\Teams\Web`, false); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if event := nextEvent(t, w); event.Type != venafi.ChangeDeleted || event.Object.DN != `\VED\Policy\Teams\Web` {
+		t.Errorf("got %s %s, want deleted Web", event.Type, event.Object.DN)
+	}
+
+	w.Stop()
+	if _, ok := <-w.Events; ok {
+		t.Error("Events is still open after Stop")
+	}
+	if _, ok := <-w.Errors; ok {
+		t.Error("Errors is still open after Stop")
+	}
+}
+
+func TestWatchRejectsBadInterval(t *testing.T) {
+	v, _ := newTestClient(t, nil)
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := venafi.Watch(v.Config, v.Schema, `\VED\Policy`, interval, nil); err == nil {
+			t.Errorf("Watch with interval %s succeeded", interval)
+		}
+	}
+}
+
+func TestWatchOnlyEnumeratesOnChange(t *testing.T) {
+	v, srv, transport := newCountingClient(t)
+	srv.AddObject(`\VED\Policy\Teams`, config.ClassPolicy, nil)
+
+	w, err := venafi.Watch(v.Config, v.Schema, `\VED\Policy\Teams`, 5*time.Millisecond, &venafi.WatchOptions{})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	w.Stop()
+	if n := transport.count("/vedsdk/Config/Enumerate"); n != 1 {
+		t.Errorf("idle watch enumerated %d times, want only the initial snapshot", n)
+	}
+
+	w, err = venafi.Watch(v.Config, v.Schema, `\VED\Policy\Teams`, 5*time.Millisecond, &venafi.WatchOptions{ResyncPolls: 2})
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	w.Stop()
+	if n := transport.count("/vedsdk/Config/Enumerate"); n < 3 {
+		t.Errorf("watch with resyncs enumerated %d times in total, want periodic resyncs", n)
+	}
+}
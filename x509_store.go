@@ -1,9 +1,12 @@
 package venafi
 
 import (
+	"bytes"
+	"crypto"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 
@@ -38,7 +41,7 @@ func (s *X509StoreService) doRequestWithBody(method string, path string, body in
 	}
 
 	if resultOutput.Result != secret_store.Success {
-		return nil, &X509StoreServiceError{Result: resultOutput.Result}
+		return nil, newX509StoreError(res, resultOutput.Result)
 	}
 
 	res.Body = save
@@ -53,7 +56,7 @@ func (s *X509StoreService) doRequestWithBody(method string, path string, body in
 	return res, nil
 }
 
-func (s *X509StoreService) Add(cert *x509.Certificate, ownerDN string, protectionKey string) (int, error) {
+func (s *X509StoreService) Add(cert *x509.Certificate, ownerDN string, protectionKey secret_store.ProtectionKey) (int, error) {
 	certText, err := pem.EncodeCert(cert)
 	if err != nil {
 		return -1, err
@@ -64,7 +67,7 @@ func (s *X509StoreService) Add(cert *x509.Certificate, ownerDN string, protectio
 	type Input struct {
 		CertificateString string
 		OwnerDN           string
-		ProtectionKey     string
+		ProtectionKey     secret_store.ProtectionKey
 	}
 	type Output struct {
 		Result  secret_store.SecretStoreResult
@@ -192,6 +195,164 @@ func (s *X509StoreService) Remove(vaultID int, ownerDN string) error {
 	return nil
 }
 
+// VaultItem is a Secret Store entry of any type. Use the method matching
+// Type to decode Data.
+type VaultItem struct {
+	VaultID int
+	Type    secret_store.VaultType
+	Data    []byte
+}
+
+// RetrieveItem returns the vault entry with the given ID, whatever its type.
+func (s *X509StoreService) RetrieveItem(vaultID int) (*VaultItem, error) {
+	data, vaultType, err := s.client.SecretStore.Retrieve(vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VaultItem{vaultID, vaultType, data}, nil
+}
+
+// RetrievePrivateKey returns the private key stored in a vault entry,
+// decrypting it with password if it is an encrypted PEM block. password is
+// ignored for unencrypted keys, such as those stored by AddPrivateKey.
+func (s *X509StoreService) RetrievePrivateKey(vaultID int, password string) (crypto.Signer, error) {
+	item, err := s.RetrieveItem(vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.PrivateKey(password)
+}
+
+// AddPrivateKey stores key in the vault, encrypted with protectionKey, with
+// the same owners as the certificate in certVaultID, and binds it to that
+// certificate. It returns the new entry's vault ID. If the entry cannot be
+// bound to every owner and the certificate, it is removed again.
+func (s *X509StoreService) AddPrivateKey(key crypto.Signer, certVaultID int, protectionKey secret_store.ProtectionKey) (int, error) {
+	keyText, err := pem.EncodePKCS8Key(key)
+	if err != nil {
+		return -1, err
+	}
+
+	owners, err := s.client.SecretStore.OwnerLookup(certVaultID)
+	if err != nil {
+		return -1, err
+	}
+	ownerDN := ""
+	if len(owners) > 0 {
+		ownerDN = owners[0]
+	}
+
+	vaultID, err := s.client.SecretStore.Add([]byte(keyText), secret_store.VaultTypePrivateKey, ownerDN, protectionKey)
+	if err != nil {
+		return -1, err
+	}
+
+	added := []string{ownerDN}
+	for i := 1; i < len(owners); i++ {
+		if err := s.client.SecretStore.OwnerAdd(vaultID, owners[i]); err != nil {
+			return -1, s.discardEntry(vaultID, added, err)
+		}
+		added = append(added, owners[i])
+	}
+	if err := s.client.SecretStore.Associate(vaultID, certificateVaultIDName, certVaultID); err != nil {
+		return -1, s.discardEntry(vaultID, added, err)
+	}
+
+	return vaultID, nil
+}
+
+// discardEntry removes owners from a vault entry, newest first, so that TPP
+// deletes it, and returns cause. If the entry cannot be fully removed, cause
+// is wrapped in a *RollbackError.
+func (s *X509StoreService) discardEntry(vaultID int, owners []string, cause error) error {
+	var failures []error
+	for i := len(owners) - 1; i >= 0; i-- {
+		if err := s.client.SecretStore.OwnerDelete(vaultID, owners[i]); err != nil {
+			failures = append(failures, fmt.Errorf("error removing owner %s from vault entry %d: %w", owners[i], vaultID, err))
+		}
+	}
+	if len(failures) > 0 {
+		return &RollbackError{Err: cause, Failures: failures}
+	}
+	return cause
+}
+
+// LookupPrivateKeys returns the vault IDs of the private keys bound to the
+// certificate in certVaultID with AddPrivateKey.
+func (s *X509StoreService) LookupPrivateKeys(certVaultID int) ([]int, error) {
+	return s.client.SecretStore.LookupByAssociation(certificateVaultIDName, certVaultID)
+}
+
+// Certificate decodes a certificate entry.
+func (i *VaultItem) Certificate() (*x509.Certificate, error) {
+	if err := i.expect(secret_store.VaultTypeCertificate); err != nil {
+		return nil, err
+	}
+	if isPEM(i.Data) {
+		return pem.DecodeCertString(string(i.Data))
+	}
+	return x509.ParseCertificate(i.Data)
+}
+
+// PrivateKey decodes a private key entry, which may be PEM or DER. password
+// is used to decrypt an encrypted PEM block.
+func (i *VaultItem) PrivateKey(password string) (crypto.Signer, error) {
+	if err := i.expect(secret_store.VaultTypePrivateKey); err != nil {
+		return nil, err
+	}
+	if isPEM(i.Data) {
+		return pem.DecodePrivateKeyWithPassword(string(i.Data), password)
+	}
+	return pem.ParsePrivateKeyDER(i.Data)
+}
+
+// PKCS12 returns the raw bytes of a PKCS#12 entry.
+func (i *VaultItem) PKCS12() ([]byte, error) {
+	if err := i.expect(secret_store.VaultTypePKCS12); err != nil {
+		return nil, err
+	}
+	return i.Data, nil
+}
+
+// Password returns a password entry as a string.
+func (i *VaultItem) Password() (string, error) {
+	if err := i.expect(secret_store.VaultTypePassword); err != nil {
+		return "", err
+	}
+	return string(i.Data), nil
+}
+
+// Blob returns the raw bytes of a blob entry.
+func (i *VaultItem) Blob() ([]byte, error) {
+	if err := i.expect(secret_store.VaultTypeBlob); err != nil {
+		return nil, err
+	}
+	return i.Data, nil
+}
+
+// expect returns a *VaultTypeError unless the item, archived or not, has the
+// given type.
+func (i *VaultItem) expect(vaultType secret_store.VaultType) error {
+	if i.Type.Live() != vaultType {
+		return &VaultTypeError{VaultID: i.VaultID, Want: vaultType, Got: i.Type}
+	}
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Helper funcs
+///////////////////////////////////////////////////////////////////////////////
+
+// certificateVaultIDName is the association that binds a private key entry to
+// its certificate's entry.
+const certificateVaultIDName = "Certificate Vault Id"
+
+func isPEM(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN"))
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Errors
 ///////////////////////////////////////////////////////////////////////////////
@@ -207,3 +368,15 @@ func (e *X509StoreServiceError) Error() string {
 func (e *X509StoreServiceError) Message() string {
 	return e.Result.String()
 }
+
+// VaultTypeError is returned when a vault entry is decoded as a type it does
+// not hold.
+type VaultTypeError struct {
+	VaultID int
+	Want    secret_store.VaultType
+	Got     secret_store.VaultType
+}
+
+func (e *VaultTypeError) Error() string {
+	return fmt.Sprintf("vault entry %d holds %s, not %s", e.VaultID, e.Got, e.Want)
+}
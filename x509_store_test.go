@@ -0,0 +1,77 @@
+package venafi_test
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+
+	venafi "github.com/tradel/venafi-tpp"
+	"github.com/tradel/venafi-tpp/pkg/const/secret_store"
+	"github.com/tradel/venafi-tpp/venafitest"
+)
+
+func TestAddAndRetrievePrivateKey(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\web01`, "X509 Certificate", nil)
+
+	cert, key := newCertificate(t, "web01.example.com", 30)
+	certID, err := v.X509Store.Add(cert, `\VED\Policy\web01`, "")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	keyID, err := v.X509Store.AddPrivateKey(key, certID, "")
+	if err != nil {
+		t.Fatalf("AddPrivateKey: %v", err)
+	}
+	ids, err := v.X509Store.LookupPrivateKeys(certID)
+	if err != nil || len(ids) != 1 || ids[0] != keyID {
+		t.Fatalf("LookupPrivateKeys = %v, %v, want [%d]", ids, err, keyID)
+	}
+
+	// The key is stored unencrypted, so a password must be ignored.
+	for _, password := range []string{"", "Passw0rd"} {
+		got, err := v.X509Store.RetrievePrivateKey(keyID, password)
+		if err != nil {
+			t.Fatalf("RetrievePrivateKey(%q): %v", password, err)
+		}
+		if !got.(*ecdsa.PrivateKey).Equal(key) {
+			t.Errorf("RetrievePrivateKey(%q) returned a different key", password)
+		}
+	}
+
+	var typeErr *venafi.VaultTypeError
+	if _, err := v.X509Store.RetrievePrivateKey(certID, ""); !errors.As(err, &typeErr) {
+		t.Errorf("RetrievePrivateKey of a certificate: got %v, want *VaultTypeError", err)
+	}
+}
+
+func TestAddPrivateKeyRemovesEntryOnFailure(t *testing.T) {
+	v, srv := newTestClient(t, nil)
+	srv.AddObject(`\VED\Policy\web01`, "X509 Certificate", nil)
+	srv.AddObject(`\VED\Policy\web02`, "X509 Certificate", nil)
+
+	cert, key := newCertificate(t, "web.example.com", 30)
+	certID, err := v.X509Store.Add(cert, `\VED\Policy\web01`, "")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := v.SecretStore.OwnerAdd(certID, `\VED\Policy\web02`); err != nil {
+		t.Fatalf("OwnerAdd: %v", err)
+	}
+
+	srv.Inject("/vedsdk/SecretStore/Associate", venafitest.StoreFault(secret_store.AssociateDataFailed))
+	if _, err := v.X509Store.AddPrivateKey(key, certID, ""); err == nil {
+		t.Fatal("AddPrivateKey succeeded despite the injected fault")
+	}
+
+	for _, owner := range []string{`\VED\Policy\web01`, `\VED\Policy\web02`} {
+		ids, err := v.SecretStore.LookupByOwner(owner, secret_store.VaultTypePrivateKey)
+		if err != nil {
+			t.Fatalf("LookupByOwner: %v", err)
+		}
+		if len(ids) != 0 {
+			t.Errorf("%s still owns private key entries %v", owner, ids)
+		}
+	}
+}